@@ -0,0 +1,232 @@
+package ellipticz
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+)
+
+// secp256k1 is the elliptic curve y² = x³ + 7 over the prime field.
+//
+// NOTE: The arithmetic is implemented with math/big and is NOT constant-time.
+// Do NOT use it with private keys, e.g. signing or key generation, because it leaks the private key through timing.
+// It is intended for the verification of "ES256K" signatures, which uses only the public key.
+//
+//   - ref. SEC 2: Recommended Elliptic Curve Domain Parameters https://www.secg.org/sec2-v2.pdf (Section 2.4.1)
+//   - ref. CBOR Object Signing and Encryption (COSE) and JSON Object Signing and Encryption (JOSE) Registrations for Web Authentication (WebAuthn) Algorithms https://www.rfc-editor.org/rfc/rfc8812#section-3
+type secp256k1 struct {
+	params *elliptic.CurveParams
+}
+
+//nolint:gochecknoglobals
+var (
+	_secp256k1     *secp256k1
+	_secp256k1Once sync.Once
+)
+
+// Secp256k1 returns a elliptic.Curve which implements secp256k1.
+//
+// The returned value is a singleton, so it can be compared with ==.
+func Secp256k1() elliptic.Curve { //nolint:ireturn
+	_secp256k1Once.Do(func() {
+		params := &elliptic.CurveParams{Name: "secp256k1", BitSize: 256} //nolint:mnd
+		params.P, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+		params.N, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+		params.B = big.NewInt(7) //nolint:mnd
+		params.Gx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+		params.Gy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+		_secp256k1 = &secp256k1{params: params}
+	})
+	return _secp256k1
+}
+
+func (c *secp256k1) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+func (c *secp256k1) IsOnCurve(x, y *big.Int) bool {
+	p := c.params.P
+	if x.Sign() < 0 || x.Cmp(p) >= 0 || y.Sign() < 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+
+	// y² = x³ + 7
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, p)
+
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+	x3.Add(x3, c.params.B)
+	x3.Mod(x3, p)
+
+	return y2.Cmp(x3) == 0
+}
+
+func (c *secp256k1) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	return c.toAffine(c.addJacobian(c.toJacobian(x1, y1), c.toJacobian(x2, y2)))
+}
+
+func (c *secp256k1) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	return c.toAffine(c.doubleJacobian(c.toJacobian(x1, y1)))
+}
+
+func (c *secp256k1) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	base := c.toJacobian(x1, y1)
+	r := jacobianPoint{x: new(big.Int), y: new(big.Int), z: new(big.Int)} // point at infinity
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			r = c.doubleJacobian(r)
+			if (b>>bit)&1 == 1 {
+				r = c.addJacobian(r, base)
+			}
+		}
+	}
+	return c.toAffine(r)
+}
+
+func (c *secp256k1) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	return c.ScalarMult(c.params.Gx, c.params.Gy, k)
+}
+
+type jacobianPoint struct {
+	x, y, z *big.Int
+}
+
+func (c *secp256k1) toJacobian(x, y *big.Int) jacobianPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return jacobianPoint{x: new(big.Int), y: new(big.Int), z: new(big.Int)}
+	}
+	return jacobianPoint{x: new(big.Int).Set(x), y: new(big.Int).Set(y), z: big.NewInt(1)}
+}
+
+func (c *secp256k1) toAffine(pt jacobianPoint) (x, y *big.Int) {
+	if pt.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	p := c.params.P
+	zInv := new(big.Int).ModInverse(pt.z, p)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+
+	x = new(big.Int).Mul(pt.x, zInv2)
+	x.Mod(x, p)
+
+	y = new(big.Int).Mul(pt.y, zInv2)
+	y.Mul(y, zInv)
+	y.Mod(y, p)
+
+	return x, y
+}
+
+// doubleJacobian doubles a point in Jacobian coordinates for a curve with a = 0.
+//
+//   - ref. https://hyperelliptic.org/EFD/g1p/auto-shortw-jacobian-0.html#doubling-dbl-2009-l
+func (c *secp256k1) doubleJacobian(pt jacobianPoint) jacobianPoint {
+	if pt.z.Sign() == 0 || pt.y.Sign() == 0 {
+		return jacobianPoint{x: new(big.Int), y: new(big.Int), z: new(big.Int)}
+	}
+	p := c.params.P
+
+	a := new(big.Int).Mul(pt.x, pt.x) // A = X1²
+	a.Mod(a, p)
+	b := new(big.Int).Mul(pt.y, pt.y) // B = Y1²
+	b.Mod(b, p)
+	cc := new(big.Int).Mul(b, b) // C = B²
+	cc.Mod(cc, p)
+
+	d := new(big.Int).Add(pt.x, b) // D = 2*((X1+B)²-A-C)
+	d.Mul(d, d)
+	d.Sub(d, a)
+	d.Sub(d, cc)
+	d.Lsh(d, 1)
+	d.Mod(d, p)
+
+	e := new(big.Int).Lsh(a, 1) // E = 3*A
+	e.Add(e, a)
+	f := new(big.Int).Mul(e, e) // F = E²
+
+	x3 := new(big.Int).Lsh(d, 1) // X3 = F-2*D
+	x3.Sub(f, x3)
+	x3.Mod(x3, p)
+
+	y3 := new(big.Int).Sub(d, x3) // Y3 = E*(D-X3)-8*C
+	y3.Mul(e, y3)
+	y3.Sub(y3, new(big.Int).Lsh(cc, 3)) //nolint:mnd
+	y3.Mod(y3, p)
+
+	z3 := new(big.Int).Mul(pt.y, pt.z) // Z3 = 2*Y1*Z1
+	z3.Lsh(z3, 1)
+	z3.Mod(z3, p)
+
+	return jacobianPoint{x: x3, y: y3, z: z3}
+}
+
+// addJacobian adds two points in Jacobian coordinates.
+//
+//   - ref. https://hyperelliptic.org/EFD/g1p/auto-shortw-jacobian-0.html#addition-add-2007-bl
+func (c *secp256k1) addJacobian(p1, p2 jacobianPoint) jacobianPoint {
+	if p1.z.Sign() == 0 {
+		return p2
+	}
+	if p2.z.Sign() == 0 {
+		return p1
+	}
+	p := c.params.P
+
+	z1z1 := new(big.Int).Mul(p1.z, p1.z) // Z1Z1 = Z1²
+	z1z1.Mod(z1z1, p)
+	z2z2 := new(big.Int).Mul(p2.z, p2.z) // Z2Z2 = Z2²
+	z2z2.Mod(z2z2, p)
+
+	u1 := new(big.Int).Mul(p1.x, z2z2) // U1 = X1*Z2Z2
+	u1.Mod(u1, p)
+	u2 := new(big.Int).Mul(p2.x, z1z1) // U2 = X2*Z1Z1
+	u2.Mod(u2, p)
+
+	s1 := new(big.Int).Mul(p1.y, p2.z) // S1 = Y1*Z2*Z2Z2
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, p)
+	s2 := new(big.Int).Mul(p2.y, p1.z) // S2 = Y2*Z1*Z1Z1
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, p)
+
+	h := new(big.Int).Sub(u2, u1) // H = U2-U1
+	h.Mod(h, p)
+	r := new(big.Int).Sub(s2, s1) // r = 2*(S2-S1)
+	r.Mod(r, p)
+
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return c.doubleJacobian(p1)
+		}
+		return jacobianPoint{x: new(big.Int), y: new(big.Int), z: new(big.Int)}
+	}
+	r.Lsh(r, 1)
+
+	i := new(big.Int).Lsh(h, 1) // I = (2*H)²
+	i.Mul(i, i)
+	i.Mod(i, p)
+	j := new(big.Int).Mul(h, i) // J = H*I
+	j.Mod(j, p)
+	v := new(big.Int).Mul(u1, i) // V = U1*I
+	v.Mod(v, p)
+
+	x3 := new(big.Int).Mul(r, r) // X3 = r²-J-2*V
+	x3.Sub(x3, j)
+	x3.Sub(x3, new(big.Int).Lsh(v, 1))
+	x3.Mod(x3, p)
+
+	y3 := new(big.Int).Sub(v, x3) // Y3 = r*(V-X3)-2*S1*J
+	y3.Mul(r, y3)
+	s1j := new(big.Int).Mul(s1, j)
+	y3.Sub(y3, s1j.Lsh(s1j, 1))
+	y3.Mod(y3, p)
+
+	z3 := new(big.Int).Add(p1.z, p2.z) // Z3 = ((Z1+Z2)²-Z1Z1-Z2Z2)*H
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	z3.Sub(z3, z2z2)
+	z3.Mul(z3, h)
+	z3.Mod(z3, p)
+
+	return jacobianPoint{x: x3, y: y3, z: z3}
+}
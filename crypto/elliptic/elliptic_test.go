@@ -0,0 +1,90 @@
+package ellipticz_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	ellipticz "github.com/kunitsucom/util.go/crypto/elliptic"
+)
+
+func TestSecp256k1(t *testing.T) {
+	t.Parallel()
+
+	curve := ellipticz.Secp256k1()
+
+	t.Run("success(singleton)", func(t *testing.T) {
+		t.Parallel()
+		if curve != ellipticz.Secp256k1() {
+			t.Errorf("❌: ellipticz.Secp256k1() != ellipticz.Secp256k1()")
+		}
+		if expect, actual := "secp256k1", curve.Params().Name; expect != actual {
+			t.Errorf("❌: Params().Name: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+
+	t.Run("success(IsOnCurve)", func(t *testing.T) {
+		t.Parallel()
+		if !curve.IsOnCurve(curve.Params().Gx, curve.Params().Gy) {
+			t.Errorf("❌: IsOnCurve(G): false")
+		}
+		if curve.IsOnCurve(curve.Params().Gx, big.NewInt(1)) {
+			t.Errorf("❌: IsOnCurve(Gx, 1): true")
+		}
+	})
+
+	t.Run("success(2G)", func(t *testing.T) {
+		t.Parallel()
+		expectX, _ := new(big.Int).SetString("C6047F9441ED7D6D3045406E95C07CD85C778E4B8CEF3CA7ABAC09B95C709EE5", 16)
+		expectY, _ := new(big.Int).SetString("1AE168FEA63DC339A3C58419466CEAEEF7F632653266D0E1236431A950CFE52A", 16)
+
+		x, y := curve.ScalarBaseMult([]byte{2})
+		if x.Cmp(expectX) != 0 || y.Cmp(expectY) != 0 {
+			t.Errorf("❌: ScalarBaseMult(2): (%X, %X)", x, y)
+		}
+		x, y = curve.Double(curve.Params().Gx, curve.Params().Gy)
+		if x.Cmp(expectX) != 0 || y.Cmp(expectY) != 0 {
+			t.Errorf("❌: Double(G): (%X, %X)", x, y)
+		}
+		x, y = curve.Add(curve.Params().Gx, curve.Params().Gy, curve.Params().Gx, curve.Params().Gy)
+		if x.Cmp(expectX) != 0 || y.Cmp(expectY) != 0 {
+			t.Errorf("❌: Add(G, G): (%X, %X)", x, y)
+		}
+	})
+
+	t.Run("success(nG=O)", func(t *testing.T) {
+		t.Parallel()
+		x, y := curve.ScalarBaseMult(curve.Params().N.Bytes())
+		if x.Sign() != 0 || y.Sign() != 0 {
+			t.Errorf("❌: ScalarBaseMult(N): (%X, %X)", x, y)
+		}
+		x, y = curve.Add(x, y, curve.Params().Gx, curve.Params().Gy)
+		if x.Cmp(curve.Params().Gx) != 0 || y.Cmp(curve.Params().Gy) != 0 {
+			t.Errorf("❌: Add(O, G): (%X, %X)", x, y)
+		}
+	})
+
+	t.Run("success(ecdsa)", func(t *testing.T) {
+		t.Parallel()
+		priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatalf("❌: ecdsa.GenerateKey: err != nil: %v", err)
+		}
+		if !curve.IsOnCurve(priv.X, priv.Y) {
+			t.Fatalf("❌: IsOnCurve(pub): false")
+		}
+		hash := sha256.Sum256([]byte("test"))
+		r, s, err := ecdsa.Sign(rand.Reader, priv, hash[:])
+		if err != nil {
+			t.Fatalf("❌: ecdsa.Sign: err != nil: %v", err)
+		}
+		if !ecdsa.Verify(&priv.PublicKey, hash[:], r, s) {
+			t.Errorf("❌: ecdsa.Verify: false")
+		}
+		if ecdsa.Verify(&priv.PublicKey, hash[1:], r, s) {
+			t.Errorf("❌: ecdsa.Verify: true")
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package x509z
 
 import (
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -14,6 +16,38 @@ var (
 	ErrKeyTypeMismatch  = errors.New("crypto/x509z: key type mismatch")
 )
 
+// ParsePKCS8PrivateKeyPEM parses a PEM encoded PKCS #8 private key.
+// It returns a *rsa.PrivateKey, a *ecdsa.PrivateKey, a ed25519.PrivateKey or a *ecdh.PrivateKey.
+func ParsePKCS8PrivateKeyPEM(pemBytes []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, ErrInvalidPEMFormat
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParsePKCS8PrivateKey: %w", err)
+	}
+
+	return key, nil
+}
+
+// ParsePKIXPublicKeyPEM parses a PEM encoded PKIX public key.
+// It returns a *rsa.PublicKey, a *ecdsa.PublicKey, a ed25519.PublicKey or a *ecdh.PublicKey.
+func ParsePKIXPublicKeyPEM(pemBytes []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, ErrInvalidPEMFormat
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParsePKIXPublicKey: %w", err)
+	}
+
+	return key, nil
+}
+
 func ParseRSAPrivateKeyPEM(pemBytes []byte) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode(pemBytes)
 	if block == nil {
@@ -89,3 +123,41 @@ func ParseECDSAPublicKeyPEM(pemBytes []byte) (*ecdsa.PublicKey, error) {
 
 	return ecdsaPublicKey, nil
 }
+
+func ParseEd25519PrivateKeyPEM(pemBytes []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, ErrInvalidPEMFormat
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParsePKCS8PrivateKey: %w", err)
+	}
+
+	ed25519PrivateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expect=%T actual=%T: %w", ed25519PrivateKey, key, ErrKeyTypeMismatch)
+	}
+
+	return ed25519PrivateKey, nil
+}
+
+func ParseEd25519PublicKeyPEM(pemBytes []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, ErrInvalidPEMFormat
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParsePKIXPublicKey: %w", err)
+	}
+
+	ed25519PublicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("expect=%T actual=%T: %w", ed25519PublicKey, key, ErrKeyTypeMismatch)
+	}
+
+	return ed25519PublicKey, nil
+}
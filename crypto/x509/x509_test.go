@@ -1,6 +1,9 @@
 package x509z_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"errors"
 	"strings"
 	"testing"
@@ -9,6 +12,92 @@ import (
 	testingz "github.com/kunitsucom/util.go/testing"
 )
 
+func TestParsePKCS8PrivateKeyPEM(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		for _, pemString := range []string{
+			testingz.TestRSAPrivateKey2048BitPEM,
+			testingz.TestECDSAPrivateKey256BitPEM,
+			testingz.TestEd25519PrivateKeyPEM,
+		} {
+			key, err := x509z.ParsePKCS8PrivateKeyPEM([]byte(pemString))
+			if err != nil {
+				t.Errorf("❌: err != nil: %v", err)
+			}
+			switch key.(type) {
+			case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+			default:
+				t.Errorf("❌: unexpected key type: %T", key)
+			}
+		}
+	})
+
+	t.Run("failure(ErrInvalidPEMFormat)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParsePKCS8PrivateKeyPEM([]byte("Invalid"))
+		if !errors.Is(err, x509z.ErrInvalidPEMFormat) {
+			t.Errorf("❌: err != x509z.ErrInvalidPEMFormat: %v", err)
+		}
+	})
+
+	t.Run("failure(x509.ParsePKCS8PrivateKey)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParsePKCS8PrivateKeyPEM([]byte(testingz.TestRSAPrivateKeyInvalidPEM))
+		if err == nil {
+			t.Errorf("❌: err == nil: %v", err)
+		}
+		const expect = "asn1: syntax error: data truncated"
+		if err != nil && !strings.Contains(err.Error(), expect) {
+			t.Errorf("❌: err != expect(%s): %v", expect, err)
+		}
+	})
+}
+
+func TestParsePKIXPublicKeyPEM(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		for _, pemString := range []string{
+			testingz.TestRSAPublicKey2048BitPEM,
+			testingz.TestECDSAPublicKey256BitPEM,
+			testingz.TestEd25519PublicKeyPEM,
+		} {
+			key, err := x509z.ParsePKIXPublicKeyPEM([]byte(pemString))
+			if err != nil {
+				t.Errorf("❌: err != nil: %v", err)
+			}
+			switch key.(type) {
+			case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+			default:
+				t.Errorf("❌: unexpected key type: %T", key)
+			}
+		}
+	})
+
+	t.Run("failure(ErrInvalidPEMFormat)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParsePKIXPublicKeyPEM([]byte("Invalid"))
+		if !errors.Is(err, x509z.ErrInvalidPEMFormat) {
+			t.Errorf("❌: err != x509z.ErrInvalidPEMFormat: %v", err)
+		}
+	})
+
+	t.Run("failure(x509.ParsePKIXPublicKey)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParsePKIXPublicKeyPEM([]byte(testingz.TestRSAPrivateKeyInvalidPEM))
+		if err == nil {
+			t.Errorf("❌: err == nil: %v", err)
+		}
+		const expect = "asn1: syntax error: data truncated"
+		if err != nil && !strings.Contains(err.Error(), expect) {
+			t.Errorf("❌: err != expect(%s): %v", expect, err)
+		}
+	})
+}
+
 func TestParseRSAPrivateKeyPEM(t *testing.T) {
 	t.Parallel()
 
@@ -168,3 +257,83 @@ func TestParseECDSAPublicKeyPEM(t *testing.T) {
 		}
 	})
 }
+
+func TestParseEd25519PrivateKeyPEM(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParseEd25519PrivateKeyPEM([]byte(testingz.TestEd25519PrivateKeyPEM))
+		if err != nil {
+			t.Errorf("❌: err != nil: %v", err)
+		}
+	})
+
+	t.Run("failure(ErrInvalidPEMFormat)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParseEd25519PrivateKeyPEM([]byte("Invalid"))
+		if !errors.Is(err, x509z.ErrInvalidPEMFormat) {
+			t.Errorf("❌: err != x509z.ErrInvalidPEMFormat: %v", err)
+		}
+	})
+
+	t.Run("failure(x509.ParsePKCS8PrivateKey)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParseEd25519PrivateKeyPEM([]byte(testingz.TestRSAPrivateKeyInvalidPEM))
+		if err == nil {
+			t.Errorf("❌: err == nil: %v", err)
+		}
+		const expect = "asn1: syntax error: data truncated"
+		if err != nil && !strings.Contains(err.Error(), expect) {
+			t.Errorf("❌: err != expect(%s): %v", expect, err)
+		}
+	})
+
+	t.Run("failure(x509z.ErrPublicKeyTypeMismatch)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParseEd25519PrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM))
+		if !errors.Is(err, x509z.ErrKeyTypeMismatch) {
+			t.Errorf("❌: err != x509z.ErrPublicKeyTypeMismatch: %v", err)
+		}
+	})
+}
+
+func TestParseEd25519PublicKeyPEM(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestEd25519PublicKeyPEM))
+		if err != nil {
+			t.Errorf("❌: err != nil: %v", err)
+		}
+	})
+
+	t.Run("failure(ErrInvalidPEMFormat)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParseEd25519PublicKeyPEM([]byte("Invalid"))
+		if !errors.Is(err, x509z.ErrInvalidPEMFormat) {
+			t.Errorf("❌: err != x509z.ErrInvalidPEMFormat: %v", err)
+		}
+	})
+
+	t.Run("failure(x509.ParsePKIXPublicKey)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestRSAPrivateKeyInvalidPEM))
+		if err == nil {
+			t.Errorf("❌: err == nil: %v", err)
+		}
+		const expect = "asn1: syntax error: data truncated"
+		if err != nil && !strings.Contains(err.Error(), expect) {
+			t.Errorf("❌: err != expect(%s): %v", expect, err)
+		}
+	})
+
+	t.Run("failure(x509z.ErrPublicKeyTypeMismatch)", func(t *testing.T) {
+		t.Parallel()
+		_, err := x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestECDSAPublicKey256BitPEM))
+		if !errors.Is(err, x509z.ErrKeyTypeMismatch) {
+			t.Errorf("❌: err != x509z.ErrPublicKeyTypeMismatch: %v", err)
+		}
+	})
+}
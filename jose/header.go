@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/kunitsucom/util.go/jose/jwk"
+	slicez "github.com/kunitsucom/util.go/slices"
 )
 
 var (
@@ -111,7 +113,7 @@ type Header struct {
 	// by implementations when these algorithms are used.
 	//
 	//   - ref. "epk" (Ephemeral Public Key) Header Parameter - JSON Web Algorithms (JWA) https://www.rfc-editor.org/rfc/rfc7518#section-4.6.1.1
-	EphemeralPublicKey string `json:"epk,omitempty"`
+	EphemeralPublicKey *jwk.JSONWebKey `json:"epk,omitempty"`
 
 	// AgreementPartyUInfo
 	//
@@ -537,6 +539,24 @@ type Header struct {
 	//   - ref. "crit" (Critical) Header Parameter - JSON Web Encryption (JWE) https://www.rfc-editor.org/rfc/rfc7516#section-4.1.13
 	Critical []string `json:"crit,omitempty"`
 
+	// Base64URLEncodePayload
+	//
+	// # JSON Web Signature (JWS) Unencoded Payload Option
+	//
+	// The Header Parameter "b64" is defined with the meaning:
+	//
+	//	true:  The payload is represented as BASE64URL(JWS Payload).
+	//	false: The payload is represented as the JWS Payload itself.
+	//
+	// When the "b64" value is "false", the signing input is
+	// ASCII(BASE64URL(UTF8(JWS Protected Header)) || '.') || JWS Payload.
+	// When "b64" is absent, its value is treated as "true".  The "b64"
+	// Header Parameter MUST be integrity protected and MUST be included
+	// in the "crit" Header Parameter value.
+	//
+	//   - ref. The "b64" Header Parameter - JSON Web Signature (JWS) Unencoded Payload Option https://www.rfc-editor.org/rfc/rfc7797#section-3
+	Base64URLEncodePayload *bool `json:"b64,omitempty"`
+
 	// PrivateHeaderParameters
 	//
 	// # JSON Web Signature (JWS)
@@ -645,6 +665,18 @@ func WithCritical(crit []string) HeaderParameter {
 	}
 }
 
+// WithBase64URLEncodePayload sets "b64" header parameter.
+// When b64 is false, "b64" is also appended to "crit" header parameter.
+func WithBase64URLEncodePayload(b64 bool) HeaderParameter {
+	return func(h *Header) {
+		h.Base64URLEncodePayload = &b64
+		if !b64 && !slicez.Contains(h.Critical, "b64") {
+			// NOTE: clip to avoid writing into the backing array of the slice passed to WithCritical.
+			h.Critical = append(slices.Clip(h.Critical), "b64")
+		}
+	}
+}
+
 func WithPrivateHeaderParameter(name string, value any) HeaderParameter {
 	return func(h *Header) {
 		h.PrivateHeaderParameters[name] = value
@@ -250,6 +250,25 @@ func TestHeader_Decode(t *testing.T) {
 	})
 }
 
+func TestWithBase64URLEncodePayload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(crit)", func(t *testing.T) {
+		t.Parallel()
+		crit := make([]string, 1, 2)
+		crit[0] = "testKey"
+		h1 := NewHeader(jwa.ES256, WithCritical(crit), WithBase64URLEncodePayload(false))
+		h2 := NewHeader(jwa.ES256, WithCritical(crit), WithPrivateHeaderParameter("testKey2", "testValue"))
+		h2.Critical = append(h2.Critical, "testKey2")
+		if expect, actual := []string{"testKey", "b64"}, h1.Critical; !reflect.DeepEqual(expect, actual) {
+			t.Errorf("❌: WithBase64URLEncodePayload: expect != actual: %v != %v", expect, actual)
+		}
+		if expect, actual := []string{"testKey"}, crit; !reflect.DeepEqual(expect, actual) {
+			t.Errorf("❌: WithBase64URLEncodePayload: crit is modified: %v", actual)
+		}
+	})
+}
+
 func TestHeader_GetPrivateHeaderParameter(t *testing.T) {
 	t.Parallel()
 
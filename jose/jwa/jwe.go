@@ -0,0 +1,122 @@
+package jwa
+
+// JWE Key Management Algorithms
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-4.1
+//
+// 4.1.  "alg" (Algorithm) Header Parameter Values for JWE
+//
+//	The table below is the set of "alg" (algorithm) Header Parameter
+//	values that are defined by this specification for use with JWE.
+//	These algorithms are used to encrypt the CEK, producing the JWE
+//	Encrypted Key, or to use key agreement to agree upon the CEK.
+//
+//	+--------------------+--------------------+--------+----------------+
+//	| "alg" Param Value  | Key Management     | More   | Implementation |
+//	|                    | Algorithm          | Header | Requirements   |
+//	|                    |                    | Params |                |
+//	+--------------------+--------------------+--------+----------------+
+//	| RSA-OAEP           | RSAES OAEP using   | (none) | Recommended+   |
+//	|                    | default parameters |        |                |
+//	| RSA-OAEP-256       | RSAES OAEP using   | (none) | Optional       |
+//	|                    | SHA-256 and MGF1   |        |                |
+//	|                    | with SHA-256       |        |                |
+//	| A128KW             | AES Key Wrap with  | (none) | Recommended    |
+//	|                    | default initial    |        |                |
+//	|                    | value using        |        |                |
+//	|                    | 128-bit key        |        |                |
+//	| A192KW             | AES Key Wrap with  | (none) | Optional       |
+//	|                    | default initial    |        |                |
+//	|                    | value using        |        |                |
+//	|                    | 192-bit key        |        |                |
+//	| A256KW             | AES Key Wrap with  | (none) | Recommended    |
+//	|                    | default initial    |        |                |
+//	|                    | value using        |        |                |
+//	|                    | 256-bit key        |        |                |
+//	| dir                | Direct use of a    | (none) | Recommended    |
+//	|                    | shared symmetric   |        |                |
+//	|                    | key as the CEK     |        |                |
+//	| ECDH-ES            | Elliptic Curve     | "epk", | Recommended+   |
+//	|                    | Diffie-Hellman     | "apu", |                |
+//	|                    | Ephemeral Static   | "apv"  |                |
+//	|                    | key agreement      |        |                |
+//	|                    | using Concat KDF   |        |                |
+//	| ECDH-ES+A128KW     | ECDH-ES using      | "epk", | Recommended    |
+//	|                    | Concat KDF and CEK | "apu", |                |
+//	|                    | wrapped with       | "apv"  |                |
+//	|                    | "A128KW"           |        |                |
+//	| ECDH-ES+A192KW     | ECDH-ES using      | "epk", | Optional       |
+//	|                    | Concat KDF and CEK | "apu", |                |
+//	|                    | wrapped with       | "apv"  |                |
+//	|                    | "A192KW"           |        |                |
+//	| ECDH-ES+A256KW     | ECDH-ES using      | "epk", | Recommended    |
+//	|                    | Concat KDF and CEK | "apu", |                |
+//	|                    | wrapped with       | "apv"  |                |
+//	|                    | "A256KW"           |        |                |
+//	+--------------------+--------------------+--------+----------------+
+const (
+	RSAOAEP      = "RSA-OAEP"
+	RSAOAEP256   = "RSA-OAEP-256"
+	A128KW       = "A128KW"
+	A192KW       = "A192KW"
+	A256KW       = "A256KW"
+	Dir          = "dir"
+	ECDHES       = "ECDH-ES"
+	ECDHESA128KW = "ECDH-ES+A128KW"
+	ECDHESA192KW = "ECDH-ES+A192KW"
+	ECDHESA256KW = "ECDH-ES+A256KW"
+)
+
+// JWE Content Encryption Algorithms
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-5.1
+//
+// 5.1.  "enc" (Encryption Algorithm) Header Parameter Values for JWE
+//
+//	The table below is the set of "enc" (encryption algorithm) Header
+//	Parameter values that are defined by this specification for use with
+//	JWE.
+//
+//	+---------------+----------------------------------+----------------+
+//	| "enc" Param   | Content Encryption Algorithm     | Implementation |
+//	| Value         |                                  | Requirements   |
+//	+---------------+----------------------------------+----------------+
+//	| A128CBC-HS256 | AES_128_CBC_HMAC_SHA_256         | Required       |
+//	|               | authenticated encryption         |                |
+//	|               | algorithm, as defined in Section |                |
+//	|               | 5.2.3                            |                |
+//	| A192CBC-HS384 | AES_192_CBC_HMAC_SHA_384         | Optional       |
+//	|               | authenticated encryption         |                |
+//	|               | algorithm, as defined in Section |                |
+//	|               | 5.2.4                            |                |
+//	| A256CBC-HS512 | AES_256_CBC_HMAC_SHA_512         | Required       |
+//	|               | authenticated encryption         |                |
+//	|               | algorithm, as defined in Section |                |
+//	|               | 5.2.5                            |                |
+//	| A128GCM       | AES GCM using 128-bit key        | Recommended    |
+//	| A192GCM       | AES GCM using 192-bit key        | Optional       |
+//	| A256GCM       | AES GCM using 256-bit key        | Recommended    |
+//	+---------------+----------------------------------+----------------+
+const (
+	A128CBCHS256 = "A128CBC-HS256"
+	A192CBCHS384 = "A192CBC-HS384"
+	A256CBCHS512 = "A256CBC-HS512"
+	A128GCM      = "A128GCM"
+	A192GCM      = "A192GCM"
+	A256GCM      = "A256GCM"
+)
+
+// JWE Compression Algorithms
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7516#section-4.1.3
+//
+// 4.1.3.  "zip" (Compression Algorithm) Header Parameter
+//
+//	The "zip" (compression algorithm) applied to the plaintext before
+//	encryption, if any.  The "zip" value defined by this specification
+//	is:
+//
+//	o  "DEF" - Compression with the DEFLATE [RFC1951] algorithm
+const (
+	DEF = "DEF"
+)
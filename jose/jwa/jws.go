@@ -2,12 +2,15 @@ package jwa
 
 import (
 	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/sha512"
 	"errors"
 	"fmt"
 	"sync"
+
+	ellipticz "github.com/kunitsucom/util.go/crypto/elliptic"
 )
 
 // JWSAlgorithm
@@ -54,6 +57,13 @@ import (
 //	signature and MAC "alg" (algorithm) values defined in this
 //	specification with the equivalent identifiers used by other standards
 //	and software packages.
+//
+// In addition, the following "alg" values defined by other specifications are available:
+//
+//   - EdDSA:  Edwards-curve Digital Signature Algorithm (Ed25519 only)
+//     ref. https://www.rfc-editor.org/rfc/rfc8037#section-3.1
+//   - ES256K: ECDSA using secp256k1 curve and SHA-256 (must be registered by RegisterES256K)
+//     ref. https://www.rfc-editor.org/rfc/rfc8812#section-3.2
 type JWSAlgorithm interface {
 	Sign(key any, signingInput string) (signatureEncoded string, err error)
 	Verify(key any, signingInput string, signatureEncoded string) (err error)
@@ -83,6 +93,9 @@ const (
 	PS384 = "PS384"
 	PS512 = "PS512"
 	None  = "none"
+
+	EdDSA  = "EdDSA"
+	ES256K = "ES256K"
 )
 
 var (
@@ -90,6 +103,7 @@ var (
 	ErrFailedToVerifySignature     = errors.New("jwa: failed to verify signature")
 	ErrAlgorithmNoneIsNotSupported = errors.New("jwa: algorithm 'none' is not supported")
 	ErrNotImplemented              = errors.New("jwa: not implemented")
+	ErrSigningIsNotSupported       = errors.New("jwa: signing is not supported")
 )
 
 //nolint:revive,stylecheck
@@ -107,6 +121,9 @@ type (
 	_PS384 string
 	_PS512 string
 	_None  string
+
+	_EdDSA  string
+	_ES256K string
 )
 
 //nolint:gochecknoglobals
@@ -125,11 +142,14 @@ var (
 		PS384: _PS384(PS384),
 		PS512: _PS512(PS512),
 		None:  _None(None),
+		EdDSA: _EdDSA(EdDSA),
 	}
 	_JWSAlgorithmMapMu sync.RWMutex
 )
 
 func JWS(alg string) JWSAlgorithm { //nolint:cyclop,ireturn
+	_JWSAlgorithmMapMu.RLock()
+	defer _JWSAlgorithmMapMu.RUnlock()
 	if a, ok := _JWSAlgorithmMap[alg]; ok {
 		return a
 	}
@@ -155,8 +175,8 @@ func (alg JWSAlgorithmFunc) Verify(key any, signingInput string, signatureEncode
 }
 
 func RegisterJWSAlgorithm(alg string, jwsAlgorithm JWSAlgorithm) {
-	_JWSAlgorithmMapMu.RLock()
-	defer _JWSAlgorithmMapMu.RUnlock()
+	_JWSAlgorithmMapMu.Lock()
+	defer _JWSAlgorithmMapMu.Unlock()
 	_JWSAlgorithmMap[alg] = jwsAlgorithm
 }
 
@@ -399,6 +419,59 @@ func (a _PS512) Verify(key any, signingInput string, signatureEncoded string) (e
 	return nil
 }
 
+//
+// EdDSA
+//
+
+// Sign for EdDSA.
+func (a _EdDSA) Sign(key any, signingInput string) (signatureEncoded string, err error) {
+	signatureEncoded, err = signEdDSA(key, signingInput)
+	if err != nil {
+		return "", fmt.Errorf("alg=%s key=%T: %w", a, key, err)
+	}
+	return signatureEncoded, nil
+}
+
+// Verify for EdDSA.
+func (a _EdDSA) Verify(key any, signingInput string, signatureEncoded string) (err error) {
+	if err := verifyEdDSA(key, signingInput, signatureEncoded); err != nil {
+		return fmt.Errorf("alg=%s key=%T: %w", a, key, err)
+	}
+	return nil
+}
+
+//
+// ES256K
+//
+
+// RegisterES256K registers "ES256K" (ECDSA using secp256k1 curve and SHA-256) for verification only.
+//
+// "ES256K" is not registered by default, and Sign always returns ErrSigningIsNotSupported,
+// because the secp256k1 arithmetic in ellipticz is not constant-time and leaks the private key through timing.
+// Verification uses only the public key, so it is safe.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc8812#section-3.2
+func RegisterES256K() {
+	RegisterJWSAlgorithm(ES256K, _ES256K(ES256K))
+}
+
+// Sign for ES256K. It always returns ErrSigningIsNotSupported. See RegisterES256K.
+func (a _ES256K) Sign(key any, _ string) (signatureEncoded string, err error) {
+	return "", fmt.Errorf("alg=%s key=%T: %w", a, key, ErrSigningIsNotSupported)
+}
+
+// Verify for ES256K.
+func (a _ES256K) Verify(key any, signingInput string, signatureEncoded string) (err error) {
+	const keySize = 32
+	if pub, ok := key.(*ecdsa.PublicKey); !ok || pub.Curve != ellipticz.Secp256k1() {
+		return fmt.Errorf("alg=%s key=%T: %w", a, key, ErrInvalidKeyReceived)
+	}
+	if err := verifyES(key, signingInput, signatureEncoded, crypto.SHA256, keySize); err != nil {
+		return fmt.Errorf("alg=%s key=%T: %w", a, key, err)
+	}
+	return nil
+}
+
 //
 // none
 //
@@ -1,10 +1,18 @@
 package jwa_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"testing"
 
+	ellipticz "github.com/kunitsucom/util.go/crypto/elliptic"
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
 	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/must"
+	testingz "github.com/kunitsucom/util.go/testing"
 )
 
 func TestRegisterJWSAlgorithm(t *testing.T) { //nolint:paralleltest
@@ -35,3 +43,43 @@ func TestRegisterJWSAlgorithm(t *testing.T) { //nolint:paralleltest
 		}
 	})
 }
+
+func TestRegisterES256K(t *testing.T) { //nolint:paralleltest
+	const signingInput = "eyJhbGciOiJFUzI1NksifQ.eyJzdWIiOiIxMjM0NTY3ODkwIn0"
+	jwa.RegisterES256K()
+	key := must.One(ecdsa.GenerateKey(ellipticz.Secp256k1(), rand.Reader))
+
+	t.Run("success()", func(t *testing.T) {
+		// NOTE: sign by crypto/ecdsa, because ES256K does not support signing.
+		digest := sha256.Sum256([]byte(signingInput))
+		r, s := must.Two(ecdsa.Sign(rand.Reader, key, digest[:]))
+		const keySize = 32
+		signature := make([]byte, 2*keySize)
+		r.FillBytes(signature[:keySize])
+		s.FillBytes(signature[keySize:])
+		signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
+
+		if err := jwa.JWS(jwa.ES256K).Verify(&key.PublicKey, signingInput, signatureEncoded); err != nil {
+			t.Errorf("❌: err != nil: %v", err)
+		}
+		if err := jwa.JWS(jwa.ES256K).Verify(&key.PublicKey, signingInput+"x", signatureEncoded); !errors.Is(err, jwa.ErrFailedToVerifySignature) {
+			t.Errorf("❌: err != jwa.ErrFailedToVerifySignature: %v", err)
+		}
+	})
+
+	t.Run("failure(jwa.ErrSigningIsNotSupported)", func(t *testing.T) {
+		if _, err := jwa.JWS(jwa.ES256K).Sign(key, signingInput); !errors.Is(err, jwa.ErrSigningIsNotSupported) {
+			t.Errorf("❌: err != jwa.ErrSigningIsNotSupported: %v", err)
+		}
+	})
+
+	t.Run("failure(jwa.ErrInvalidKeyReceived)", func(t *testing.T) {
+		p256 := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+		if err := jwa.JWS(jwa.ES256K).Verify(&p256.PublicKey, signingInput, "signature"); !errors.Is(err, jwa.ErrInvalidKeyReceived) {
+			t.Errorf("❌: err != jwa.ErrInvalidKeyReceived: %v", err)
+		}
+		if err := jwa.JWS(jwa.ES256K).Verify("invalidKey", signingInput, "signature"); !errors.Is(err, jwa.ErrInvalidKeyReceived) {
+			t.Errorf("❌: err != jwa.ErrInvalidKeyReceived: %v", err)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package jwa
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/rsa"
@@ -81,3 +82,15 @@ func signPS(key crypto.PrivateKey, signingInput string, cryptoHash crypto.Hash)
 	}
 	return base64.RawURLEncoding.EncodeToString(rawSignature), nil
 }
+
+func signEdDSA(key crypto.PrivateKey, signingInput string) (signatureEncoded string, err error) {
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", ErrInvalidKeyReceived
+	}
+	if len(priv) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("len(key)=%d: %w", len(priv), ErrInvalidKeyReceived)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(ed25519.Sign(priv, []byte(signingInput))), nil
+}
@@ -2,6 +2,7 @@ package jwa_test
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rsa"
 	"errors"
@@ -480,6 +481,55 @@ var signTestCases = map[string]struct {
 		},
 	},
 	//
+	// EdDSA
+	//
+	fmt.Sprintf("success(%s)", jwa.EdDSA): {
+		alg:          jwa.EdDSA,
+		key:          must.One(x509z.ParseEd25519PrivateKeyPEM([]byte(testingz.TestEd25519PrivateKeyPEM))),
+		signingInput: "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+		sigHandler: func(t *testing.T, signatureEncoded string) { //nolint:thelper
+			// - ref. https://www.rfc-editor.org/rfc/rfc8037#appendix-A.4
+			if actual, expect := signatureEncoded, "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"; actual != expect {
+				t.Errorf("❌: actual != expect: %v", actual)
+			}
+		},
+		errHandler: func(t *testing.T, err error) { //nolint:thelper
+			if err != nil {
+				t.Errorf("❌: err != nil: %v", err)
+			}
+		},
+	},
+	fmt.Sprintf("failure(%s,jwa.ErrInvalidKeyReceived)", jwa.EdDSA): {
+		alg:          jwa.EdDSA,
+		key:          "invalidKey",
+		signingInput: "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+		sigHandler: func(t *testing.T, signatureEncoded string) { //nolint:thelper
+			if actual, expect := signatureEncoded, ""; actual != expect {
+				t.Errorf("❌: actual != expect: %v", actual)
+			}
+		},
+		errHandler: func(t *testing.T, err error) { //nolint:thelper
+			if !errors.Is(err, jwa.ErrInvalidKeyReceived) {
+				t.Errorf("❌: err != jwa.ErrInvalidKeyReceived: %v", err)
+			}
+		},
+	},
+	fmt.Sprintf("failure(%s,len(key))", jwa.EdDSA): {
+		alg:          jwa.EdDSA,
+		key:          ed25519.PrivateKey("invalidKey"),
+		signingInput: "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+		sigHandler: func(t *testing.T, signatureEncoded string) { //nolint:thelper
+			if actual, expect := signatureEncoded, ""; actual != expect {
+				t.Errorf("❌: actual != expect: %v", actual)
+			}
+		},
+		errHandler: func(t *testing.T, err error) { //nolint:thelper
+			if !errors.Is(err, jwa.ErrInvalidKeyReceived) {
+				t.Errorf("❌: err != jwa.ErrInvalidKeyReceived: %v", err)
+			}
+		},
+	},
+	//
 	// none
 	//
 	fmt.Sprintf("failure(%s,jwa.ErrAlgorithmNoneIsNotSupported)", jwa.None): {
@@ -3,6 +3,7 @@ package jwa
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/rsa"
 	"encoding/base64"
@@ -85,3 +86,21 @@ func verifyPS(key crypto.PublicKey, signingInput string, signatureEncoded string
 	}
 	return nil
 }
+
+func verifyEdDSA(key crypto.PublicKey, signingInput string, signatureEncoded string) error {
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return ErrInvalidKeyReceived
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("len(key)=%d: %w", len(pub), ErrInvalidKeyReceived)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signatureEncoded)
+	if err != nil {
+		return fmt.Errorf("base64.RawURLEncoding.DecodeString: %w", err)
+	}
+	if !ed25519.Verify(pub, []byte(signingInput), signature) {
+		return fmt.Errorf("ed25519.Verify: %w", ErrFailedToVerifySignature)
+	}
+	return nil
+}
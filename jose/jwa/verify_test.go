@@ -1,6 +1,7 @@
 package jwa_test
 
 import (
+	"crypto/ed25519"
 	"crypto/rsa"
 	"errors"
 	"fmt"
@@ -420,6 +421,64 @@ var verifyTestCases = map[string]struct {
 		},
 	},
 	//
+	// EdDSA
+	//
+	fmt.Sprintf("success(%s)", jwa.EdDSA): {
+		alg:              jwa.EdDSA,
+		key:              must.One(x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestEd25519PublicKeyPEM))),
+		signingInput:     "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+		signatureEncoded: "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg",
+		errHandler: func(t *testing.T, err error) { //nolint:thelper
+			if err != nil {
+				t.Errorf("❌: err != nil: %v", err)
+			}
+		},
+	},
+	fmt.Sprintf("failure(%s,jwa.ErrInvalidKeyReceived)", jwa.EdDSA): {
+		alg:              jwa.EdDSA,
+		key:              "invalidKey",
+		signingInput:     "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+		signatureEncoded: "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg",
+		errHandler: func(t *testing.T, err error) { //nolint:thelper
+			if !errors.Is(err, jwa.ErrInvalidKeyReceived) {
+				t.Errorf("❌: err != jwa.ErrInvalidKeyReceived: %v", err)
+			}
+		},
+	},
+	fmt.Sprintf("failure(%s,len(key))", jwa.EdDSA): {
+		alg:              jwa.EdDSA,
+		key:              ed25519.PublicKey("invalidKey"),
+		signingInput:     "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+		signatureEncoded: "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg",
+		errHandler: func(t *testing.T, err error) { //nolint:thelper
+			if !errors.Is(err, jwa.ErrInvalidKeyReceived) {
+				t.Errorf("❌: err != jwa.ErrInvalidKeyReceived: %v", err)
+			}
+		},
+	},
+	fmt.Sprintf("failure(%s,base64.RawURLEncoding.DecodeString)", jwa.EdDSA): {
+		alg:              jwa.EdDSA,
+		key:              must.One(x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestEd25519PublicKeyPEM))),
+		signingInput:     "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+		signatureEncoded: "inv@lidBase64",
+		errHandler: func(t *testing.T, err error) { //nolint:thelper
+			if expect := "illegal base64 data at input byte 3"; err == nil || !strings.Contains(err.Error(), expect) {
+				t.Errorf("❌: err != expect(%s): %v", expect, err)
+			}
+		},
+	},
+	fmt.Sprintf("failure(%s,jwa.ErrFailedToVerifySignature)", jwa.EdDSA): {
+		alg:              jwa.EdDSA,
+		key:              must.One(x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestEd25519PublicKeyPEM))),
+		signingInput:     "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+		signatureEncoded: "failure",
+		errHandler: func(t *testing.T, err error) { //nolint:thelper
+			if !errors.Is(err, jwa.ErrFailedToVerifySignature) {
+				t.Errorf("❌: err != jwa.ErrFailedToVerifySignature: %v", err)
+			}
+		},
+	},
+	//
 	// none
 	//
 	fmt.Sprintf("failure(%s,jwa.ErrAlgorithmNoneIsNotSupported)", jwa.None): {
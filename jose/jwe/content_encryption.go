@@ -0,0 +1,209 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/kunitsucom/util.go/jose/jwa"
+)
+
+// contentEncryption is a content encryption algorithm ("enc").
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-5
+type contentEncryption interface {
+	// keySize returns the CEK size in bytes.
+	keySize() int
+	encrypt(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error)
+	decrypt(cek, iv, ciphertext, tag, aad []byte) (plaintext []byte, err error)
+}
+
+func getContentEncryption(enc string) (contentEncryption, error) { //nolint:ireturn
+	switch enc {
+	case jwa.A128CBCHS256:
+		return aesCBCHMAC{size: 32, hashNewFunc: sha256.New}, nil
+	case jwa.A192CBCHS384:
+		return aesCBCHMAC{size: 48, hashNewFunc: sha512.New384}, nil
+	case jwa.A256CBCHS512:
+		return aesCBCHMAC{size: 64, hashNewFunc: sha512.New}, nil
+	case jwa.A128GCM:
+		return aesGCM{size: 16}, nil
+	case jwa.A192GCM:
+		return aesGCM{size: 24}, nil
+	case jwa.A256GCM:
+		return aesGCM{size: 32}, nil
+	}
+
+	return nil, fmt.Errorf("enc=%s: %w", enc, ErrEncryptionAlgorithmIsNotSupported)
+}
+
+//
+// AES_CBC_HMAC_SHA2
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-5.2
+//
+
+type aesCBCHMAC struct {
+	size        int
+	hashNewFunc func() hash.Hash
+}
+
+func (a aesCBCHMAC) keySize() int { return a.size }
+
+func (a aesCBCHMAC) encrypt(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	if len(cek) != a.size {
+		return nil, nil, nil, fmt.Errorf("len(cek)=%d: %w", len(cek), ErrInvalidKeyReceived)
+	}
+	macKey, encKey := cek[:a.size/2], cek[a.size/2:]
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+
+	iv = make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, nil, nil, fmt.Errorf("io.ReadFull: %w", err)
+	}
+
+	// PKCS #7 padding
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext = make([]byte, len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return iv, ciphertext, a.tag(macKey, aad, iv, ciphertext), nil
+}
+
+func (a aesCBCHMAC) decrypt(cek, iv, ciphertext, tag, aad []byte) (plaintext []byte, err error) {
+	if len(cek) != a.size {
+		return nil, fmt.Errorf("len(cek)=%d: %w", len(cek), ErrInvalidKeyReceived)
+	}
+	macKey, encKey := cek[:a.size/2], cek[a.size/2:]
+
+	if !hmac.Equal(tag, a.tag(macKey, aad, iv, ciphertext)) {
+		return nil, fmt.Errorf("hmac.Equal: %w", ErrFailedToDecrypt)
+	}
+
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("len(iv)=%d len(ciphertext)=%d: %w", len(iv), len(ciphertext), ErrFailedToDecrypt)
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+
+	plaintext = make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, fmt.Errorf("padding=%d: %w", padding, ErrFailedToDecrypt)
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, fmt.Errorf("padding=%d: %w", padding, ErrFailedToDecrypt)
+		}
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// tag computes the Authentication Tag.
+//
+//		M = MAC(MAC_KEY, A || IV || E || AL)
+//		T = M[0:T_LEN]
+//
+//	  - ref. https://www.rfc-editor.org/rfc/rfc7518#section-5.2.2.1
+func (a aesCBCHMAC) tag(macKey, aad, iv, ciphertext []byte) []byte {
+	const bitsPerByte, uint64Size = 8, 8
+	al := make([]byte, uint64Size)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*bitsPerByte)
+
+	h := hmac.New(a.hashNewFunc, macKey)
+	h.Write(aad)
+	h.Write(iv)
+	h.Write(ciphertext)
+	h.Write(al)
+	return h.Sum(nil)[:a.size/2]
+}
+
+//
+// AES GCM
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-5.3
+//
+
+type aesGCM struct {
+	size int
+}
+
+func (a aesGCM) keySize() int { return a.size }
+
+func (a aesGCM) aead(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != a.size {
+		return nil, fmt.Errorf("len(cek)=%d: %w", len(cek), ErrInvalidKeyReceived)
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewGCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+func (a aesGCM) encrypt(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	aead, err := a.aead(cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, nil, nil, fmt.Errorf("io.ReadFull: %w", err)
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	tagOffset := len(sealed) - aead.Overhead()
+
+	return iv, sealed[:tagOffset], sealed[tagOffset:], nil
+}
+
+func (a aesGCM) decrypt(cek, iv, ciphertext, tag, aad []byte) (plaintext []byte, err error) {
+	aead, err := a.aead(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+		return nil, fmt.Errorf("len(iv)=%d len(tag)=%d: %w", len(iv), len(tag), ErrFailedToDecrypt)
+	}
+
+	sealed := make([]byte, 0, len(ciphertext)+len(tag))
+	sealed = append(sealed, ciphertext...)
+	sealed = append(sealed, tag...)
+
+	plaintext, err = aead.Open(nil, iv, sealed, aad)
+	if err != nil {
+		return nil, fmt.Errorf("(cipher.AEAD).Open: %v: %w", err, ErrFailedToDecrypt) //nolint:errorlint
+	}
+
+	return plaintext, nil
+}
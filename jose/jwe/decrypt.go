@@ -0,0 +1,232 @@
+package jwe
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	slicez "github.com/kunitsucom/util.go/slices"
+)
+
+type DecryptionKeyOption struct {
+	key        any
+	jsonWebKey *jwk.JSONWebKey
+}
+
+func UseKey(key any) DecryptionKeyOption {
+	return DecryptionKeyOption{
+		key: key,
+	}
+}
+
+// UseRSAKey is a DecryptionKeyOption for "RSA-OAEP" and "RSA-OAEP-256".
+func UseRSAKey(key *rsa.PrivateKey) DecryptionKeyOption {
+	return UseKey(key)
+}
+
+// UseECDSAKey is a DecryptionKeyOption for "ECDH-ES" and "ECDH-ES+A*KW".
+func UseECDSAKey(key *ecdsa.PrivateKey) DecryptionKeyOption {
+	return UseKey(key)
+}
+
+// UseSymmetricKey is a DecryptionKeyOption for "A*KW" and "dir".
+func UseSymmetricKey(key []byte) DecryptionKeyOption {
+	return UseKey(key)
+}
+
+// UseJSONWebKey is a DecryptionKeyOption with a private (or symmetric) JSON Web Key.
+func UseJSONWebKey(jsonWebKey *jwk.JSONWebKey) DecryptionKeyOption {
+	return DecryptionKeyOption{
+		jsonWebKey: jsonWebKey,
+	}
+}
+
+func (o DecryptionKeyOption) getKey() (any, error) {
+	if o.key != nil {
+		return o.key, nil
+	}
+
+	if o.jsonWebKey != nil {
+		switch o.jsonWebKey.KeyType {
+		case "RSA":
+			key, err := o.jsonWebKey.DecodeRSAPrivateKey()
+			if err != nil {
+				return nil, fmt.Errorf("(*jwk.JSONWebKey).DecodeRSAPrivateKey: %w", err)
+			}
+			key.Precompute()
+			return key, nil
+		case "EC":
+			key, err := o.jsonWebKey.DecodeECDSAPrivateKey()
+			if err != nil {
+				return nil, fmt.Errorf("(*jwk.JSONWebKey).DecodeECDSAPrivateKey: %w", err)
+			}
+			return key, nil
+		case "oct":
+			key, err := o.jsonWebKey.DecodeSymmetricKey()
+			if err != nil {
+				return nil, fmt.Errorf("(*jwk.JSONWebKey).DecodeSymmetricKey: %w", err)
+			}
+			return key, nil
+		}
+		return nil, fmt.Errorf("kty=%s: %w", o.jsonWebKey.KeyType, jwk.ErrKeyIsNotForAlgorithm)
+	}
+
+	return nil, ErrInvalidKeyOption
+}
+
+type decryptOption struct {
+	crit []string
+}
+
+type DecryptOption func(*decryptOption)
+
+// DecryptCriticalHeaderParameters is a DecryptOption to specify the names of
+// the "crit" header parameters which the application understands and processes.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7516#section-4.1.13
+func DecryptCriticalHeaderParameters(names ...string) DecryptOption {
+	return func(do *decryptOption) {
+		do.crit = append(do.crit, names...)
+	}
+}
+
+// Decrypt
+//
+// Example:
+//
+//	header, plaintext, err := jwe.Decrypt(
+//		jwe.UseRSAKey(privateKey),
+//		token,
+//	)
+func Decrypt(keyOpt DecryptionKeyOption, token string, opts ...DecryptOption) (header *jose.Header, plaintext []byte, err error) {
+	headerEncoded, encryptedKeyEncoded, ivEncoded, ciphertextEncoded, tagEncoded, err := Parse(token)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jwe.Parse: %w", err)
+	}
+
+	h := new(jose.Header)
+	if err := h.Decode(headerEncoded); err != nil {
+		return nil, nil, fmt.Errorf("(*jose.Header).Decode: %w", err)
+	}
+
+	if err := decryptCritical(h, opts...); err != nil {
+		return nil, nil, err
+	}
+
+	km, err := getKeyManagement(h.Algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ce, err := getContentEncryption(h.EncryptionAlgorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err := base64.RawURLEncoding.DecodeString(encryptedKeyEncoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: encrypted key: %w", err)
+	}
+	iv, err := base64.RawURLEncoding.DecodeString(ivEncoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: iv: %w", err)
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(ciphertextEncoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: ciphertext: %w", err)
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(tagEncoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: tag: %w", err)
+	}
+
+	key, err := keyOpt.getKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cek, err := km.decrypt(key, h, encryptedKey, ce.keySize())
+	if err != nil {
+		return nil, nil, fmt.Errorf("alg=%s: %w", h.Algorithm, err)
+	}
+
+	payload, err := ce.decrypt(cek, iv, ciphertext, tag, []byte(headerEncoded))
+	if err != nil {
+		return nil, nil, fmt.Errorf("enc=%s: %w", h.EncryptionAlgorithm, err)
+	}
+
+	plaintext, err = decompress(h.CompressionAlgorithm, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return h, plaintext, nil
+}
+
+func decompress(zip string, payload []byte) ([]byte, error) {
+	switch zip {
+	case "":
+		return payload, nil
+	case jwa.DEF:
+		// Limit the decompressed size to mitigate decompression bombs.
+		const minLimit, ratio = 250 * 1024, 10
+		limit := max(int64(minLimit), int64(len(payload))*ratio)
+		r := flate.NewReader(bytes.NewReader(payload))
+		defer r.Close()
+		plaintext, err := io.ReadAll(io.LimitReader(r, limit+1))
+		if err != nil {
+			return nil, fmt.Errorf("io.ReadAll: %w", err)
+		}
+		if int64(len(plaintext)) > limit {
+			return nil, fmt.Errorf("limit=%d: %w", limit, ErrDecompressedPayloadIsTooLarge)
+		}
+		return plaintext, nil
+	}
+
+	return nil, fmt.Errorf("zip=%s: %w", zip, ErrCompressionAlgorithmIsNotSupported)
+}
+
+//nolint:gochecknoglobals
+var registeredHeaderParameterNames = map[string]bool{
+	"alg": true, "enc": true, "zip": true, "jku": true, "jwk": true, "kid": true, "x5u": true, "x5c": true, "x5t": true, "x5t#S256": true, "typ": true, "cty": true, "crit": true,
+	"epk": true, "apu": true, "apv": true, "iv": true, "tag": true, "p2s": true, "p2c": true,
+}
+
+// decryptCritical processes "crit" header parameter.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7516#section-4.1.13
+func decryptCritical(h *jose.Header, opts ...DecryptOption) error {
+	if h.Critical == nil {
+		return nil
+	}
+
+	if len(h.Critical) == 0 {
+		return fmt.Errorf("crit is empty: %w", ErrInvalidCriticalHeaderParameter)
+	}
+
+	do := new(decryptOption)
+	for _, opt := range opts {
+		opt(do)
+	}
+
+	for _, name := range h.Critical {
+		if registeredHeaderParameterNames[name] {
+			return fmt.Errorf("crit=%s: %w", name, ErrInvalidCriticalHeaderParameter)
+		}
+		if !slicez.Contains(do.crit, name) {
+			return fmt.Errorf("crit=%s: %w", name, ErrCriticalHeaderParameterIsNotUnderstood)
+		}
+		if _, ok := h.PrivateHeaderParameters[name]; !ok {
+			return fmt.Errorf("crit=%s: not present: %w", name, ErrInvalidCriticalHeaderParameter)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package jwe
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+)
+
+type EncryptionKeyOption struct {
+	key        any
+	jsonWebKey *jwk.JSONWebKey
+}
+
+func WithKey(key any) EncryptionKeyOption {
+	return EncryptionKeyOption{
+		key: key,
+	}
+}
+
+// WithRSAKey is a EncryptionKeyOption for "RSA-OAEP" and "RSA-OAEP-256".
+func WithRSAKey(key *rsa.PublicKey) EncryptionKeyOption {
+	return WithKey(key)
+}
+
+// WithECDSAKey is a EncryptionKeyOption for "ECDH-ES" and "ECDH-ES+A*KW".
+func WithECDSAKey(key *ecdsa.PublicKey) EncryptionKeyOption {
+	return WithKey(key)
+}
+
+// WithSymmetricKey is a EncryptionKeyOption for "A*KW" and "dir".
+func WithSymmetricKey(key []byte) EncryptionKeyOption {
+	return WithKey(key)
+}
+
+// WithJSONWebKey is a EncryptionKeyOption with a public (or symmetric) JSON Web Key.
+func WithJSONWebKey(jsonWebKey *jwk.JSONWebKey) EncryptionKeyOption {
+	return EncryptionKeyOption{
+		jsonWebKey: jsonWebKey,
+	}
+}
+
+func (o EncryptionKeyOption) getKey() (any, error) {
+	if o.key != nil {
+		return o.key, nil
+	}
+
+	if o.jsonWebKey != nil {
+		if o.jsonWebKey.KeyType == "oct" {
+			key, err := o.jsonWebKey.DecodeSymmetricKey()
+			if err != nil {
+				return nil, fmt.Errorf("(*jwk.JSONWebKey).DecodeSymmetricKey: %w", err)
+			}
+			return key, nil
+		}
+		key, err := o.jsonWebKey.DecodePublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("(*jwk.JSONWebKey).DecodePublicKey: %w", err)
+		}
+		return key, nil
+	}
+
+	return nil, ErrInvalidKeyOption
+}
+
+// Encrypt
+//
+// Example:
+//
+//	token, err := jwe.Encrypt(
+//		jwe.WithRSAKey(publicKey),
+//		jose.NewHeader(jwa.RSAOAEP256, jose.WithEncryptionAlgorithm(jwa.A256GCM)),
+//		[]byte("plaintext"),
+//	)
+func Encrypt(keyOpt EncryptionKeyOption, header *jose.Header, plaintext []byte) (token string, err error) {
+	km, err := getKeyManagement(header.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	ce, err := getContentEncryption(header.EncryptionAlgorithm)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := keyOpt.getKey()
+	if err != nil {
+		return "", err
+	}
+
+	// copy not to modify the caller's header
+	h := *header
+
+	cek, encryptedKey, err := km.encrypt(key, &h, ce.keySize())
+	if err != nil {
+		return "", fmt.Errorf("alg=%s: %w", h.Algorithm, err)
+	}
+
+	payload, err := compress(h.CompressionAlgorithm, plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	headerEncoded, err := h.Encode()
+	if err != nil {
+		return "", fmt.Errorf("(*jose.Header).Encode: %w", err)
+	}
+
+	// Additional Authenticated Data = ASCII(Encoded Protected Header)
+	//
+	//   - ref. https://www.rfc-editor.org/rfc/rfc7516#section-5.1
+	iv, ciphertext, tag, err := ce.encrypt(cek, payload, []byte(headerEncoded))
+	if err != nil {
+		return "", fmt.Errorf("enc=%s: %w", h.EncryptionAlgorithm, err)
+	}
+
+	return headerEncoded + "." +
+		base64.RawURLEncoding.EncodeToString(encryptedKey) + "." +
+		base64.RawURLEncoding.EncodeToString(iv) + "." +
+		base64.RawURLEncoding.EncodeToString(ciphertext) + "." +
+		base64.RawURLEncoding.EncodeToString(tag), nil
+}
+
+func compress(zip string, plaintext []byte) ([]byte, error) {
+	switch zip {
+	case "":
+		return plaintext, nil
+	case jwa.DEF:
+		buf := new(bytes.Buffer)
+		w, err := flate.NewWriter(buf, flate.DefaultCompression)
+		if err != nil {
+			return nil, fmt.Errorf("flate.NewWriter: %w", err)
+		}
+		if _, err := w.Write(plaintext); err != nil {
+			return nil, fmt.Errorf("(*flate.Writer).Write: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("(*flate.Writer).Close: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("zip=%s: %w", zip, ErrCompressionAlgorithmIsNotSupported)
+}
@@ -1,7 +1,45 @@
 package jwe
 
+import (
+	"errors"
+	"strings"
+)
+
 //
 // JSON Web Encryption (JWE) https://www.rfc-editor.org/rfc/rfc7516
 //
 
-// TODO: implement
+var (
+	ErrInvalidTokenReceived                   = errors.New(`jwe: invalid token received, token must have 5 parts`)
+	ErrInvalidKeyOption                       = errors.New(`jwe: invalid key option`)
+	ErrInvalidKeyReceived                     = errors.New(`jwe: invalid key received`)
+	ErrAlgorithmIsNotSupported                = errors.New(`jwe: algorithm is not supported`)
+	ErrEncryptionAlgorithmIsNotSupported      = errors.New(`jwe: encryption algorithm is not supported`)
+	ErrCompressionAlgorithmIsNotSupported     = errors.New(`jwe: compression algorithm is not supported`)
+	ErrEphemeralPublicKeyIsEmpty              = errors.New(`jwe: epk is empty`)
+	ErrFailedToDecrypt                        = errors.New(`jwe: failed to decrypt`)
+	ErrDecompressedPayloadIsTooLarge          = errors.New(`jwe: decompressed payload is too large`)
+	ErrInvalidCriticalHeaderParameter         = errors.New(`jwe: invalid crit header parameter`)
+	ErrCriticalHeaderParameterIsNotUnderstood = errors.New(`jwe: crit header parameter is not understood`)
+)
+
+// Parse splits a JWE Compact Serialization into its five parts.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7516#section-7.1
+//
+// 7.1.  JWE Compact Serialization
+//
+//	BASE64URL(UTF8(JWE Protected Header)) || '.' ||
+//	BASE64URL(JWE Encrypted Key) || '.' ||
+//	BASE64URL(JWE Initialization Vector) || '.' ||
+//	BASE64URL(JWE Ciphertext) || '.' ||
+//	BASE64URL(JWE Authentication Tag)
+func Parse(token string) (headerEncoded, encryptedKeyEncoded, initializationVectorEncoded, ciphertextEncoded, authenticationTagEncoded string, err error) {
+	parts := strings.Split(token, ".")
+	const expectedPartsLen = 5
+	if len(parts) != expectedPartsLen {
+		return "", "", "", "", "", ErrInvalidTokenReceived
+	}
+
+	return parts[0], parts[1], parts[2], parts[3], parts[4], nil
+}
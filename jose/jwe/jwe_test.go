@@ -0,0 +1,341 @@
+package jwe_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwe"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/must"
+	testingz "github.com/kunitsucom/util.go/testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		if _, _, _, _, _, err := jwe.Parse("header.key.iv.ciphertext.tag"); err != nil {
+			t.Errorf("❌: jwe.Parse: err != nil: %v", err)
+		}
+	})
+	t.Run("failure(jwe.ErrInvalidTokenReceived)", func(t *testing.T) {
+		t.Parallel()
+		if _, _, _, _, _, err := jwe.Parse("header.payload.signature"); !errors.Is(err, jwe.ErrInvalidTokenReceived) {
+			t.Errorf("❌: jwe.Parse: err != jwe.ErrInvalidTokenReceived: %v", err)
+		}
+	})
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	rsaPrivateKey := must.One(x509z.ParseRSAPrivateKeyPEM([]byte(testingz.TestRSAPrivateKey2048BitPEM)))
+	ecdsaPrivateKey256 := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	ecdsaPrivateKey384 := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey384BitPEM)))
+	ecdsaPrivateKey521 := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey521bitPEM)))
+	plaintext := []byte("The true sign of intelligence is not knowledge but imagination.")
+
+	encs := map[string]int{
+		jwa.A128CBCHS256: 32,
+		jwa.A192CBCHS384: 48,
+		jwa.A256CBCHS512: 64,
+		jwa.A128GCM:      16,
+		jwa.A192GCM:      24,
+		jwa.A256GCM:      32,
+	}
+
+	for enc, cekSize := range encs {
+		cases := []struct {
+			alg    string
+			encKey jwe.EncryptionKeyOption
+			decKey jwe.DecryptionKeyOption
+		}{
+			{jwa.RSAOAEP, jwe.WithRSAKey(&rsaPrivateKey.PublicKey), jwe.UseRSAKey(rsaPrivateKey)},
+			{jwa.RSAOAEP256, jwe.WithRSAKey(&rsaPrivateKey.PublicKey), jwe.UseRSAKey(rsaPrivateKey)},
+			{jwa.A128KW, jwe.WithSymmetricKey(bytes.Repeat([]byte{1}, 16)), jwe.UseSymmetricKey(bytes.Repeat([]byte{1}, 16))},
+			{jwa.A192KW, jwe.WithSymmetricKey(bytes.Repeat([]byte{1}, 24)), jwe.UseSymmetricKey(bytes.Repeat([]byte{1}, 24))},
+			{jwa.A256KW, jwe.WithSymmetricKey(bytes.Repeat([]byte{1}, 32)), jwe.UseSymmetricKey(bytes.Repeat([]byte{1}, 32))},
+			{jwa.Dir, jwe.WithSymmetricKey(bytes.Repeat([]byte{1}, cekSize)), jwe.UseSymmetricKey(bytes.Repeat([]byte{1}, cekSize))},
+			{jwa.ECDHES, jwe.WithECDSAKey(&ecdsaPrivateKey256.PublicKey), jwe.UseECDSAKey(ecdsaPrivateKey256)},
+			{jwa.ECDHES, jwe.WithECDSAKey(&ecdsaPrivateKey384.PublicKey), jwe.UseECDSAKey(ecdsaPrivateKey384)},
+			{jwa.ECDHES, jwe.WithECDSAKey(&ecdsaPrivateKey521.PublicKey), jwe.UseECDSAKey(ecdsaPrivateKey521)},
+			{jwa.ECDHESA128KW, jwe.WithECDSAKey(&ecdsaPrivateKey256.PublicKey), jwe.UseECDSAKey(ecdsaPrivateKey256)},
+			{jwa.ECDHESA192KW, jwe.WithECDSAKey(&ecdsaPrivateKey384.PublicKey), jwe.UseECDSAKey(ecdsaPrivateKey384)},
+			{jwa.ECDHESA256KW, jwe.WithECDSAKey(&ecdsaPrivateKey521.PublicKey), jwe.UseECDSAKey(ecdsaPrivateKey521)},
+		}
+		for _, tt := range cases {
+			t.Run("success("+tt.alg+","+enc+")", func(t *testing.T) {
+				t.Parallel()
+				header := jose.NewHeader(tt.alg, jose.WithEncryptionAlgorithm(enc), jose.WithKeyID("testKeyID"))
+				token, err := jwe.Encrypt(tt.encKey, header, plaintext)
+				if err != nil {
+					t.Fatalf("❌: jwe.Encrypt: err != nil: %v", err)
+				}
+				if header.EphemeralPublicKey != nil {
+					t.Errorf("❌: jwe.Encrypt: header is modified: %v", header.EphemeralPublicKey)
+				}
+				h, actual, err := jwe.Decrypt(tt.decKey, token)
+				if err != nil {
+					t.Fatalf("❌: jwe.Decrypt: err != nil: %v", err)
+				}
+				if !bytes.Equal(plaintext, actual) {
+					t.Errorf("❌: jwe.Decrypt: expect(%s) != actual(%s)", plaintext, actual)
+				}
+				if expect, actual := "testKeyID", h.KeyID; expect != actual {
+					t.Errorf("❌: jwe.Decrypt: kid: expect(%s) != actual(%s)", expect, actual)
+				}
+			})
+		}
+	}
+
+	t.Run("success(zip=DEF)", func(t *testing.T) {
+		t.Parallel()
+		key := bytes.Repeat([]byte{1}, 16)
+		long := bytes.Repeat(plaintext, 100)
+		token, err := jwe.Encrypt(jwe.WithSymmetricKey(key), jose.NewHeader(jwa.A128KW, jose.WithEncryptionAlgorithm(jwa.A128GCM), jose.WithCompressionAlgorithm(jwa.DEF)), long)
+		if err != nil {
+			t.Fatalf("❌: jwe.Encrypt: err != nil: %v", err)
+		}
+		if len(token) >= len(long) {
+			t.Errorf("❌: jwe.Encrypt: len(token)=%d >= len(plaintext)=%d", len(token), len(long))
+		}
+		_, actual, err := jwe.Decrypt(jwe.UseSymmetricKey(key), token)
+		if err != nil {
+			t.Fatalf("❌: jwe.Decrypt: err != nil: %v", err)
+		}
+		if !bytes.Equal(long, actual) {
+			t.Errorf("❌: jwe.Decrypt: expect != actual: %s", actual)
+		}
+	})
+
+	t.Run("success(apu,apv)", func(t *testing.T) {
+		t.Parallel()
+		header := jose.NewHeader(jwa.ECDHES, jose.WithEncryptionAlgorithm(jwa.A128GCM))
+		header.AgreementPartyUInfo = "QWxpY2U"
+		header.AgreementPartyVInfo = "Qm9i"
+		token, err := jwe.Encrypt(jwe.WithECDSAKey(&ecdsaPrivateKey256.PublicKey), header, plaintext)
+		if err != nil {
+			t.Fatalf("❌: jwe.Encrypt: err != nil: %v", err)
+		}
+		if _, _, err := jwe.Decrypt(jwe.UseECDSAKey(ecdsaPrivateKey256), token); err != nil {
+			t.Fatalf("❌: jwe.Decrypt: err != nil: %v", err)
+		}
+	})
+
+	t.Run("success(JSONWebKey)", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			alg  string
+			pub  *jwk.JSONWebKey
+			priv *jwk.JSONWebKey
+		}{
+			{jwa.RSAOAEP256, new(jwk.JSONWebKey).EncodeRSAPublicKey(&rsaPrivateKey.PublicKey), new(jwk.JSONWebKey).EncodeRSAPrivateKey(rsaPrivateKey)},
+			{jwa.ECDHESA256KW, new(jwk.JSONWebKey).EncodeECDSAPublicKey(&ecdsaPrivateKey256.PublicKey), new(jwk.JSONWebKey).EncodeECDSAPrivateKey(ecdsaPrivateKey256)},
+			{jwa.A256KW, &jwk.JSONWebKey{KeyType: "oct", K: "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8"}, &jwk.JSONWebKey{KeyType: "oct", K: "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8"}},
+		} {
+			token, err := jwe.Encrypt(jwe.WithJSONWebKey(tt.pub), jose.NewHeader(tt.alg, jose.WithEncryptionAlgorithm(jwa.A256GCM)), plaintext)
+			if err != nil {
+				t.Fatalf("❌: jwe.Encrypt: alg=%s: err != nil: %v", tt.alg, err)
+			}
+			_, actual, err := jwe.Decrypt(jwe.UseJSONWebKey(tt.priv), token)
+			if err != nil {
+				t.Fatalf("❌: jwe.Decrypt: alg=%s: err != nil: %v", tt.alg, err)
+			}
+			if !bytes.Equal(plaintext, actual) {
+				t.Errorf("❌: jwe.Decrypt: alg=%s: expect != actual: %s", tt.alg, actual)
+			}
+		}
+	})
+
+	t.Run("failure(jwe.ErrAlgorithmIsNotSupported)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jwe.Encrypt(jwe.WithKey(nil), jose.NewHeader("RSA1_5", jose.WithEncryptionAlgorithm(jwa.A128GCM)), plaintext); !errors.Is(err, jwe.ErrAlgorithmIsNotSupported) {
+			t.Errorf("❌: jwe.Encrypt: err != jwe.ErrAlgorithmIsNotSupported: %v", err)
+		}
+	})
+
+	t.Run("failure(jwe.ErrEncryptionAlgorithmIsNotSupported)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jwe.Encrypt(jwe.WithKey(nil), jose.NewHeader(jwa.Dir, jose.WithEncryptionAlgorithm("invalid")), plaintext); !errors.Is(err, jwe.ErrEncryptionAlgorithmIsNotSupported) {
+			t.Errorf("❌: jwe.Encrypt: err != jwe.ErrEncryptionAlgorithmIsNotSupported: %v", err)
+		}
+	})
+
+	t.Run("failure(jwe.ErrCompressionAlgorithmIsNotSupported)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jwe.Encrypt(jwe.WithSymmetricKey(make([]byte, 16)), jose.NewHeader(jwa.Dir, jose.WithEncryptionAlgorithm(jwa.A128GCM), jose.WithCompressionAlgorithm("invalid")), plaintext); !errors.Is(err, jwe.ErrCompressionAlgorithmIsNotSupported) {
+			t.Errorf("❌: jwe.Encrypt: err != jwe.ErrCompressionAlgorithmIsNotSupported: %v", err)
+		}
+	})
+
+	t.Run("failure(jwe.ErrInvalidKeyOption)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jwe.Encrypt(jwe.WithKey(nil), jose.NewHeader(jwa.Dir, jose.WithEncryptionAlgorithm(jwa.A128GCM)), plaintext); !errors.Is(err, jwe.ErrInvalidKeyOption) {
+			t.Errorf("❌: jwe.Encrypt: err != jwe.ErrInvalidKeyOption: %v", err)
+		}
+	})
+
+	t.Run("failure(jwe.ErrInvalidKeyReceived)", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			alg string
+			key jwe.EncryptionKeyOption
+		}{
+			{jwa.RSAOAEP, jwe.WithECDSAKey(&ecdsaPrivateKey256.PublicKey)},
+			{jwa.ECDHES, jwe.WithRSAKey(&rsaPrivateKey.PublicKey)},
+			{jwa.A128KW, jwe.WithSymmetricKey(make([]byte, 32))},
+			{jwa.Dir, jwe.WithSymmetricKey(make([]byte, 32))},
+		} {
+			if _, err := jwe.Encrypt(tt.key, jose.NewHeader(tt.alg, jose.WithEncryptionAlgorithm(jwa.A128GCM)), plaintext); !errors.Is(err, jwe.ErrInvalidKeyReceived) {
+				t.Errorf("❌: jwe.Encrypt: alg=%s: err != jwe.ErrInvalidKeyReceived: %v", tt.alg, err)
+			}
+		}
+	})
+}
+
+func TestDecrypt(t *testing.T) {
+	t.Parallel()
+
+	rsaPrivateKey := must.One(x509z.ParseRSAPrivateKeyPEM([]byte(testingz.TestRSAPrivateKey2048BitPEM)))
+	ecdsaPrivateKey256 := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	ecdsaPrivateKey384 := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey384BitPEM)))
+
+	t.Run("success(RFC7516,A.3)", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc7516#appendix-A.3
+		key := &jwk.JSONWebKey{KeyType: "oct", K: "GawgguFyGrWKav7AX4VKUg"}
+		token := "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+			"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+			"AxY8DCtDaGlsbGljb3RoZQ." +
+			"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+			"U0m_YmjN04DJvceFICbCVQ"
+		_, actual, err := jwe.Decrypt(jwe.UseJSONWebKey(key), token)
+		if err != nil {
+			t.Fatalf("❌: jwe.Decrypt: err != nil: %v", err)
+		}
+		if expect := "Live long and prosper."; expect != string(actual) {
+			t.Errorf("❌: jwe.Decrypt: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+
+	t.Run("failure(jwe.ErrInvalidTokenReceived)", func(t *testing.T) {
+		t.Parallel()
+		if _, _, err := jwe.Decrypt(jwe.UseKey(nil), "invalid.jwe"); !errors.Is(err, jwe.ErrInvalidTokenReceived) {
+			t.Errorf("❌: jwe.Decrypt: err != jwe.ErrInvalidTokenReceived: %v", err)
+		}
+	})
+
+	t.Run("failure(header.Decode)", func(t *testing.T) {
+		t.Parallel()
+		expect := "illegal base64 data at input byte 3"
+		if _, _, err := jwe.Decrypt(jwe.UseKey(nil), "inv@lid.key.iv.ciphertext.tag"); err == nil || !strings.Contains(err.Error(), expect) {
+			t.Errorf("❌: jwe.Decrypt: err != %s: %v", expect, err)
+		}
+	})
+
+	t.Run("failure(jwe.ErrFailedToDecrypt)", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			alg    string
+			encKey jwe.EncryptionKeyOption
+			decKey jwe.DecryptionKeyOption
+		}{
+			{jwa.RSAOAEP256, jwe.WithRSAKey(&rsaPrivateKey.PublicKey), jwe.UseRSAKey(rsaPrivateKey)},
+			{jwa.A128KW, jwe.WithSymmetricKey(make([]byte, 16)), jwe.UseSymmetricKey(make([]byte, 16))},
+			{jwa.ECDHES, jwe.WithECDSAKey(&ecdsaPrivateKey256.PublicKey), jwe.UseECDSAKey(ecdsaPrivateKey256)},
+		} {
+			for _, enc := range []string{jwa.A128GCM, jwa.A128CBCHS256} {
+				token, err := jwe.Encrypt(tt.encKey, jose.NewHeader(tt.alg, jose.WithEncryptionAlgorithm(enc)), []byte("plaintext"))
+				if err != nil {
+					t.Fatalf("❌: jwe.Encrypt: err != nil: %v", err)
+				}
+				// tamper with the authentication tag
+				parts := strings.Split(token, ".")
+				tag, err := base64.RawURLEncoding.DecodeString(parts[4])
+				if err != nil {
+					t.Fatalf("❌: base64.RawURLEncoding.DecodeString: err != nil: %v", err)
+				}
+				tag[0] ^= 0xff
+				parts[4] = base64.RawURLEncoding.EncodeToString(tag)
+				tampered := strings.Join(parts, ".")
+				if _, _, err := jwe.Decrypt(tt.decKey, tampered); !errors.Is(err, jwe.ErrFailedToDecrypt) {
+					t.Errorf("❌: jwe.Decrypt: alg=%s enc=%s: err != jwe.ErrFailedToDecrypt: %v", tt.alg, enc, err)
+				}
+			}
+		}
+	})
+
+	t.Run("success(crit)", func(t *testing.T) {
+		t.Parallel()
+		token, err := jwe.Encrypt(jwe.WithSymmetricKey(make([]byte, 16)), jose.NewHeader(jwa.A128KW, jose.WithEncryptionAlgorithm(jwa.A128GCM), jose.WithCritical([]string{"exp"}), jose.WithPrivateHeaderParameter("exp", 1)), []byte("plaintext"))
+		if err != nil {
+			t.Fatalf("❌: jwe.Encrypt: err != nil: %v", err)
+		}
+		if _, _, err := jwe.Decrypt(jwe.UseSymmetricKey(make([]byte, 16)), token, jwe.DecryptCriticalHeaderParameters("exp")); err != nil {
+			t.Errorf("❌: jwe.Decrypt: err != nil: %v", err)
+		}
+	})
+
+	t.Run("failure(crit)", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			header *jose.Header
+			expect error
+		}{
+			{jose.NewHeader(jwa.A128KW, jose.WithEncryptionAlgorithm(jwa.A128GCM), jose.WithCritical([]string{"exp"}), jose.WithPrivateHeaderParameter("exp", 1)), jwe.ErrCriticalHeaderParameterIsNotUnderstood},
+			{jose.NewHeader(jwa.A128KW, jose.WithEncryptionAlgorithm(jwa.A128GCM), jose.WithCritical([]string{"enc"})), jwe.ErrInvalidCriticalHeaderParameter},
+		} {
+			token, err := jwe.Encrypt(jwe.WithSymmetricKey(make([]byte, 16)), tt.header, []byte("plaintext"))
+			if err != nil {
+				t.Fatalf("❌: jwe.Encrypt: err != nil: %v", err)
+			}
+			if _, _, err := jwe.Decrypt(jwe.UseSymmetricKey(make([]byte, 16)), token); !errors.Is(err, tt.expect) {
+				t.Errorf("❌: jwe.Decrypt: err != %v: %v", tt.expect, err)
+			}
+		}
+	})
+
+	t.Run("failure(jwe.ErrFailedToDecrypt,wrongKey)", func(t *testing.T) {
+		t.Parallel()
+		token, err := jwe.Encrypt(jwe.WithSymmetricKey(make([]byte, 16)), jose.NewHeader(jwa.A128KW, jose.WithEncryptionAlgorithm(jwa.A128GCM)), []byte("plaintext"))
+		if err != nil {
+			t.Fatalf("❌: jwe.Encrypt: err != nil: %v", err)
+		}
+		if _, _, err := jwe.Decrypt(jwe.UseSymmetricKey(bytes.Repeat([]byte{1}, 16)), token); !errors.Is(err, jwe.ErrFailedToDecrypt) {
+			t.Errorf("❌: jwe.Decrypt: err != jwe.ErrFailedToDecrypt: %v", err)
+		}
+	})
+
+	t.Run("failure(jwe.ErrInvalidKeyReceived,curve)", func(t *testing.T) {
+		t.Parallel()
+		token, err := jwe.Encrypt(jwe.WithECDSAKey(&ecdsaPrivateKey256.PublicKey), jose.NewHeader(jwa.ECDHES, jose.WithEncryptionAlgorithm(jwa.A128GCM)), []byte("plaintext"))
+		if err != nil {
+			t.Fatalf("❌: jwe.Encrypt: err != nil: %v", err)
+		}
+		if _, _, err := jwe.Decrypt(jwe.UseECDSAKey(ecdsaPrivateKey384), token); !errors.Is(err, jwe.ErrInvalidKeyReceived) {
+			t.Errorf("❌: jwe.Decrypt: err != jwe.ErrInvalidKeyReceived: %v", err)
+		}
+	})
+
+	t.Run("failure(jwe.ErrEphemeralPublicKeyIsEmpty)", func(t *testing.T) {
+		t.Parallel()
+		headerEncoded := must.One(jose.NewHeader(jwa.ECDHES, jose.WithEncryptionAlgorithm(jwa.A128GCM)).Encode())
+		if _, _, err := jwe.Decrypt(jwe.UseECDSAKey(ecdsaPrivateKey256), headerEncoded+"..AAAA.AAAA.AAAA"); !errors.Is(err, jwe.ErrEphemeralPublicKeyIsEmpty) {
+			t.Errorf("❌: jwe.Decrypt: err != jwe.ErrEphemeralPublicKeyIsEmpty: %v", err)
+		}
+	})
+
+	t.Run("failure(jwk.ErrKeyIsNotForAlgorithm)", func(t *testing.T) {
+		t.Parallel()
+		headerEncoded := must.One(jose.NewHeader(jwa.Dir, jose.WithEncryptionAlgorithm(jwa.A128GCM)).Encode())
+		if _, _, err := jwe.Decrypt(jwe.UseJSONWebKey(&jwk.JSONWebKey{KeyType: "invalid"}), headerEncoded+"..AAAA.AAAA.AAAA"); !errors.Is(err, jwk.ErrKeyIsNotForAlgorithm) {
+			t.Errorf("❌: jwe.Decrypt: err != jwk.ErrKeyIsNotForAlgorithm: %v", err)
+		}
+	})
+}
@@ -0,0 +1,359 @@
+package jwe
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+)
+
+// keyManagement is a key management algorithm ("alg").
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-4
+type keyManagement interface {
+	// encrypt determines the CEK and returns it with the JWE Encrypted Key.
+	// It may set Header Parameters (e.g. "epk") to header.
+	encrypt(key any, header *jose.Header, cekSize int) (cek, encryptedKey []byte, err error)
+	// decrypt determines the CEK from the JWE Encrypted Key and header.
+	decrypt(key any, header *jose.Header, encryptedKey []byte, cekSize int) (cek []byte, err error)
+}
+
+func getKeyManagement(alg string) (keyManagement, error) { //nolint:ireturn
+	switch alg {
+	case jwa.RSAOAEP:
+		return rsaOAEP{hashNewFunc: sha1.New}, nil
+	case jwa.RSAOAEP256:
+		return rsaOAEP{hashNewFunc: sha256.New}, nil
+	case jwa.A128KW:
+		return aesKW{size: 16}, nil
+	case jwa.A192KW:
+		return aesKW{size: 24}, nil
+	case jwa.A256KW:
+		return aesKW{size: 32}, nil
+	case jwa.Dir:
+		return direct{}, nil
+	case jwa.ECDHES:
+		return ecdhES{}, nil
+	case jwa.ECDHESA128KW:
+		return ecdhES{kwSize: 16}, nil
+	case jwa.ECDHESA192KW:
+		return ecdhES{kwSize: 24}, nil
+	case jwa.ECDHESA256KW:
+		return ecdhES{kwSize: 32}, nil
+	}
+
+	return nil, fmt.Errorf("alg=%s: %w", alg, ErrAlgorithmIsNotSupported)
+}
+
+func generateCEK(cekSize int) ([]byte, error) {
+	cek := make([]byte, cekSize)
+	if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+		return nil, fmt.Errorf("io.ReadFull: %w", err)
+	}
+	return cek, nil
+}
+
+//
+// RSAES OAEP
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-4.3
+//
+
+type rsaOAEP struct {
+	hashNewFunc func() hash.Hash
+}
+
+func (a rsaOAEP) encrypt(key any, _ *jose.Header, cekSize int) (cek, encryptedKey []byte, err error) {
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("key=%T: %w", key, ErrInvalidKeyReceived)
+	}
+
+	cek, err = generateCEK(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err = rsa.EncryptOAEP(a.hashNewFunc(), rand.Reader, pub, cek, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rsa.EncryptOAEP: %w", err)
+	}
+
+	return cek, encryptedKey, nil
+}
+
+func (a rsaOAEP) decrypt(key any, _ *jose.Header, encryptedKey []byte, cekSize int) (cek []byte, err error) {
+	priv, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key=%T: %w", key, ErrInvalidKeyReceived)
+	}
+
+	// To mitigate the attacks described in RFC 3218, the recipient MUST NOT
+	// distinguish between format, padding, and length errors of encrypted keys.
+	// Substitute a random CEK and let the content decryption fail instead.
+	//
+	//   - ref. https://www.rfc-editor.org/rfc/rfc7516#section-11.5
+	cek, err = rsa.DecryptOAEP(a.hashNewFunc(), nil, priv, encryptedKey, nil)
+	if err != nil || len(cek) != cekSize {
+		return generateCEK(cekSize)
+	}
+
+	return cek, nil
+}
+
+//
+// AES Key Wrap
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-4.4
+//
+
+type aesKW struct {
+	size int
+}
+
+func (a aesKW) kek(key any) ([]byte, error) {
+	kek, ok := key.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("key=%T: %w", key, ErrInvalidKeyReceived)
+	}
+	if len(kek) != a.size {
+		return nil, fmt.Errorf("len(key)=%d: %w", len(kek), ErrInvalidKeyReceived)
+	}
+	return kek, nil
+}
+
+func (a aesKW) encrypt(key any, _ *jose.Header, cekSize int) (cek, encryptedKey []byte, err error) {
+	kek, err := a.kek(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cek, err = generateCEK(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err = aesKeyWrap(kek, cek)
+	if err != nil {
+		return nil, nil, fmt.Errorf("aesKeyWrap: %w", err)
+	}
+
+	return cek, encryptedKey, nil
+}
+
+func (a aesKW) decrypt(key any, _ *jose.Header, encryptedKey []byte, cekSize int) (cek []byte, err error) {
+	kek, err := a.kek(key)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err = aesKeyUnwrap(kek, encryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("aesKeyUnwrap: %w", err)
+	}
+	if len(cek) != cekSize {
+		return nil, fmt.Errorf("len(cek)=%d: %w", len(cek), ErrFailedToDecrypt)
+	}
+
+	return cek, nil
+}
+
+//
+// Direct Encryption with a Shared Symmetric Key
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-4.5
+//
+
+type direct struct{}
+
+func (direct) encrypt(key any, _ *jose.Header, cekSize int) (cek, encryptedKey []byte, err error) {
+	cek, ok := key.([]byte)
+	if !ok {
+		return nil, nil, fmt.Errorf("key=%T: %w", key, ErrInvalidKeyReceived)
+	}
+	if len(cek) != cekSize {
+		return nil, nil, fmt.Errorf("len(key)=%d: %w", len(cek), ErrInvalidKeyReceived)
+	}
+	return cek, nil, nil
+}
+
+func (d direct) decrypt(key any, header *jose.Header, encryptedKey []byte, cekSize int) (cek []byte, err error) {
+	if len(encryptedKey) != 0 {
+		return nil, fmt.Errorf("alg=%s: encrypted key must be empty: %w", header.Algorithm, ErrFailedToDecrypt)
+	}
+	cek, _, err = d.encrypt(key, header, cekSize)
+	return cek, err
+}
+
+//
+// Key Agreement with Elliptic Curve Diffie-Hellman Ephemeral Static (ECDH-ES)
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-4.6
+//
+
+type ecdhES struct {
+	// kwSize is the AES Key Wrap key size in bytes. 0 means Direct Key Agreement.
+	kwSize int
+}
+
+func (a ecdhES) encrypt(key any, header *jose.Header, cekSize int) (cek, encryptedKey []byte, err error) {
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("key=%T: %w", key, ErrInvalidKeyReceived)
+	}
+	pubECDH, err := pub.ECDH()
+	if err != nil {
+		return nil, nil, fmt.Errorf("(*ecdsa.PublicKey).ECDH: %w", err)
+	}
+
+	ephemeral, err := ecdsa.GenerateKey(pub.Curve, rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ecdsa.GenerateKey: %w", err)
+	}
+	ephemeralECDH, err := ephemeral.ECDH()
+	if err != nil {
+		return nil, nil, fmt.Errorf("(*ecdsa.PrivateKey).ECDH: %w", err)
+	}
+
+	z, err := ephemeralECDH.ECDH(pubECDH)
+	if err != nil {
+		return nil, nil, fmt.Errorf("(*ecdh.PrivateKey).ECDH: %w", err)
+	}
+
+	header.EphemeralPublicKey = new(jwk.JSONWebKey).EncodeECDSAPublicKey(&ephemeral.PublicKey)
+
+	derived, err := a.deriveKey(z, header, cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if a.kwSize == 0 {
+		return derived, nil, nil
+	}
+
+	cek, err = generateCEK(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err = aesKeyWrap(derived, cek)
+	if err != nil {
+		return nil, nil, fmt.Errorf("aesKeyWrap: %w", err)
+	}
+
+	return cek, encryptedKey, nil
+}
+
+func (a ecdhES) decrypt(key any, header *jose.Header, encryptedKey []byte, cekSize int) (cek []byte, err error) {
+	priv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key=%T: %w", key, ErrInvalidKeyReceived)
+	}
+	privECDH, err := priv.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("(*ecdsa.PrivateKey).ECDH: %w", err)
+	}
+
+	if header.EphemeralPublicKey == nil {
+		return nil, ErrEphemeralPublicKeyIsEmpty
+	}
+	epk, err := header.EphemeralPublicKey.DecodeECDSAPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("(*jwk.JSONWebKey).DecodeECDSAPublicKey: %w", err)
+	}
+	if epk.Curve != priv.Curve {
+		return nil, fmt.Errorf("epk.crv=%s key.crv=%s: %w", epk.Params().Name, priv.Params().Name, ErrInvalidKeyReceived)
+	}
+	// (*ecdsa.PublicKey).ECDH validates that the point is on the curve.
+	epkECDH, err := epk.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("(*ecdsa.PublicKey).ECDH: %w", err)
+	}
+
+	z, err := privECDH.ECDH(epkECDH)
+	if err != nil {
+		return nil, fmt.Errorf("(*ecdh.PrivateKey).ECDH: %w", err)
+	}
+
+	derived, err := a.deriveKey(z, header, cekSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.kwSize == 0 {
+		if len(encryptedKey) != 0 {
+			return nil, fmt.Errorf("alg=%s: encrypted key must be empty: %w", header.Algorithm, ErrFailedToDecrypt)
+		}
+		return derived, nil
+	}
+
+	cek, err = aesKeyUnwrap(derived, encryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("aesKeyUnwrap: %w", err)
+	}
+	if len(cek) != cekSize {
+		return nil, fmt.Errorf("len(cek)=%d: %w", len(cek), ErrFailedToDecrypt)
+	}
+
+	return cek, nil
+}
+
+func (a ecdhES) deriveKey(z []byte, header *jose.Header, cekSize int) ([]byte, error) {
+	apu, err := base64.RawURLEncoding.DecodeString(header.AgreementPartyUInfo)
+	if err != nil {
+		return nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: apu: %w", err)
+	}
+	apv, err := base64.RawURLEncoding.DecodeString(header.AgreementPartyVInfo)
+	if err != nil {
+		return nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: apv: %w", err)
+	}
+
+	// In the Direct Key Agreement case, AlgorithmID is the "enc" value and
+	// keydatalen is the CEK size. Otherwise, AlgorithmID is the "alg" value
+	// and keydatalen is the AES Key Wrap key size.
+	//
+	//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-4.6.2
+	if a.kwSize == 0 {
+		return concatKDF(z, header.EncryptionAlgorithm, apu, apv, cekSize), nil
+	}
+	return concatKDF(z, header.Algorithm, apu, apv, a.kwSize), nil
+}
+
+// concatKDF is the Concat KDF with SHA-256.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-4.6.2
+//   - ref. https://nvlpubs.nist.gov/nistpubs/Legacy/SP/nistspecialpublication800-56a.pdf (Section 5.8.1)
+func concatKDF(z []byte, algorithmID string, partyUInfo, partyVInfo []byte, keyDataLen int) []byte {
+	const bitsPerByte = 8
+	otherInfo := make([]byte, 0)
+	otherInfo = appendLengthPrefixed(otherInfo, []byte(algorithmID))
+	otherInfo = appendLengthPrefixed(otherInfo, partyUInfo)
+	otherInfo = appendLengthPrefixed(otherInfo, partyVInfo)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(keyDataLen*bitsPerByte)) //nolint:gosec
+
+	derived := make([]byte, 0, keyDataLen+sha256.Size)
+	for counter := uint32(1); len(derived) < keyDataLen; counter++ {
+		h := sha256.New()
+		_ = binary.Write(h, binary.BigEndian, counter)
+		h.Write(z)
+		h.Write(otherInfo)
+		derived = h.Sum(derived)
+	}
+
+	return derived[:keyDataLen]
+}
+
+func appendLengthPrefixed(dst, data []byte) []byte {
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(data))) //nolint:gosec
+	return append(dst, data...)
+}
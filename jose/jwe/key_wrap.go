@@ -0,0 +1,87 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+// AES Key Wrap
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc3394#section-2.2
+
+const keyWrapBlockSize = 8
+
+//nolint:gochecknoglobals
+var keyWrapDefaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+func aesKeyWrap(kek, plaintext []byte) ([]byte, error) {
+	if len(plaintext)%keyWrapBlockSize != 0 || len(plaintext) < 2*keyWrapBlockSize {
+		return nil, fmt.Errorf("len(plaintext)=%d: %w", len(plaintext), ErrInvalidKeyReceived)
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+
+	n := len(plaintext) / keyWrapBlockSize
+	a := make([]byte, keyWrapBlockSize)
+	copy(a, keyWrapDefaultIV)
+	r := make([]byte, len(plaintext))
+	copy(r, plaintext)
+
+	b := make([]byte, aes.BlockSize)
+	const rounds = 6
+	for j := range rounds {
+		for i := 1; i <= n; i++ {
+			ri := r[(i-1)*keyWrapBlockSize : i*keyWrapBlockSize]
+			copy(b, a)
+			copy(b[keyWrapBlockSize:], ri)
+			block.Encrypt(b, b)
+			t := uint64(n*j + i) //nolint:gosec
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(b[:keyWrapBlockSize])^t)
+			copy(ri, b[keyWrapBlockSize:])
+		}
+	}
+
+	return append(a, r...), nil
+}
+
+func aesKeyUnwrap(kek, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext)%keyWrapBlockSize != 0 || len(ciphertext) < 3*keyWrapBlockSize {
+		return nil, fmt.Errorf("len(ciphertext)=%d: %w", len(ciphertext), ErrFailedToDecrypt)
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+
+	n := len(ciphertext)/keyWrapBlockSize - 1
+	a := make([]byte, keyWrapBlockSize)
+	copy(a, ciphertext[:keyWrapBlockSize])
+	r := make([]byte, len(ciphertext)-keyWrapBlockSize)
+	copy(r, ciphertext[keyWrapBlockSize:])
+
+	b := make([]byte, aes.BlockSize)
+	const rounds = 6
+	for j := rounds - 1; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			ri := r[(i-1)*keyWrapBlockSize : i*keyWrapBlockSize]
+			t := uint64(n*j + i) //nolint:gosec
+			binary.BigEndian.PutUint64(b, binary.BigEndian.Uint64(a)^t)
+			copy(b[keyWrapBlockSize:], ri)
+			block.Decrypt(b, b)
+			copy(a, b[:keyWrapBlockSize])
+			copy(ri, b[keyWrapBlockSize:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(a, keyWrapDefaultIV) != 1 {
+		return nil, fmt.Errorf("integrity check: %w", ErrFailedToDecrypt)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,65 @@
+package jwe //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func Test_aesKeyWrap(t *testing.T) {
+	t.Parallel()
+
+	// - ref. https://www.rfc-editor.org/rfc/rfc3394#section-4
+	for _, tt := range []struct {
+		name       string
+		kek        string
+		keyData    string
+		ciphertext string
+	}{
+		{"4.1", "000102030405060708090A0B0C0D0E0F", "00112233445566778899AABBCCDDEEFF", "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5"},
+		{"4.2", "000102030405060708090A0B0C0D0E0F1011121314151617", "00112233445566778899AABBCCDDEEFF", "96778B25AE6CA435F92B5B97C050AED2468AB8A17AD84E5D"},
+		{"4.3", "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F", "00112233445566778899AABBCCDDEEFF", "64E8C3F9CE0F5BA263E9777905818A2A93C8191E7D6E8AE7"},
+		{"4.6", "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F", "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F", "28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21"},
+	} {
+		t.Run("success("+tt.name+")", func(t *testing.T) {
+			t.Parallel()
+			kek, _ := hex.DecodeString(tt.kek)
+			keyData, _ := hex.DecodeString(tt.keyData)
+			expect, _ := hex.DecodeString(tt.ciphertext)
+
+			actual, err := aesKeyWrap(kek, keyData)
+			if err != nil {
+				t.Fatalf("❌: aesKeyWrap: err != nil: %v", err)
+			}
+			if !bytes.Equal(expect, actual) {
+				t.Errorf("❌: aesKeyWrap: expect(%X) != actual(%X)", expect, actual)
+			}
+
+			unwrapped, err := aesKeyUnwrap(kek, actual)
+			if err != nil {
+				t.Fatalf("❌: aesKeyUnwrap: err != nil: %v", err)
+			}
+			if !bytes.Equal(keyData, unwrapped) {
+				t.Errorf("❌: aesKeyUnwrap: expect(%X) != actual(%X)", keyData, unwrapped)
+			}
+		})
+	}
+
+	t.Run("failure(ErrInvalidKeyReceived)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := aesKeyWrap(make([]byte, 16), make([]byte, 12)); !errors.Is(err, ErrInvalidKeyReceived) {
+			t.Errorf("❌: aesKeyWrap: err != ErrInvalidKeyReceived: %v", err)
+		}
+	})
+
+	t.Run("failure(ErrFailedToDecrypt)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := aesKeyUnwrap(make([]byte, 16), make([]byte, 24)); !errors.Is(err, ErrFailedToDecrypt) {
+			t.Errorf("❌: aesKeyUnwrap: err != ErrFailedToDecrypt: %v", err)
+		}
+		if _, err := aesKeyUnwrap(make([]byte, 16), make([]byte, 12)); !errors.Is(err, ErrFailedToDecrypt) {
+			t.Errorf("❌: aesKeyUnwrap: err != ErrFailedToDecrypt: %v", err)
+		}
+	})
+}
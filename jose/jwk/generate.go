@@ -0,0 +1,128 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // for crypto.SHA256 thumbprint
+	"errors"
+	"fmt"
+
+	"github.com/kunitsucom/util.go/jose/jwa"
+)
+
+var ErrAlgorithmIsNotSupported = errors.New("jwk: algorithm is not supported for key generation")
+
+const defaultRSAKeySize = 2048
+
+// Generate generates a new private (or symmetric) JSON Web Key for alg.
+//
+// The key parameters are derived from kty and alg:
+//
+//	RSA: 2048 bit key for "RS*", "PS*" and "RSA-OAEP*".
+//	EC:  "P-256" for "ES256" and "ECDH-ES*", "P-384" for "ES384", "P-521" for "ES512".
+//	OKP: "Ed25519" for "EdDSA".
+//	oct: key size of "HS*", "A*KW", "A*GCM" and "A*CBC-HS*".
+//
+// "ES256K" is not supported, because the secp256k1 arithmetic in ellipticz is not constant-time. See jwa.RegisterES256K.
+//
+// If "kid" is not specified by opts, the RFC 7638 thumbprint (SHA-256) is used as "kid".
+//
+// Example:
+//
+//	privateKey, err := jwk.Generate("EC", jwa.ES256, jwk.WithPublicKeyUse("sig"))
+//	if err != nil {
+//		return err
+//	}
+//	publicKey := privateKey.Public()
+func Generate(kty, alg string, opts ...JSONWebKeyOption) (*JSONWebKey, error) {
+	opts = append([]JSONWebKeyOption{WithAlgorithm(alg)}, opts...)
+
+	var jwk *JSONWebKey
+	switch kty {
+	case "RSA":
+		switch alg {
+		case jwa.RS256, jwa.RS384, jwa.RS512, jwa.PS256, jwa.PS384, jwa.PS512, jwa.RSAOAEP, jwa.RSAOAEP256:
+		default:
+			return nil, fmt.Errorf("kty=%s alg=%s: %w", kty, alg, ErrAlgorithmIsNotSupported)
+		}
+		key, err := rsa.GenerateKey(rand.Reader, defaultRSAKeySize)
+		if err != nil {
+			return nil, fmt.Errorf("rsa.GenerateKey: %w", err)
+		}
+		jwk = new(JSONWebKey).EncodeRSAPrivateKey(key, opts...)
+	case "EC":
+		crv, err := curveForAlgorithm(alg)
+		if err != nil {
+			return nil, fmt.Errorf("kty=%s: %w", kty, err)
+		}
+		key, err := ecdsa.GenerateKey(crv, rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("ecdsa.GenerateKey: %w", err)
+		}
+		jwk = new(JSONWebKey).EncodeECDSAPrivateKey(key, opts...)
+	case "OKP":
+		if alg != jwa.EdDSA {
+			return nil, fmt.Errorf("kty=%s alg=%s: %w", kty, alg, ErrAlgorithmIsNotSupported)
+		}
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("ed25519.GenerateKey: %w", err)
+		}
+		jwk = new(JSONWebKey).EncodeEd25519PrivateKey(key, opts...)
+	case "oct":
+		size, err := symmetricKeySizeForAlgorithm(alg)
+		if err != nil {
+			return nil, fmt.Errorf("kty=%s: %w", kty, err)
+		}
+		key := make([]byte, size)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("rand.Read: %w", err)
+		}
+		jwk = new(JSONWebKey).EncodeSymmetricKey(key, opts...)
+	default:
+		return nil, fmt.Errorf("kty=%s: %w", kty, ErrKeyIsNotForAlgorithm)
+	}
+
+	if jwk.KeyID == "" {
+		kid, err := jwk.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("(*jwk.JSONWebKey).Thumbprint: %w", err)
+		}
+		jwk.KeyID = kid
+	}
+
+	return jwk, nil
+}
+
+func curveForAlgorithm(alg string) (elliptic.Curve, error) { //nolint:ireturn
+	switch alg {
+	case jwa.ES256, jwa.ECDHES, jwa.ECDHESA128KW, jwa.ECDHESA192KW, jwa.ECDHESA256KW:
+		return elliptic.P256(), nil
+	case jwa.ES384:
+		return elliptic.P384(), nil
+	case jwa.ES512:
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("alg=%s: %w", alg, ErrAlgorithmIsNotSupported)
+}
+
+func symmetricKeySizeForAlgorithm(alg string) (int, error) {
+	const size128, size192, size256, size384, size512 = 16, 24, 32, 48, 64
+	switch alg {
+	case jwa.A128KW, jwa.A128GCM:
+		return size128, nil
+	case jwa.A192KW, jwa.A192GCM:
+		return size192, nil
+	case jwa.HS256, jwa.A256KW, jwa.A256GCM, jwa.A128CBCHS256:
+		return size256, nil
+	case jwa.HS384, jwa.A192CBCHS384:
+		return size384, nil
+	case jwa.HS512, jwa.A256CBCHS512:
+		return size512, nil
+	}
+	return 0, fmt.Errorf("alg=%s: %w", alg, ErrAlgorithmIsNotSupported)
+}
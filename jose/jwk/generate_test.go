@@ -0,0 +1,176 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			kty string
+			alg string
+			crv string
+			k   int
+		}{
+			{"RSA", jwa.RS256, "", 0},
+			{"EC", jwa.ES256, "P-256", 0},
+			{"EC", jwa.ES384, "P-384", 0},
+			{"EC", jwa.ES512, "P-521", 0},
+			{"EC", jwa.ECDHESA128KW, "P-256", 0},
+			{"OKP", jwa.EdDSA, "Ed25519", 0},
+			{"oct", jwa.HS256, "", 32},
+			{"oct", jwa.HS512, "", 64},
+			{"oct", jwa.A128KW, "", 16},
+			{"oct", jwa.A192GCM, "", 24},
+			{"oct", jwa.A256CBCHS512, "", 64},
+		} {
+			k, err := jwk.Generate(tt.kty, tt.alg, jwk.WithPublicKeyUse("sig"))
+			if err != nil {
+				t.Fatalf("❌: jwk.Generate: %s %s: err != nil: %v", tt.kty, tt.alg, err)
+			}
+			if k.KeyType != tt.kty || k.Algorithm != tt.alg || k.Crv != tt.crv || k.PublicKeyUse != "sig" {
+				t.Errorf("❌: jwk.Generate: %s %s: %+v", tt.kty, tt.alg, k)
+			}
+			kid, err := k.Thumbprint(crypto.SHA256)
+			if err != nil {
+				t.Fatalf("❌: (*jwk.JSONWebKey).Thumbprint: err != nil: %v", err)
+			}
+			if k.KeyID != kid {
+				t.Errorf("❌: jwk.Generate: kid: expect(%s) != actual(%s)", kid, k.KeyID)
+			}
+			switch tt.kty {
+			case "RSA":
+				if _, err := k.DecodeRSAPrivateKey(); err != nil {
+					t.Errorf("❌: (*jwk.JSONWebKey).DecodeRSAPrivateKey: err != nil: %v", err)
+				}
+			case "EC":
+				if _, err := k.DecodeECDSAPrivateKey(); err != nil {
+					t.Errorf("❌: (*jwk.JSONWebKey).DecodeECDSAPrivateKey: err != nil: %v", err)
+				}
+			case "OKP":
+				if _, err := k.DecodeEd25519PrivateKey(); err != nil {
+					t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PrivateKey: err != nil: %v", err)
+				}
+			case "oct":
+				key, err := k.DecodeSymmetricKey()
+				if err != nil {
+					t.Errorf("❌: (*jwk.JSONWebKey).DecodeSymmetricKey: err != nil: %v", err)
+				}
+				if len(key) != tt.k {
+					t.Errorf("❌: (*jwk.JSONWebKey).DecodeSymmetricKey: len: expect(%d) != actual(%d)", tt.k, len(key))
+				}
+			}
+		}
+	})
+
+	t.Run("success(WithKeyID)", func(t *testing.T) {
+		t.Parallel()
+		k, err := jwk.Generate("oct", jwa.HS256, jwk.WithKeyID("testKeyID"))
+		if err != nil {
+			t.Fatalf("❌: jwk.Generate: err != nil: %v", err)
+		}
+		if k.KeyID != "testKeyID" {
+			t.Errorf("❌: jwk.Generate: kid: %s", k.KeyID)
+		}
+	})
+
+	t.Run("failure(jwk.ErrAlgorithmIsNotSupported)", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range [][2]string{
+			{"RSA", jwa.ES256},
+			{"EC", jwa.RS256},
+			{"EC", jwa.ES256K},
+			{"OKP", jwa.ES256},
+			{"oct", jwa.Dir},
+		} {
+			if _, err := jwk.Generate(tt[0], tt[1]); !errors.Is(err, jwk.ErrAlgorithmIsNotSupported) {
+				t.Errorf("❌: jwk.Generate: %v: err != jwk.ErrAlgorithmIsNotSupported: %v", tt, err)
+			}
+		}
+	})
+
+	t.Run("failure(jwk.ErrKeyIsNotForAlgorithm)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jwk.Generate("unknown", jwa.HS256); !errors.Is(err, jwk.ErrKeyIsNotForAlgorithm) {
+			t.Errorf("❌: jwk.Generate: err != jwk.ErrKeyIsNotForAlgorithm: %v", err)
+		}
+	})
+}
+
+func TestJSONWebKey_Public(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		for _, kty := range []string{"RSA", "EC", "OKP"} {
+			alg := map[string]string{"RSA": jwa.RS256, "EC": jwa.ES256, "OKP": jwa.EdDSA}[kty]
+			priv, err := jwk.Generate(kty, alg)
+			if err != nil {
+				t.Fatalf("❌: jwk.Generate: err != nil: %v", err)
+			}
+			pub := priv.Public()
+			if pub.IsPrivate() || !priv.IsPrivate() {
+				t.Errorf("❌: (*jwk.JSONWebKey).Public: %+v", pub)
+			}
+			if pub.KeyID != priv.KeyID || pub.Algorithm != priv.Algorithm {
+				t.Errorf("❌: (*jwk.JSONWebKey).Public: %+v", pub)
+			}
+			key, err := pub.DecodePublicKey()
+			if err != nil {
+				t.Fatalf("❌: (*jwk.JSONWebKey).DecodePublicKey: err != nil: %v", err)
+			}
+			switch key.(type) {
+			case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+			default:
+				t.Errorf("❌: (*jwk.JSONWebKey).DecodePublicKey: %T", key)
+			}
+		}
+	})
+
+	t.Run("success(oct)", func(t *testing.T) {
+		t.Parallel()
+		if pub := new(jwk.JSONWebKey).EncodeSymmetricKey([]byte("secret")).Public(); pub != nil {
+			t.Errorf("❌: (*jwk.JSONWebKey).Public: pub != nil: %+v", pub)
+		}
+	})
+}
+
+func TestJSONWebKey_DecodeSymmetricKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		k := new(jwk.JSONWebKey).EncodeSymmetricKey([]byte("secret"), jwk.WithKeyID("testKeyID"))
+		if k.KeyType != "oct" || k.K != "c2VjcmV0" || k.KeyID != "testKeyID" {
+			t.Errorf("❌: (*jwk.JSONWebKey).EncodeSymmetricKey: %+v", k)
+		}
+		key, err := k.DecodeSymmetricKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodeSymmetricKey: err != nil: %v", err)
+		}
+		if string(key) != "secret" {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeSymmetricKey: %s", key)
+		}
+	})
+
+	t.Run("failure()", func(t *testing.T) {
+		t.Parallel()
+		if _, err := (&jwk.JSONWebKey{KeyType: "oct"}).DecodeSymmetricKey(); !errors.Is(err, jwk.ErrInvalidKey) {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeSymmetricKey: err != jwk.ErrInvalidKey: %v", err)
+		}
+		if _, err := (&jwk.JSONWebKey{KeyType: "oct", K: "inv@lid"}).DecodeSymmetricKey(); err == nil {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeSymmetricKey: err == nil")
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rsa"
 	"encoding/base64"
@@ -14,8 +15,12 @@ import (
 	"math"
 	"math/big"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	ellipticz "github.com/kunitsucom/util.go/crypto/elliptic"
 	slicez "github.com/kunitsucom/util.go/slices"
 	syncz "github.com/kunitsucom/util.go/sync"
 )
@@ -127,6 +132,13 @@ type JSONWebKey struct {
 	//	o  "P-521"
 	//
 	//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-6.2.1.1
+	//
+	// For "OKP" (Octet Key Pair) keys, the "crv" parameter contains the
+	// subtype of the key, such as "Ed25519".  "secp256k1" is also accepted
+	// for "EC" keys.
+	//
+	//   - ref. https://www.rfc-editor.org/rfc/rfc8037#section-2
+	//   - ref. https://www.rfc-editor.org/rfc/rfc8812#section-3.1
 	Crv string `json:"crv,omitempty"`
 
 	// X
@@ -142,6 +154,11 @@ type JSONWebKey struct {
 	// string must be 66 octets long.
 	//
 	//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-6.2.1.2
+	//
+	// For "OKP" keys, the "x" parameter contains the base64url encoded
+	// public key.
+	//
+	//   - ref. https://www.rfc-editor.org/rfc/rfc8037#section-2
 	X string `json:"x,omitempty"`
 
 	// Y
@@ -302,6 +319,13 @@ type JSONWebKey struct {
 	//
 	//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-6.3.2.1
 	//
+	// Parameters for Octet Key Pair Private Keys
+	//
+	// The parameter "d" MUST be present for private keys and contains the
+	// private key (for Ed25519, the 32 octets seed).
+	//
+	//   - ref. https://www.rfc-editor.org/rfc/rfc8037#section-2
+	//
 	D string `json:"d,omitempty"`
 
 	//
@@ -380,7 +404,17 @@ func WithAlgorithm(alg string) JSONWebKeyOption {
 	}
 }
 
-// TODO: WithPublicKeyUse() and so on
+func WithPublicKeyUse(use string) JSONWebKeyOption {
+	return func(jwk *JSONWebKey) {
+		jwk.PublicKeyUse = use
+	}
+}
+
+func WithKeyOperations(keyOps ...string) JSONWebKeyOption {
+	return func(jwk *JSONWebKey) {
+		jwk.KeyOperations = keyOps
+	}
+}
 
 func (jwk *JSONWebKey) EncodeRSAPublicKey(key *rsa.PublicKey, opts ...JSONWebKeyOption) *JSONWebKey {
 	if jwk == nil {
@@ -464,12 +498,17 @@ func (jwk *JSONWebKey) EncodeECDSAPublicKey(key *ecdsa.PublicKey, opts ...JSONWe
 	if jwk == nil {
 		jwk = new(JSONWebKey)
 	}
+	jwk.KeyType = "EC"
 	for _, opt := range opts {
 		opt(jwk)
 	}
 	jwk.Crv = key.Params().Name
-	jwk.X = base64.RawURLEncoding.EncodeToString(key.X.Bytes())
-	jwk.Y = base64.RawURLEncoding.EncodeToString(key.Y.Bytes())
+	// The length of x and y MUST be the full size of a coordinate for the curve.
+	//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-6.2.1.2
+	const bitsPerByte = 8
+	size := (key.Params().BitSize + bitsPerByte - 1) / bitsPerByte
+	jwk.X = base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+	jwk.Y = base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
 	return jwk
 }
 
@@ -482,6 +521,8 @@ func (jwk *JSONWebKey) DecodeECDSAPublicKey() (*ecdsa.PublicKey, error) {
 		crv = elliptic.P384()
 	case "P-521":
 		crv = elliptic.P521()
+	case "secp256k1":
+		crv = ellipticz.Secp256k1()
 	default:
 		return nil, fmt.Errorf("crv=%s: %w", jwk.Crv, ErrCurveNotSupported)
 	}
@@ -508,7 +549,11 @@ func (jwk *JSONWebKey) EncodeECDSAPrivateKey(key *ecdsa.PrivateKey, opts ...JSON
 	for _, opt := range opts {
 		opt(jwk)
 	}
-	jwk.D = base64.RawURLEncoding.EncodeToString(key.D.Bytes())
+	// The length of d MUST be ceiling(log-base-2(n)/8) octets.
+	//   - ref. https://www.rfc-editor.org/rfc/rfc7518#section-6.2.2.1
+	const bitsPerByte = 8
+	size := (key.Params().N.BitLen() + bitsPerByte - 1) / bitsPerByte
+	jwk.D = base64.RawURLEncoding.EncodeToString(key.D.FillBytes(make([]byte, size)))
 	return jwk
 }
 
@@ -529,6 +574,117 @@ func (jwk *JSONWebKey) DecodeECDSAPrivateKey() (*ecdsa.PrivateKey, error) {
 	}, nil
 }
 
+func (jwk *JSONWebKey) EncodeEd25519PublicKey(key ed25519.PublicKey, opts ...JSONWebKeyOption) *JSONWebKey {
+	if jwk == nil {
+		jwk = new(JSONWebKey)
+	}
+	jwk.KeyType = "OKP"
+	for _, opt := range opts {
+		opt(jwk)
+	}
+	jwk.Crv = "Ed25519"
+	jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	return jwk
+}
+
+func (jwk *JSONWebKey) DecodeEd25519PublicKey() (ed25519.PublicKey, error) {
+	if jwk.Crv != "Ed25519" {
+		return nil, fmt.Errorf("crv=%s: %w", jwk.Crv, ErrCurveNotSupported)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: JSONWebKey.X=%s: %w", jwk.X, err)
+	}
+	if len(x) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("len(x)=%d: %w", len(x), ErrInvalidKey)
+	}
+
+	return ed25519.PublicKey(x), nil
+}
+
+func (jwk *JSONWebKey) EncodeEd25519PrivateKey(key ed25519.PrivateKey, opts ...JSONWebKeyOption) *JSONWebKey {
+	jwk = jwk.EncodeEd25519PublicKey(key.Public().(ed25519.PublicKey)) //nolint:forcetypeassert
+	for _, opt := range opts {
+		opt(jwk)
+	}
+	jwk.D = base64.RawURLEncoding.EncodeToString(key.Seed())
+	return jwk
+}
+
+func (jwk *JSONWebKey) DecodeEd25519PrivateKey() (ed25519.PrivateKey, error) {
+	pub, err := jwk.DecodeEd25519PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := base64.RawURLEncoding.DecodeString(jwk.D)
+	if err != nil {
+		return nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: JSONWebKey.D: %w", err)
+	}
+	if len(d) != ed25519.SeedSize {
+		return nil, fmt.Errorf("len(d)=%d: %w", len(d), ErrInvalidKey)
+	}
+
+	key := ed25519.NewKeyFromSeed(d)
+	if !pub.Equal(key.Public()) {
+		return nil, fmt.Errorf("x does not match d: %w", ErrInvalidKey)
+	}
+
+	return key, nil
+}
+
+func (jwk *JSONWebKey) EncodeSymmetricKey(key []byte, opts ...JSONWebKeyOption) *JSONWebKey {
+	if jwk == nil {
+		jwk = new(JSONWebKey)
+	}
+	jwk.KeyType = "oct"
+	for _, opt := range opts {
+		opt(jwk)
+	}
+	jwk.K = base64.RawURLEncoding.EncodeToString(key)
+	return jwk
+}
+
+func (jwk *JSONWebKey) DecodeSymmetricKey() ([]byte, error) {
+	if jwk.K == "" {
+		return nil, fmt.Errorf("k is empty: %w", ErrInvalidKey)
+	}
+
+	k, err := base64.RawURLEncoding.DecodeString(jwk.K)
+	if err != nil {
+		return nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: JSONWebKey.K: %w", err)
+	}
+
+	return k, nil
+}
+
+// Public returns a copy of the JSON Web Key without private (and symmetric) key parameters.
+// For "oct" keys, which have no public part, Public returns nil.
+func (jwk *JSONWebKey) Public() *JSONWebKey {
+	if jwk.KeyType == "oct" {
+		return nil
+	}
+
+	pub := *jwk
+	pub.KeyOperations = append([]string(nil), jwk.KeyOperations...)
+	pub.X509CertificateChain = append([]string(nil), jwk.X509CertificateChain...)
+	pub.D = ""
+	pub.P = ""
+	pub.Q = ""
+	pub.DP = ""
+	pub.DQ = ""
+	pub.QI = ""
+	pub.Oth = nil
+	pub.K = ""
+	return &pub
+}
+
+// IsPrivate reports whether the JSON Web Key contains private (or symmetric) key parameters.
+func (jwk *JSONWebKey) IsPrivate() bool {
+	return jwk.D != "" || jwk.K != ""
+}
+
 func (jwk *JSONWebKey) DecodePublicKey() (crypto.PublicKey, error) {
 	switch jwk.KeyType {
 	case "RSA":
@@ -543,22 +699,89 @@ func (jwk *JSONWebKey) DecodePublicKey() (crypto.PublicKey, error) {
 			return nil, fmt.Errorf("jwk.DecodeRSAPublicKey: %w", err)
 		}
 		return key, nil
+	case "OKP":
+		key, err := jwk.DecodeEd25519PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk.DecodeEd25519PublicKey: %w", err)
+		}
+		return key, nil
 	}
 
 	return nil, fmt.Errorf("kty=%s: %w", jwk.KeyType, ErrKeyIsNotForAlgorithm)
 }
 
-// TODO: implement
-// func (jwk *JSONWebKey) DecodePrivateKey(alg string) (crypto.PrivateKey, error) {
-// }
+// DecodePrivateKey decodes the private key of the JSON Web Key.
+// For "oct", the symmetric key is returned as []byte.
+func (jwk *JSONWebKey) DecodePrivateKey() (crypto.PrivateKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		key, err := jwk.DecodeRSAPrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk.DecodeRSAPrivateKey: %w", err)
+		}
+		return key, nil
+	case "EC":
+		key, err := jwk.DecodeECDSAPrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk.DecodeECDSAPrivateKey: %w", err)
+		}
+		return key, nil
+	case "OKP":
+		key, err := jwk.DecodeEd25519PrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk.DecodeEd25519PrivateKey: %w", err)
+		}
+		return key, nil
+	case "oct":
+		key, err := jwk.DecodeSymmetricKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwk.DecodeSymmetricKey: %w", err)
+		}
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("kty=%s: %w", jwk.KeyType, ErrKeyIsNotForAlgorithm)
+}
 
 type Client struct { //nolint:revive
 	client   *http.Client
 	cacheMap syncz.Map[*JWKSet]
+
+	defaultTTL                time.Duration
+	minTTL                    time.Duration
+	maxTTL                    time.Duration
+	refetchInterval           time.Duration
+	backgroundRefreshInterval time.Duration
+	maxEntries                int
+
+	mu      sync.Mutex
+	entries map[JWKSetURL]*jwksEntry
+}
+
+// jwksEntry holds the last known good JWK Set of a JWK Set URL.
+// It is kept after the cache in cacheMap expires, for revalidation with ETag and for serving stale JWK Set when the server is unavailable,
+// until it is evicted by the background refresher or by exceeding the max entries.
+type jwksEntry struct {
+	// usedAt is guarded by Client.mu, and the others are guarded by mu.
+	usedAt time.Time
+
+	mu        sync.Mutex
+	jwks      *JWKSet
+	etag      string
+	checkedAt time.Time
+	expiresAt time.Time
 }
 
 func NewClient(ctx context.Context, opts ...ClientOption) *Client {
-	const defaultTTL = 10 * time.Minute
+	const (
+		defaultTTL             = 10 * time.Minute
+		defaultMinTTL          = 1 * time.Minute
+		defaultMaxTTL          = 24 * time.Hour
+		defaultRefetchInterval = 1 * time.Minute
+		defaultMaxEntries      = 100
+
+		defaultBackgroundRefreshInterval = 1 * time.Minute
+	)
 	d := &Client{
 		client: &http.Client{
 			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
@@ -566,13 +789,23 @@ func NewClient(ctx context.Context, opts ...ClientOption) *Client {
 				return http.ErrUseLastResponse
 			},
 		},
-		cacheMap: syncz.NewMap[*JWKSet](ctx, syncz.WithNewMapOptionTTL(defaultTTL)),
+		cacheMap:        syncz.NewMap[*JWKSet](ctx, syncz.WithNewMapOptionTTL(defaultTTL)),
+		defaultTTL:      defaultTTL,
+		minTTL:          defaultMinTTL,
+		maxTTL:          defaultMaxTTL,
+		refetchInterval: defaultRefetchInterval,
+		maxEntries:      defaultMaxEntries,
+		entries:         make(map[JWKSetURL]*jwksEntry),
+
+		backgroundRefreshInterval: defaultBackgroundRefreshInterval,
 	}
 
 	for _, opt := range opts {
 		opt(d)
 	}
 
+	d.backgroundRefresher(ctx)
+
 	return d
 }
 
@@ -590,22 +823,198 @@ func WithCacheMap(cacheMap syncz.Map[*JWKSet]) ClientOption {
 	}
 }
 
+// WithDefaultTTL sets the TTL of the cache used when the response has no Cache-Control max-age directive.
+func WithDefaultTTL(ttl time.Duration) ClientOption {
+	return func(d *Client) {
+		d.defaultTTL = ttl
+	}
+}
+
+// WithTTLBounds sets the lower and upper bounds of the TTL of the cache derived from Cache-Control max-age directive.
+func WithTTLBounds(minTTL, maxTTL time.Duration) ClientOption {
+	return func(d *Client) {
+		d.minTTL = minTTL
+		d.maxTTL = maxTTL
+	}
+}
+
+// WithRefetchInterval sets the minimum interval between refetches of a JWK Set URL
+// triggered by unknown kid, or retried while the server is unavailable.
+func WithRefetchInterval(interval time.Duration) ClientOption {
+	return func(d *Client) {
+		d.refetchInterval = interval
+	}
+}
+
+// WithBackgroundRefresh sets the interval of the background refresher, which runs until ctx of NewClient is done. Default is 1 minute.
+// It refreshes JWK Sets that will expire before the next tick and have been used since the last fetch,
+// and evicts JWK Sets that have expired without being used.
+// If interval is 0 or less, background refresh is disabled.
+func WithBackgroundRefresh(interval time.Duration) ClientOption {
+	return func(d *Client) {
+		d.backgroundRefreshInterval = interval
+	}
+}
+
+// WithoutBackgroundRefresh disables the background refresher.
+// JWK Sets are fetched when they are requested after the cache expires.
+func WithoutBackgroundRefresh() ClientOption {
+	return WithBackgroundRefresh(0)
+}
+
+// WithMaxEntries sets the max number of JWK Set URLs whose last known good JWK Set is kept. Default is 100.
+// When it is exceeded, the expired ones, or the least recently used one if none has expired, are evicted,
+// so that jku from untrusted tokens cannot grow the entries without limit.
+func WithMaxEntries(maxEntries int) ClientOption {
+	return func(d *Client) {
+		d.maxEntries = maxEntries
+	}
+}
+
+// GetJWKSet returns the JWK Set of jwksURL.
+//
+// The JWK Set is cached for the duration of Cache-Control max-age directive (or the default TTL),
+// and revalidated with If-None-Match when the response had ETag.
+// If the server is unavailable, the last known good JWK Set is returned.
 func (d *Client) GetJWKSet(ctx context.Context, jwksURL JWKSetURL) (*JWKSet, error) {
 	if jwks, ok := d.cacheMap.Load(jwksURL); ok {
+		d.touch(jwksURL)
+		return jwks, nil
+	}
+
+	return d.refresh(ctx, jwksURL, false)
+}
+
+// GetJSONWebKey returns the JSON Web Key of kid in the JWK Set of jwksURL.
+//
+// If kid is not found in the cached JWK Set, the JWK Set is refetched once (at most once per refetch interval)
+// for following key rotation.
+func (d *Client) GetJSONWebKey(ctx context.Context, jwksURL JWKSetURL, kid string) (*JSONWebKey, error) {
+	jwks, err := d.GetJWKSet(ctx, jwksURL)
+	if err != nil {
+		return nil, fmt.Errorf("(*jwk.Client).GetJWKSet: %w", err)
+	}
+
+	if key, err := jwks.GetJSONWebKey(kid); err == nil {
+		return key, nil
+	}
+
+	jwks, err = d.refresh(ctx, jwksURL, true)
+	if err != nil {
+		return nil, fmt.Errorf("(*jwk.Client).refresh: %w", err)
+	}
+
+	return jwks.GetJSONWebKey(kid)
+}
+
+func (d *Client) entry(jwksURL JWKSetURL) *jwksEntry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	e, ok := d.entries[jwksURL]
+	if !ok {
+		if d.maxEntries > 0 && len(d.entries) >= d.maxEntries {
+			d.evict(now, true)
+		}
+		e = new(jwksEntry)
+		d.entries[jwksURL] = e
+	}
+	e.usedAt = now
+	return e
+}
+
+// touch marks the entry of jwksURL as used.
+func (d *Client) touch(jwksURL JWKSetURL) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if e, ok := d.entries[jwksURL]; ok {
+		e.usedAt = time.Now()
+	}
+}
+
+// evict removes the entries whose JWK Set has expired without being used since the last fetch, or has never been fetched successfully.
+// If lru is true and no entry is removed, the least recently used entry is removed.
+// The entries which are being fetched are skipped. d.mu must be held.
+func (d *Client) evict(now time.Time, lru bool) {
+	var (
+		evicted   bool
+		lruURL    JWKSetURL
+		lruUsedAt time.Time
+	)
+	for jwksURL, e := range d.entries {
+		if !e.mu.TryLock() {
+			continue
+		}
+		// NOTE: the expired JWK Set which has been used since the last fetch is kept for the background refresher,
+		// even if the tick is delayed after its expiration.
+		expired := e.jwks == nil || (now.After(e.expiresAt) && !e.usedAt.After(e.checkedAt))
+		e.mu.Unlock()
+
+		if expired {
+			delete(d.entries, jwksURL)
+			evicted = true
+			continue
+		}
+		if lruUsedAt.IsZero() || e.usedAt.Before(lruUsedAt) {
+			lruURL, lruUsedAt = jwksURL, e.usedAt
+		}
+	}
+
+	if lru && !evicted && !lruUsedAt.IsZero() {
+		delete(d.entries, lruURL)
+	}
+}
+
+func (d *Client) refresh(ctx context.Context, jwksURL JWKSetURL, force bool) (*JWKSet, error) {
+	e := d.entry(jwksURL)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if force {
+		if e.jwks != nil && time.Since(e.checkedAt) < d.refetchInterval {
+			return e.jwks, nil
+		}
+	} else if jwks, ok := d.cacheMap.Load(jwksURL); ok {
+		// refreshed by another goroutine while waiting for the lock
 		return jwks, nil
 	}
 
+	jwks, err := d.fetch(ctx, jwksURL, e)
+	if err != nil {
+		if e.jwks == nil {
+			return nil, err
+		}
+		// serve stale JWK Set, and retry after the refetch interval
+		d.cacheMap.StoreTTL(jwksURL, e.jwks, d.refetchInterval)
+		return e.jwks, nil
+	}
+
+	return jwks, nil
+}
+
+func (d *Client) fetch(ctx context.Context, jwksURL JWKSetURL, e *jwksEntry) (*JWKSet, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("http.NewRequestWithContext: %w", err)
 	}
+	if e.jwks != nil && e.etag != "" {
+		req.Header.Set("If-None-Match", e.etag)
+	}
 
+	e.checkedAt = time.Now()
 	resp, err := d.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("(*http.Client).Do: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && e.jwks != nil {
+		d.store(jwksURL, e, e.jwks, resp.Header)
+		return e.jwks, nil
+	}
+
 	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
 		body, _ := io.ReadAll(resp.Body)
 		const cutOffSize = 100
@@ -618,12 +1027,93 @@ func (d *Client) GetJWKSet(ctx context.Context, jwksURL JWKSetURL) (*JWKSet, err
 		return nil, fmt.Errorf("(*json.Decoder).Decode(*discovery.JWKSet): %w", err)
 	}
 
-	d.cacheMap.Store(jwksURL, r)
+	e.etag = resp.Header.Get("ETag")
+	d.store(jwksURL, e, r, resp.Header)
 	return r, nil
 }
 
+func (d *Client) store(jwksURL JWKSetURL, e *jwksEntry, jwks *JWKSet, header http.Header) {
+	ttl := d.ttl(header)
+	e.jwks = jwks
+	e.expiresAt = time.Now().Add(ttl)
+	d.cacheMap.StoreTTL(jwksURL, jwks, ttl)
+}
+
+// ttl returns the TTL of the cache derived from Cache-Control header.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9111#section-5.2.2
+func (d *Client) ttl(header http.Header) time.Duration {
+	ttl := d.defaultTTL
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store", directive == "no-cache":
+			return d.minTTL
+		case strings.HasPrefix(directive, "max-age="):
+			maxAge, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(directive, "max-age="), `"`), 10, 64)
+			if err != nil || maxAge < 0 {
+				continue
+			}
+			ttl = time.Duration(maxAge) * time.Second
+		}
+	}
+
+	if ttl < d.minTTL {
+		return d.minTTL
+	}
+	if d.maxTTL > 0 && ttl > d.maxTTL {
+		return d.maxTTL
+	}
+	return ttl
+}
+
+func (d *Client) backgroundRefresher(ctx context.Context) {
+	if d.backgroundRefreshInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(d.backgroundRefreshInterval)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				d.refreshExpiring(ctx)
+			}
+		}
+	}()
+}
+
+// refreshExpiring evicts JWK Sets that have expired, and refreshes JWK Sets that will expire before the next tick
+// of the background refresher and have been used since the last fetch.
+// The JWK Sets that are not used are not refreshed, so that they expire and are evicted at the following ticks.
+func (d *Client) refreshExpiring(ctx context.Context) {
+	now := time.Now()
+
+	d.mu.Lock()
+	d.evict(now, false)
+	entries := make(map[JWKSetURL]*jwksEntry, len(d.entries))
+	usedAt := make(map[JWKSetURL]time.Time, len(d.entries))
+	for jwksURL, e := range d.entries {
+		entries[jwksURL], usedAt[jwksURL] = e, e.usedAt
+	}
+	d.mu.Unlock()
+
+	deadline := now.Add(d.backgroundRefreshInterval)
+	for jwksURL, e := range entries {
+		e.mu.Lock()
+		if e.jwks != nil && e.expiresAt.Before(deadline) && usedAt[jwksURL].After(e.checkedAt) {
+			_, _ = d.fetch(ctx, jwksURL, e) // on failure, keep the current JWK Set until it expires
+		}
+		e.mu.Unlock()
+	}
+}
+
 //nolint:gochecknoglobals
 var (
+	// Default is the Client used by GetJWKSet and GetJSONWebKey, and by jws.UseJWKSetURL.
+	// It refreshes the JWK Sets in use in background at the default interval.
 	Default = NewClient(context.Background())
 )
 
@@ -631,6 +1121,10 @@ func GetJWKSet(ctx context.Context, jwksURL JWKSetURL) (*JWKSet, error) {
 	return Default.GetJWKSet(ctx, jwksURL)
 }
 
+func GetJSONWebKey(ctx context.Context, jwksURL JWKSetURL, kid string) (*JSONWebKey, error) {
+	return Default.GetJSONWebKey(ctx, jwksURL, kid)
+}
+
 var ErrKidNotFound = errors.New("jwk: kid not found in jwks")
 
 func (jwks *JWKSet) GetJSONWebKey(kid string) (*JSONWebKey, error) {
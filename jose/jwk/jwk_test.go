@@ -4,17 +4,23 @@ import (
 	"bytes"
 	"context"
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	ellipticz "github.com/kunitsucom/util.go/crypto/elliptic"
 	x509z "github.com/kunitsucom/util.go/crypto/x509"
 	errorz "github.com/kunitsucom/util.go/errors"
 	"github.com/kunitsucom/util.go/jose/jwk"
@@ -166,10 +172,11 @@ func TestJSONWebKey_DecodeECDSAPublicKey(t *testing.T) {
 		if err != nil {
 			t.Fatalf("❌: (*jwk.JSONWebKey).DecodeECDSAPublicKey: err != nil: %v", err)
 		}
-		if expect, actual := k1.Y, base64.RawURLEncoding.EncodeToString(k2.Y.Bytes()); expect != actual {
+		const keySize = 66
+		if expect, actual := k1.Y, base64.RawURLEncoding.EncodeToString(k2.Y.FillBytes(make([]byte, keySize))); expect != actual {
 			t.Errorf("❌: (*jwk.JSONWebKey).DecodeECDSAPublicKey: Y: %v", actual)
 		}
-		if expect, actual := k1.X, base64.RawURLEncoding.EncodeToString(k2.X.Bytes()); expect != actual {
+		if expect, actual := k1.X, base64.RawURLEncoding.EncodeToString(k2.X.FillBytes(make([]byte, keySize))); expect != actual {
 			t.Errorf("❌: (*jwk.JSONWebKey).DecodeECDSAPublicKey: X: %v", actual)
 		}
 	})
@@ -209,6 +216,27 @@ func TestJSONWebKey_DecodeECDSAPrivateKey(t *testing.T) {
 			t.Errorf("❌: (*jwk.JSONWebKey).DecodeECDSAPrivateKey: D: %v", actual)
 		}
 	})
+	t.Run("success(shortD)", func(t *testing.T) {
+		t.Parallel()
+		crv := elliptic.P256()
+		key := &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: crv, X: crv.Params().Gx, Y: crv.Params().Gy}, D: big.NewInt(1)}
+		k1 := new(jwk.JSONWebKey).EncodeECDSAPrivateKey(key)
+		d, err := base64.RawURLEncoding.DecodeString(k1.D)
+		if err != nil {
+			t.Fatalf("❌: base64.RawURLEncoding.DecodeString: err != nil: %v", err)
+		}
+		const keySize = 32
+		if expect, actual := keySize, len(d); expect != actual {
+			t.Errorf("❌: (*jwk.JSONWebKey).EncodeECDSAPrivateKey: len(D): expect(%d) != actual(%d)", expect, actual)
+		}
+		k2, err := k1.DecodeECDSAPrivateKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodeECDSAPrivateKey: err != nil: %v", err)
+		}
+		if k2.D.Cmp(key.D) != 0 {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeECDSAPrivateKey: D: %v", k2.D)
+		}
+	})
 	t.Run("failure()", func(t *testing.T) {
 		t.Parallel()
 		k1 := new(jwk.JSONWebKey).EncodeECDSAPrivateKey(
@@ -225,6 +253,90 @@ func TestJSONWebKey_DecodeECDSAPrivateKey(t *testing.T) {
 	})
 }
 
+func TestJSONWebKey_DecodeEd25519PublicKey(t *testing.T) {
+	t.Parallel()
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc8037#appendix-A.2
+		const expectX = "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+		k1 := new(jwk.JSONWebKey).EncodeEd25519PublicKey(
+			must.One(x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestEd25519PublicKeyPEM))),
+			jwk.WithKeyID("testKeyID"),
+		)
+		if k1.KeyType != "OKP" || k1.Crv != "Ed25519" || k1.X != expectX {
+			t.Fatalf("❌: (*jwk.JSONWebKey).EncodeEd25519PublicKey: %+v", k1)
+		}
+		k2, err := k1.DecodeEd25519PublicKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodeEd25519PublicKey: err != nil: %v", err)
+		}
+		if expect, actual := k1.X, base64.RawURLEncoding.EncodeToString(k2); expect != actual {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PublicKey: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+	t.Run("failure()", func(t *testing.T) {
+		t.Parallel()
+		k1 := &jwk.JSONWebKey{KeyType: "OKP", Crv: "X25519"}
+		if _, err := k1.DecodeEd25519PublicKey(); !errors.Is(err, jwk.ErrCurveNotSupported) {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PublicKey: err != jwk.ErrCurveNotSupported: %v", err)
+		}
+		k1.Crv = "Ed25519"
+		k1.X = "inv@lid"
+		if _, err := k1.DecodeEd25519PublicKey(); !errorz.Contains(err, "base64.RawURLEncoding.DecodeString: JSONWebKey.X=inv@lid: illegal base64 data at input byte 3") {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PublicKey: err != nil: %v", err)
+		}
+		k1.X = "AAAA"
+		if _, err := k1.DecodeEd25519PublicKey(); !errors.Is(err, jwk.ErrInvalidKey) {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PublicKey: err != jwk.ErrInvalidKey: %v", err)
+		}
+	})
+}
+
+func TestJSONWebKey_DecodeEd25519PrivateKey(t *testing.T) {
+	t.Parallel()
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc8037#appendix-A.1
+		const expectD = "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"
+		k1 := new(jwk.JSONWebKey).EncodeEd25519PrivateKey(
+			must.One(x509z.ParseEd25519PrivateKeyPEM([]byte(testingz.TestEd25519PrivateKeyPEM))),
+			jwk.WithKeyID("testKeyID"),
+		)
+		if k1.D != expectD {
+			t.Fatalf("❌: (*jwk.JSONWebKey).EncodeEd25519PrivateKey: D: expect(%s) != actual(%s)", expectD, k1.D)
+		}
+		k2, err := k1.DecodeEd25519PrivateKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodeEd25519PrivateKey: err != nil: %v", err)
+		}
+		if expect, actual := k1.D, base64.RawURLEncoding.EncodeToString(k2.Seed()); expect != actual {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PrivateKey: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+	t.Run("failure()", func(t *testing.T) {
+		t.Parallel()
+		k1 := new(jwk.JSONWebKey).EncodeEd25519PrivateKey(
+			must.One(x509z.ParseEd25519PrivateKeyPEM([]byte(testingz.TestEd25519PrivateKeyPEM))),
+		)
+		k1.D = "inv@lid"
+		if _, err := k1.DecodeEd25519PrivateKey(); !errorz.Contains(err, "base64.RawURLEncoding.DecodeString: JSONWebKey.D: illegal base64 data at input byte 3") {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PrivateKey: err != nil: %v", err)
+		}
+		k1.D = "AAAA"
+		if _, err := k1.DecodeEd25519PrivateKey(); !errors.Is(err, jwk.ErrInvalidKey) {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PrivateKey: err != jwk.ErrInvalidKey: %v", err)
+		}
+		k1.D = base64.RawURLEncoding.EncodeToString(make([]byte, 32))
+		if _, err := k1.DecodeEd25519PrivateKey(); !errors.Is(err, jwk.ErrInvalidKey) {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PrivateKey: err != jwk.ErrInvalidKey: %v", err)
+		}
+		k1.Crv = "invalid"
+		if _, err := k1.DecodeEd25519PrivateKey(); !errors.Is(err, jwk.ErrCurveNotSupported) {
+			t.Errorf("❌: (*jwk.JSONWebKey).DecodeEd25519PrivateKey: err != jwk.ErrCurveNotSupported: %v", err)
+		}
+	})
+}
+
 func TestJSONWebKey_DecodePublicKey(t *testing.T) {
 	t.Parallel()
 	t.Run("success(RSA)", func(t *testing.T) {
@@ -269,6 +381,51 @@ func TestJSONWebKey_DecodePublicKey(t *testing.T) {
 			t.Fatalf("❌: (*jwk.JSONWebKey).DecodePublicKey: N: %s", pub1.X)
 		}
 	})
+	t.Run("success(OKP)", func(t *testing.T) {
+		t.Parallel()
+		var k0 *jwk.JSONWebKey
+		okp1 := k0.EncodeEd25519PublicKey(
+			must.One(x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestEd25519PublicKeyPEM))),
+			jwk.WithKeyID("testKeyID"),
+		)
+		key1, err := okp1.DecodePublicKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodePublicKey: err != nil: %v", err)
+		}
+		pub1, ok := (key1).(ed25519.PublicKey)
+		if !ok {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodePublicKey: ed25519.PublicKey: !ok")
+		}
+		if okp1.X != base64.RawURLEncoding.EncodeToString(pub1) {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodePublicKey: X: %x", pub1)
+		}
+	})
+	t.Run("success(EC,secp256k1)", func(t *testing.T) {
+		t.Parallel()
+		priv := must.One(ecdsa.GenerateKey(ellipticz.Secp256k1(), rand.Reader))
+		ec1 := new(jwk.JSONWebKey).EncodeECDSAPublicKey(&priv.PublicKey)
+		if ec1.Crv != "secp256k1" {
+			t.Fatalf("❌: (*jwk.JSONWebKey).EncodeECDSAPublicKey: crv: %s", ec1.Crv)
+		}
+		key1, err := ec1.DecodePublicKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodePublicKey: err != nil: %v", err)
+		}
+		if !priv.PublicKey.Equal(key1) {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodePublicKey: key mismatch")
+		}
+	})
+	t.Run("failure,jwk.DecodeEd25519PublicKey", func(t *testing.T) {
+		t.Parallel()
+		okp1 := &jwk.JSONWebKey{
+			KeyType: "OKP",
+			Crv:     "ERROR",
+		}
+		const expect = `jwk.DecodeEd25519PublicKey: crv=ERROR: jwk: specified curve parameter is not supported`
+		if _, err := okp1.DecodePublicKey(); !errorz.Contains(err, expect) {
+			t.Fatalf("❌: (*jwk.JSONWebKey).DecodePublicKey: expect(%v) != actual(%s)", expect, err)
+		}
+	})
 	t.Run("failure,jwk.DecodeRSAPublicKey", func(t *testing.T) {
 		t.Parallel()
 		rsa1 := &jwk.JSONWebKey{
@@ -498,3 +655,233 @@ func TestGetJSONWebKey(t *testing.T) {
 		}
 	})
 }
+
+type testJWKSServer struct {
+	mu       sync.Mutex
+	kid      string
+	etag     string
+	cache    string
+	down     bool
+	requests int
+	notMod   int
+}
+
+func (s *testJWKSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if s.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if s.cache != "" {
+		w.Header().Set("Cache-Control", s.cache)
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+		if r.Header.Get("If-None-Match") == s.etag {
+			s.notMod++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	_, _ = fmt.Fprintf(w, `{"keys":[{"kty":"oct","kid":%q,"k":"c2VjcmV0"}]}`, s.kid)
+}
+
+func (s *testJWKSServer) set(f func(s *testJWKSServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+func (s *testJWKSServer) count() (requests, notModified int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests, s.notMod
+}
+
+func newTestJWKSServer(t *testing.T, kid string) (*testJWKSServer, string) {
+	t.Helper()
+	h := &testJWKSServer{kid: kid}
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+	return h, s.URL
+}
+
+func TestClient_GetJWKSet_cache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(Cache-Control,ETag)", func(t *testing.T) {
+		t.Parallel()
+		h, jwksURL := newTestJWKSServer(t, "kid1")
+		h.set(func(s *testJWKSServer) { s.cache, s.etag = "public, max-age=0", `"v1"` })
+		c := jwk.NewClient(context.Background(), jwk.WithTTLBounds(0, time.Hour))
+
+		for range 2 {
+			if _, err := c.GetJWKSet(context.Background(), jwksURL); err != nil {
+				t.Fatalf("❌: (*jwk.Client).GetJWKSet: err != nil: %v", err)
+			}
+		}
+		if requests, notModified := h.count(); requests != 2 || notModified != 1 {
+			t.Errorf("❌: (*jwk.Client).GetJWKSet: requests=%d notModified=%d", requests, notModified)
+		}
+
+		h.set(func(s *testJWKSServer) { s.cache = "max-age=3600" })
+		for range 2 {
+			if _, err := c.GetJWKSet(context.Background(), jwksURL); err != nil {
+				t.Fatalf("❌: (*jwk.Client).GetJWKSet: err != nil: %v", err)
+			}
+		}
+		if requests, _ := h.count(); requests != 3 {
+			t.Errorf("❌: (*jwk.Client).GetJWKSet: requests != 3: %d", requests)
+		}
+	})
+
+	t.Run("success(stale)", func(t *testing.T) {
+		t.Parallel()
+		h, jwksURL := newTestJWKSServer(t, "kid1")
+		h.set(func(s *testJWKSServer) { s.cache = "no-cache" })
+		c := jwk.NewClient(context.Background(), jwk.WithTTLBounds(0, time.Hour), jwk.WithRefetchInterval(time.Hour))
+
+		if _, err := c.GetJWKSet(context.Background(), jwksURL); err != nil {
+			t.Fatalf("❌: (*jwk.Client).GetJWKSet: err != nil: %v", err)
+		}
+		h.set(func(s *testJWKSServer) { s.down = true })
+		for range 2 {
+			jwks, err := c.GetJWKSet(context.Background(), jwksURL)
+			if err != nil {
+				t.Fatalf("❌: (*jwk.Client).GetJWKSet: err != nil: %v", err)
+			}
+			if _, err := jwks.GetJSONWebKey("kid1"); err != nil {
+				t.Errorf("❌: (*jwk.JWKSet).GetJSONWebKey: err != nil: %v", err)
+			}
+		}
+		if requests, _ := h.count(); requests != 2 {
+			t.Errorf("❌: (*jwk.Client).GetJWKSet: requests != 2: %d", requests)
+		}
+	})
+
+	t.Run("success(WithBackgroundRefresh)", func(t *testing.T) {
+		t.Parallel()
+		h, jwksURL := newTestJWKSServer(t, "kid1")
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		c := jwk.NewClient(ctx, jwk.WithDefaultTTL(50*time.Millisecond), jwk.WithTTLBounds(0, time.Hour), jwk.WithBackgroundRefresh(10*time.Millisecond))
+
+		// NOTE: the second call is a cache hit, which marks the JWK Set as used since the last fetch.
+		for range 2 {
+			if _, err := c.GetJWKSet(context.Background(), jwksURL); err != nil {
+				t.Fatalf("❌: (*jwk.Client).GetJWKSet: err != nil: %v", err)
+			}
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if requests, _ := h.count(); requests >= 2 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("❌: background refresh did not run")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// NOTE: the JWK Set which is not used is not refreshed anymore.
+		time.Sleep(200 * time.Millisecond)
+		if requests, _ := h.count(); requests != 2 {
+			t.Errorf("❌: unused JWK Set is refreshed: requests=%d", requests)
+		}
+	})
+
+	t.Run("success(WithoutBackgroundRefresh)", func(t *testing.T) {
+		t.Parallel()
+		h, jwksURL := newTestJWKSServer(t, "kid1")
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		c := jwk.NewClient(ctx, jwk.WithDefaultTTL(50*time.Millisecond), jwk.WithTTLBounds(0, time.Hour), jwk.WithBackgroundRefresh(10*time.Millisecond), jwk.WithoutBackgroundRefresh())
+
+		for range 2 {
+			if _, err := c.GetJWKSet(context.Background(), jwksURL); err != nil {
+				t.Fatalf("❌: (*jwk.Client).GetJWKSet: err != nil: %v", err)
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+		if requests, _ := h.count(); requests != 1 {
+			t.Errorf("❌: JWK Set is refreshed in background: requests=%d", requests)
+		}
+	})
+
+	t.Run("success(WithMaxEntries)", func(t *testing.T) {
+		t.Parallel()
+		h1, jwksURL1 := newTestJWKSServer(t, "kid1")
+		h1.set(func(s *testJWKSServer) { s.cache, s.etag = "public, max-age=0", `"v1"` })
+		_, jwksURL2 := newTestJWKSServer(t, "kid2")
+		c := jwk.NewClient(context.Background(), jwk.WithTTLBounds(0, time.Hour), jwk.WithMaxEntries(1))
+
+		for _, jwksURL := range []string{jwksURL1, jwksURL2, jwksURL1} {
+			if _, err := c.GetJWKSet(context.Background(), jwksURL); err != nil {
+				t.Fatalf("❌: (*jwk.Client).GetJWKSet: err != nil: %v", err)
+			}
+		}
+		// NOTE: the entry of jwksURL1 is evicted by jwksURL2, so ETag is not sent.
+		if requests, notModified := h1.count(); requests != 2 || notModified != 0 {
+			t.Errorf("❌: (*jwk.Client).GetJWKSet: requests=%d notModified=%d", requests, notModified)
+		}
+	})
+
+	t.Run("failure(jwk.ErrResponseIsNotCacheable)", func(t *testing.T) {
+		t.Parallel()
+		h, jwksURL := newTestJWKSServer(t, "kid1")
+		h.set(func(s *testJWKSServer) { s.down = true })
+		c := jwk.NewClient(context.Background())
+		if _, err := c.GetJWKSet(context.Background(), jwksURL); !errors.Is(err, jwk.ErrResponseIsNotCacheable) {
+			t.Errorf("❌: (*jwk.Client).GetJWKSet: err != jwk.ErrResponseIsNotCacheable: %v", err)
+		}
+	})
+}
+
+func TestClient_GetJSONWebKey_rotation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(refetch)", func(t *testing.T) {
+		t.Parallel()
+		h, jwksURL := newTestJWKSServer(t, "kid1")
+		c := jwk.NewClient(context.Background(), jwk.WithRefetchInterval(0))
+		if _, err := c.GetJSONWebKey(context.Background(), jwksURL, "kid1"); err != nil {
+			t.Fatalf("❌: (*jwk.Client).GetJSONWebKey: err != nil: %v", err)
+		}
+		h.set(func(s *testJWKSServer) { s.kid = "kid2" })
+		if _, err := c.GetJSONWebKey(context.Background(), jwksURL, "kid2"); err != nil {
+			t.Fatalf("❌: (*jwk.Client).GetJSONWebKey: err != nil: %v", err)
+		}
+		if requests, _ := h.count(); requests != 2 {
+			t.Errorf("❌: (*jwk.Client).GetJSONWebKey: requests != 2: %d", requests)
+		}
+	})
+
+	t.Run("failure(jwk.ErrKidNotFound)", func(t *testing.T) {
+		t.Parallel()
+		h, jwksURL := newTestJWKSServer(t, "kid1")
+		c := jwk.NewClient(context.Background(), jwk.WithRefetchInterval(time.Hour))
+		if _, err := c.GetJWKSet(context.Background(), jwksURL); err != nil {
+			t.Fatalf("❌: (*jwk.Client).GetJWKSet: err != nil: %v", err)
+		}
+		for range 3 {
+			if _, err := c.GetJSONWebKey(context.Background(), jwksURL, "unknown"); !errors.Is(err, jwk.ErrKidNotFound) {
+				t.Errorf("❌: (*jwk.Client).GetJSONWebKey: err != jwk.ErrKidNotFound: %v", err)
+			}
+		}
+		if requests, _ := h.count(); requests != 1 {
+			t.Errorf("❌: (*jwk.Client).GetJSONWebKey: refetch is not rate-limited: requests=%d", requests)
+		}
+	})
+
+	t.Run("failure(ctx)", func(t *testing.T) {
+		t.Parallel()
+		c := jwk.NewClient(context.Background())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := c.GetJSONWebKey(ctx, "http://127.0.0.1:0/certs", "kid1"); !errors.Is(err, context.Canceled) {
+			t.Errorf("❌: (*jwk.Client).GetJSONWebKey: err != context.Canceled: %v", err)
+		}
+	})
+}
@@ -0,0 +1,272 @@
+package jwk
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// KeyManager holds the signing keys of a token issuer and rotates them.
+//
+// KeyManager holds three kinds of keys:
+//
+//   - active key: the key to sign with.
+//   - next key: the key which will be active after the next rotation. It is published in advance, so that verifiers which cache the JWK Set can verify tokens signed after the rotation.
+//   - retired keys: the keys which were active before. They are published during the retention period, so that tokens signed before the rotation can still be verified.
+//
+// The keys are rotated every rotation interval (checked on access), or on demand by Rotate.
+// KeyManager implements http.Handler which serves the public JWK Set, and can be used as jws.SigningKeyOption by jws.WithSigningKeyProvider.
+//
+// Example:
+//
+//	keyManager, err := jwk.NewKeyManager("EC", jwa.ES256, jwk.WithRotationInterval(24*time.Hour))
+//	if err != nil {
+//		return err
+//	}
+//	http.Handle("/.well-known/jwks.json", keyManager)
+//
+//	token, err := jwt.New(
+//		jws.WithSigningKeyProvider(keyManager),
+//		jose.NewHeader(jwa.ES256, jose.WithType("JWT")),
+//		jwt.NewClaimsSet(jwt.WithSubject("userID")),
+//	)
+type KeyManager struct {
+	generate          func() (*JSONWebKey, error)
+	rotationErrorFunc func(err error)
+	rotationInterval  time.Duration
+	retentionPeriod   time.Duration
+	maxAge            time.Duration
+	now               func() time.Time
+	mu                sync.Mutex
+	active            *JSONWebKey
+	activatedAt       time.Time
+	next              *JSONWebKey
+	retired           []*retiredKey
+	jwksCache         []byte
+	jwksCacheETag     string
+	jwksCacheModified bool
+}
+
+type retiredKey struct {
+	key       *JSONWebKey
+	retiredAt time.Time
+}
+
+type KeyManagerOption func(*KeyManager)
+
+// WithRotationInterval sets the interval of automatic rotation.
+// If interval is 0 or less, the keys are rotated only by Rotate.
+func WithRotationInterval(interval time.Duration) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.rotationInterval = interval
+	}
+}
+
+// WithRetentionPeriod sets the period during which retired keys are published.
+// It should be longer than the lifetime of the tokens signed by the keys.
+func WithRetentionPeriod(period time.Duration) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.retentionPeriod = period
+	}
+}
+
+// WithMaxAge sets max-age of Cache-Control header of the JWK Set response.
+// It should be shorter than the rotation interval, so that verifiers fetch the next key before it becomes active.
+func WithMaxAge(maxAge time.Duration) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.maxAge = maxAge
+	}
+}
+
+// WithKeyGenerator sets the function to generate a new key, instead of Generate.
+// The generated key must have a unique "kid".
+func WithKeyGenerator(generate func() (*JSONWebKey, error)) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.generate = generate
+	}
+}
+
+// WithRotationErrorFunc sets the function which is called when the automatic rotation fails.
+// On failure, the current keys are kept, and the rotation is retried on the next access.
+func WithRotationErrorFunc(f func(err error)) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.rotationErrorFunc = f
+	}
+}
+
+// WithNowFunc sets the function which returns the current time.
+func WithNowFunc(now func() time.Time) KeyManagerOption {
+	return func(m *KeyManager) {
+		m.now = now
+	}
+}
+
+// NewKeyManager returns a new KeyManager which generates keys by Generate(kty, alg, jwk.WithPublicKeyUse("sig")).
+func NewKeyManager(kty, alg string, opts ...KeyManagerOption) (*KeyManager, error) {
+	const (
+		defaultRotationInterval = 24 * time.Hour
+		defaultRetentionPeriod  = 24 * time.Hour
+		defaultMaxAge           = 1 * time.Hour
+	)
+
+	m := &KeyManager{
+		generate:         func() (*JSONWebKey, error) { return Generate(kty, alg, WithPublicKeyUse("sig")) },
+		rotationInterval: defaultRotationInterval,
+		retentionPeriod:  defaultRetentionPeriod,
+		maxAge:           defaultMaxAge,
+		now:              time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	active, err := m.generate()
+	if err != nil {
+		return nil, fmt.Errorf("generate: %w", err)
+	}
+	next, err := m.generate()
+	if err != nil {
+		return nil, fmt.Errorf("generate: %w", err)
+	}
+
+	m.active, m.activatedAt, m.next = active, m.now(), next
+	m.jwksCacheModified = true
+
+	return m, nil
+}
+
+// Rotate retires the active key, activates the next key, and generates a new next key.
+func (m *KeyManager) Rotate() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.rotate(m.now())
+}
+
+func (m *KeyManager) rotate(now time.Time) error {
+	next, err := m.generate()
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	m.retired = append(m.retired, &retiredKey{key: m.active, retiredAt: now})
+	m.active, m.activatedAt, m.next = m.next, now, next
+	m.jwksCacheModified = true
+
+	return nil
+}
+
+// refresh rotates the keys if the rotation interval has passed, and removes the retired keys whose retention period has passed.
+// If the rotation fails, the current keys are kept, because the active key is still valid, and the rotation is retried on the next access.
+func (m *KeyManager) refresh() {
+	now := m.now()
+
+	if m.rotationInterval > 0 && !now.Before(m.activatedAt.Add(m.rotationInterval)) {
+		if err := m.rotate(now); err != nil && m.rotationErrorFunc != nil {
+			m.rotationErrorFunc(err)
+		}
+	}
+
+	retired := m.retired[:0]
+	for _, r := range m.retired {
+		if now.Before(r.retiredAt.Add(m.retentionPeriod)) {
+			retired = append(retired, r)
+		}
+	}
+	if len(retired) != len(m.retired) {
+		m.jwksCacheModified = true
+	}
+	m.retired = retired
+}
+
+// SigningKey returns the active private (or symmetric) key.
+func (m *KeyManager) SigningKey() (*JSONWebKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.refresh()
+
+	return m.active, nil
+}
+
+// JWKSet returns the JWK Set of the public keys of the active, next and retired keys.
+// Symmetric keys are not included.
+func (m *KeyManager) JWKSet() (*JWKSet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.refresh()
+
+	return m.jwkSet(), nil
+}
+
+func (m *KeyManager) jwkSet() *JWKSet {
+	keys := []*JSONWebKey{m.active, m.next}
+	for i := len(m.retired) - 1; i >= 0; i-- {
+		keys = append(keys, m.retired[i].key)
+	}
+
+	jwks := &JWKSet{Keys: make([]*JSONWebKey, 0, len(keys))}
+	for _, key := range keys {
+		if pub := key.Public(); pub != nil {
+			jwks.Keys = append(jwks.Keys, pub)
+		}
+	}
+	return jwks
+}
+
+func (m *KeyManager) encodedJWKSet() (body []byte, etag string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.refresh()
+
+	if m.jwksCacheModified {
+		b, err := json.Marshal(m.jwkSet())
+		if err != nil {
+			return nil, "", fmt.Errorf("json.Marshal: %w", err)
+		}
+		sum := sha256.Sum256(b)
+		m.jwksCache, m.jwksCacheETag, m.jwksCacheModified = b, `"`+base64.RawURLEncoding.EncodeToString(sum[:])+`"`, false
+	}
+
+	return m.jwksCache, m.jwksCacheETag, nil
+}
+
+// ServeHTTP serves the public JWK Set with Cache-Control and ETag headers.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7517#section-8.5.1
+func (m *KeyManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodHead)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, etag, err := m.encodedJWKSet()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.FormatInt(int64(m.maxAge/time.Second), 10))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,245 @@
+package jwk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+)
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func kids(jwks *jwk.JWKSet) []string {
+	kids := make([]string, 0, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		kids = append(kids, key.KeyID)
+	}
+	return kids
+}
+
+func TestKeyManager(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(rotation)", func(t *testing.T) {
+		t.Parallel()
+		clock := &testClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+		m, err := jwk.NewKeyManager("EC", jwa.ES256, jwk.WithRotationInterval(time.Hour), jwk.WithRetentionPeriod(45*time.Minute), jwk.WithNowFunc(clock.Now))
+		if err != nil {
+			t.Fatalf("❌: jwk.NewKeyManager: err != nil: %v", err)
+		}
+
+		key1, err := m.SigningKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.KeyManager).SigningKey: err != nil: %v", err)
+		}
+		if !key1.IsPrivate() || key1.Algorithm != jwa.ES256 || key1.PublicKeyUse != "sig" {
+			t.Errorf("❌: (*jwk.KeyManager).SigningKey: %+v", key1)
+		}
+		jwks, err := m.JWKSet()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.KeyManager).JWKSet: err != nil: %v", err)
+		}
+		if len(jwks.Keys) != 2 || jwks.Keys[0].KeyID != key1.KeyID || jwks.Keys[0].IsPrivate() || jwks.Keys[1].IsPrivate() {
+			t.Fatalf("❌: (*jwk.KeyManager).JWKSet: %v", kids(jwks))
+		}
+		next := jwks.Keys[1].KeyID
+
+		clock.Add(time.Hour)
+		key2, err := m.SigningKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.KeyManager).SigningKey: err != nil: %v", err)
+		}
+		if key2.KeyID != next {
+			t.Errorf("❌: (*jwk.KeyManager).SigningKey: expect(%s) != actual(%s)", next, key2.KeyID)
+		}
+		jwks, _ = m.JWKSet()
+		if len(jwks.Keys) != 3 || jwks.Keys[0].KeyID != key2.KeyID || jwks.Keys[2].KeyID != key1.KeyID {
+			t.Errorf("❌: (*jwk.KeyManager).JWKSet: %v", kids(jwks))
+		}
+
+		clock.Add(30 * time.Minute)
+		if err := m.Rotate(); err != nil {
+			t.Fatalf("❌: (*jwk.KeyManager).Rotate: err != nil: %v", err)
+		}
+		clock.Add(20 * time.Minute)
+		jwks, _ = m.JWKSet()
+		if len(jwks.Keys) != 3 || jwks.Keys[2].KeyID != key2.KeyID {
+			t.Errorf("❌: (*jwk.KeyManager).JWKSet: retired key is not removed: %v", kids(jwks))
+		}
+	})
+
+	t.Run("success(oct)", func(t *testing.T) {
+		t.Parallel()
+		m, err := jwk.NewKeyManager("oct", jwa.HS256)
+		if err != nil {
+			t.Fatalf("❌: jwk.NewKeyManager: err != nil: %v", err)
+		}
+		jwks, _ := m.JWKSet()
+		if len(jwks.Keys) != 0 {
+			t.Errorf("❌: (*jwk.KeyManager).JWKSet: symmetric keys are published: %v", kids(jwks))
+		}
+	})
+
+	t.Run("failure(jwk.ErrAlgorithmIsNotSupported)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jwk.NewKeyManager("EC", jwa.RS256); !errors.Is(err, jwk.ErrAlgorithmIsNotSupported) {
+			t.Errorf("❌: jwk.NewKeyManager: err != jwk.ErrAlgorithmIsNotSupported: %v", err)
+		}
+	})
+
+	t.Run("failure(WithKeyGenerator)", func(t *testing.T) {
+		t.Parallel()
+		errTest := errors.New("test")
+		n := 0
+		m, err := jwk.NewKeyManager("", "", jwk.WithKeyGenerator(func() (*jwk.JSONWebKey, error) {
+			n++
+			if n > 2 {
+				return nil, errTest
+			}
+			return jwk.Generate("OKP", jwa.EdDSA)
+		}))
+		if err != nil {
+			t.Fatalf("❌: jwk.NewKeyManager: err != nil: %v", err)
+		}
+		if err := m.Rotate(); !errors.Is(err, errTest) {
+			t.Errorf("❌: (*jwk.KeyManager).Rotate: err != errTest: %v", err)
+		}
+	})
+
+	t.Run("success(rotation,WithKeyGenerator,failure)", func(t *testing.T) {
+		t.Parallel()
+		clock := &testClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+		errTest := errors.New("test")
+		var mu sync.Mutex
+		fail := false
+		var rotationErrs []error
+		m, err := jwk.NewKeyManager("", "",
+			jwk.WithRotationInterval(time.Hour),
+			jwk.WithNowFunc(clock.Now),
+			jwk.WithKeyGenerator(func() (*jwk.JSONWebKey, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if fail {
+					return nil, errTest
+				}
+				return jwk.Generate("OKP", jwa.EdDSA)
+			}),
+			jwk.WithRotationErrorFunc(func(err error) { rotationErrs = append(rotationErrs, err) }),
+		)
+		if err != nil {
+			t.Fatalf("❌: jwk.NewKeyManager: err != nil: %v", err)
+		}
+		key1, _ := m.SigningKey()
+
+		mu.Lock()
+		fail = true
+		mu.Unlock()
+		clock.Add(time.Hour)
+		key, err := m.SigningKey()
+		if err != nil || key.KeyID != key1.KeyID {
+			t.Errorf("❌: (*jwk.KeyManager).SigningKey: the active key is not kept: err=%v", err)
+		}
+		if jwks, err := m.JWKSet(); err != nil || len(jwks.Keys) != 2 {
+			t.Errorf("❌: (*jwk.KeyManager).JWKSet: err=%v", err)
+		}
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("❌: (*jwk.KeyManager).ServeHTTP: code != 200: %d", rec.Code)
+		}
+		if len(rotationErrs) != 3 || !errors.Is(rotationErrs[0], errTest) {
+			t.Errorf("❌: WithRotationErrorFunc: %v", rotationErrs)
+		}
+
+		mu.Lock()
+		fail = false
+		mu.Unlock()
+		if key, _ := m.SigningKey(); key.KeyID == key1.KeyID {
+			t.Errorf("❌: (*jwk.KeyManager).SigningKey: the rotation is not retried")
+		}
+	})
+}
+
+func TestKeyManager_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	m, err := jwk.NewKeyManager("OKP", jwa.EdDSA, jwk.WithMaxAge(10*time.Minute))
+	if err != nil {
+		t.Fatalf("❌: jwk.NewKeyManager: err != nil: %v", err)
+	}
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("❌: (*jwk.KeyManager).ServeHTTP: code != 200: %d", w.Code)
+		}
+		if actual := w.Header().Get("Cache-Control"); actual != "public, max-age=600" {
+			t.Errorf("❌: (*jwk.KeyManager).ServeHTTP: Cache-Control: %s", actual)
+		}
+		if actual := w.Header().Get("Content-Type"); actual != "application/jwk-set+json" {
+			t.Errorf("❌: (*jwk.KeyManager).ServeHTTP: Content-Type: %s", actual)
+		}
+		jwks := new(jwk.JWKSet)
+		if err := json.Unmarshal(w.Body.Bytes(), jwks); err != nil {
+			t.Fatalf("❌: json.Unmarshal: err != nil: %v", err)
+		}
+		if len(jwks.Keys) != 2 || jwks.Keys[0].IsPrivate() {
+			t.Errorf("❌: (*jwk.KeyManager).ServeHTTP: %s", w.Body.String())
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		r.Header.Set("If-None-Match", w.Header().Get("ETag"))
+		w = httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("❌: (*jwk.KeyManager).ServeHTTP: code != 304: %d", w.Code)
+		}
+	})
+
+	t.Run("success(jwk.Client)", func(t *testing.T) {
+		t.Parallel()
+		s := httptest.NewServer(m)
+		t.Cleanup(s.Close)
+		key, err := m.SigningKey()
+		if err != nil {
+			t.Fatalf("❌: (*jwk.KeyManager).SigningKey: err != nil: %v", err)
+		}
+		c := jwk.NewClient(context.Background())
+		if _, err := c.GetJSONWebKey(context.Background(), s.URL, key.KeyID); err != nil {
+			t.Errorf("❌: (*jwk.Client).GetJSONWebKey: err != nil: %v", err)
+		}
+	})
+
+	t.Run("failure(405)", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/.well-known/jwks.json", nil))
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("❌: (*jwk.KeyManager).ServeHTTP: code != 405: %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package jwk
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// - ref. JSON Web Key (JWK) Thumbprint https://www.rfc-editor.org/rfc/rfc7638
+
+var ErrHashIsNotAvailable = errors.New("jwk: hash is not available")
+
+// Thumbprint returns the base64url-encoded JWK Thumbprint of the JSON Web Key.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7638#section-3
+//   - ref. https://www.rfc-editor.org/rfc/rfc8037#appendix-A.3
+//
+// The thumbprint is computed over the JSON object which contains only the
+// required members of the key type, with the member names in lexicographic order
+// and no whitespace:
+//
+//	RSA: {"e","kty","n"}
+//	EC:  {"crv","kty","x","y"}
+//	OKP: {"crv","kty","x"}
+//	oct: {"k","kty"}
+//
+// Example:
+//
+//	kid, err := jsonWebKey.Thumbprint(crypto.SHA256)
+func (jwk *JSONWebKey) Thumbprint(hash crypto.Hash) (string, error) {
+	if !hash.Available() {
+		return "", fmt.Errorf("hash=%s: %w", hash, ErrHashIsNotAvailable)
+	}
+
+	// NOTE: encoding/json marshals struct fields in the order of declaration.
+	var v any
+	switch jwk.KeyType {
+	case "RSA":
+		if jwk.E == "" || jwk.N == "" {
+			return "", fmt.Errorf("kty=%s: e or n is empty: %w", jwk.KeyType, ErrInvalidKey)
+		}
+		v = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	case "EC":
+		if jwk.Crv == "" || jwk.X == "" || jwk.Y == "" {
+			return "", fmt.Errorf("kty=%s: crv, x or y is empty: %w", jwk.KeyType, ErrInvalidKey)
+		}
+		v = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.KeyType, jwk.X, jwk.Y}
+	case "OKP":
+		if jwk.Crv == "" || jwk.X == "" {
+			return "", fmt.Errorf("kty=%s: crv or x is empty: %w", jwk.KeyType, ErrInvalidKey)
+		}
+		v = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.KeyType, jwk.X}
+	case "oct":
+		if jwk.K == "" {
+			return "", fmt.Errorf("kty=%s: k is empty: %w", jwk.KeyType, ErrInvalidKey)
+		}
+		v = struct {
+			K   string `json:"k"`
+			Kty string `json:"kty"`
+		}{jwk.K, jwk.KeyType}
+	default:
+		return "", fmt.Errorf("kty=%s: %w", jwk.KeyType, ErrKeyIsNotForAlgorithm)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	h := hash.New()
+	_, _ = h.Write(b)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)), nil
+}
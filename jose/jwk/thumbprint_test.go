@@ -0,0 +1,101 @@
+package jwk_test
+
+import (
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/kunitsucom/util.go/jose/jwk"
+)
+
+func TestJSONWebKey_Thumbprint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(RSA)", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc7638#section-3.1
+		k := &jwk.JSONWebKey{
+			KeyType:   "RSA",
+			N:         "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			E:         "AQAB",
+			Algorithm: "RS256",
+			KeyID:     "2011-04-29",
+		}
+		const expect = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+		actual, err := k.Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Fatalf("❌: (*jwk.JSONWebKey).Thumbprint: err != nil: %v", err)
+		}
+		if expect != actual {
+			t.Errorf("❌: (*jwk.JSONWebKey).Thumbprint: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+
+	t.Run("success(OKP)", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc8037#appendix-A.3
+		k := &jwk.JSONWebKey{
+			KeyType: "OKP",
+			Crv:     "Ed25519",
+			X:       "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+		}
+		const expect = "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+		actual, err := k.Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Fatalf("❌: (*jwk.JSONWebKey).Thumbprint: err != nil: %v", err)
+		}
+		if expect != actual {
+			t.Errorf("❌: (*jwk.JSONWebKey).Thumbprint: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+
+	t.Run("success(EC,oct)", func(t *testing.T) {
+		t.Parallel()
+		for _, k := range []*jwk.JSONWebKey{
+			{KeyType: "EC", Crv: "P-256", X: "x", Y: "y", D: "d"},
+			{KeyType: "oct", K: "k"},
+		} {
+			priv, err := k.Thumbprint(crypto.SHA256)
+			if err != nil {
+				t.Fatalf("❌: (*jwk.JSONWebKey).Thumbprint: err != nil: %v", err)
+			}
+			k.KeyID = "ignored"
+			k.D = ""
+			actual, err := k.Thumbprint(crypto.SHA256)
+			if err != nil {
+				t.Fatalf("❌: (*jwk.JSONWebKey).Thumbprint: err != nil: %v", err)
+			}
+			if priv != actual {
+				t.Errorf("❌: (*jwk.JSONWebKey).Thumbprint: expect(%s) != actual(%s)", priv, actual)
+			}
+		}
+	})
+
+	t.Run("failure(jwk.ErrInvalidKey)", func(t *testing.T) {
+		t.Parallel()
+		for _, k := range []*jwk.JSONWebKey{
+			{KeyType: "RSA"},
+			{KeyType: "EC", Crv: "P-256", X: "x"},
+			{KeyType: "OKP", Crv: "Ed25519"},
+			{KeyType: "oct"},
+		} {
+			if _, err := k.Thumbprint(crypto.SHA256); !errors.Is(err, jwk.ErrInvalidKey) {
+				t.Errorf("❌: (*jwk.JSONWebKey).Thumbprint: kty=%s: err != jwk.ErrInvalidKey: %v", k.KeyType, err)
+			}
+		}
+	})
+
+	t.Run("failure(jwk.ErrKeyIsNotForAlgorithm)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := (&jwk.JSONWebKey{KeyType: "unknown"}).Thumbprint(crypto.SHA256); !errors.Is(err, jwk.ErrKeyIsNotForAlgorithm) {
+			t.Errorf("❌: (*jwk.JSONWebKey).Thumbprint: err != jwk.ErrKeyIsNotForAlgorithm: %v", err)
+		}
+	})
+
+	t.Run("failure(jwk.ErrHashIsNotAvailable)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := (&jwk.JSONWebKey{KeyType: "oct", K: "k"}).Thumbprint(crypto.MD4); !errors.Is(err, jwk.ErrHashIsNotAvailable) {
+			t.Errorf("❌: (*jwk.JSONWebKey).Thumbprint: err != jwk.ErrHashIsNotAvailable: %v", err)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package jws
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/kunitsucom/util.go/jose"
+)
+
+// - ref. Detached Content https://www.rfc-editor.org/rfc/rfc7515#appendix-F
+// - ref. JSON Web Signature (JWS) Unencoded Payload Option https://www.rfc-editor.org/rfc/rfc7797
+
+var ErrPayloadIsNotDetached = errors.New(`jws: payload is not detached`)
+
+func isPayloadBase64URLEncoded(h *jose.Header) bool {
+	return h.Base64URLEncodePayload == nil || *h.Base64URLEncodePayload
+}
+
+// signingInputWithPayload returns the JWS Signing Input.
+// If "b64" is false, payload is not base64url-encoded.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7797#section-3
+func signingInputWithPayload(h *jose.Header, headerEncoded string, payload []byte) string {
+	if !isPayloadBase64URLEncoded(h) {
+		return headerEncoded + "." + string(payload)
+	}
+	return headerEncoded + "." + base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// SignDetached signs payload and returns the JWS Compact Serialization with detached content ("header..signature").
+// If header has "b64": false (see jose.WithBase64URLEncodePayload), payload is signed without base64url-encoding.
+//
+// Example:
+//
+//	detached, err := jws.SignDetached(
+//		jws.WithECDSAKey(privateKey),
+//		jose.NewHeader(jwa.ES256, jose.WithBase64URLEncodePayload(false)),
+//		body,
+//	)
+func SignDetached(keyOpt SigningKeyOption, header *jose.Header, payload []byte) (detached string, err error) {
+	keyOpt, header, err = ResolveSigningKey(keyOpt, header)
+	if err != nil {
+		return "", fmt.Errorf("jws.ResolveSigningKey: %w", err)
+	}
+
+	headerEncoded, err := header.Encode()
+	if err != nil {
+		return "", fmt.Errorf("(*jose.Header).Encode: %w", err)
+	}
+
+	signatureEncoded, err := Sign(header.Algorithm, keyOpt, signingInputWithPayload(header, headerEncoded, payload))
+	if err != nil {
+		return "", fmt.Errorf("jws.Sign: %w", err)
+	}
+
+	return headerEncoded + ".." + signatureEncoded, nil
+}
+
+// VerifyDetached verifies the JWS Compact Serialization with detached content ("header..signature") against payload.
+//
+// Example:
+//
+//	header, err := jws.VerifyDetached(
+//		jws.UseECDSAKey(publicKey),
+//		r.Header.Get("X-JWS-Signature"),
+//		body,
+//	)
+func VerifyDetached(keyOption VerificationKeyOption, detached string, payload []byte, opts ...VerifyOption) (header *jose.Header, err error) {
+	headerEncoded, payloadEncoded, signatureEncoded, err := Parse(detached)
+	if err != nil {
+		return nil, fmt.Errorf("jws.Parse: %w", err)
+	}
+
+	if payloadEncoded != "" {
+		return nil, ErrPayloadIsNotDetached
+	}
+
+	h := new(jose.Header)
+	if err := h.Decode(headerEncoded); err != nil {
+		return nil, fmt.Errorf("(*jose.Header).Decode: %w", err)
+	}
+
+	if err := verifyCritical(h, opts...); err != nil {
+		return nil, err
+	}
+
+	if err := verify(keyOption, h, signingInputWithPayload(h, headerEncoded, payload), signatureEncoded); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
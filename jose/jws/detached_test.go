@@ -0,0 +1,192 @@
+package jws_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/must"
+	testingz "github.com/kunitsucom/util.go/testing"
+)
+
+func TestVerifyDetached(t *testing.T) {
+	t.Parallel()
+
+	// - ref. https://www.rfc-editor.org/rfc/rfc7515#appendix-A.1
+	hmacKey := must.One(base64.RawURLEncoding.DecodeString("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"))
+	privateKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	publicKey := must.One(x509z.ParseECDSAPublicKeyPEM([]byte(testingz.TestECDSAPublicKey256BitPEM)))
+	payload := []byte("$.02")
+
+	t.Run("success(RFC7797 4.1)", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc7797#section-4.1
+		const detached = "eyJhbGciOiJIUzI1NiJ9..5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"
+		if _, err := jws.VerifyDetached(jws.UseHMACKey(hmacKey), detached, payload); err != nil {
+			t.Errorf("❌: jws.VerifyDetached: err != nil: %v", err)
+		}
+		actual, err := jws.SignDetached(jws.WithHMACKey(hmacKey), jose.NewHeader(jwa.HS256), payload)
+		if err != nil {
+			t.Fatalf("❌: jws.SignDetached: err != nil: %v", err)
+		}
+		if actual != detached {
+			t.Errorf("❌: jws.SignDetached: expect(%s) != actual(%s)", detached, actual)
+		}
+	})
+
+	t.Run("success(RFC7797 4.2)", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc7797#section-4.2
+		const detached = "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+		header, err := jws.VerifyDetached(jws.UseHMACKey(hmacKey), detached, payload)
+		if err != nil {
+			t.Fatalf("❌: jws.VerifyDetached: err != nil: %v", err)
+		}
+		if header.Base64URLEncodePayload == nil || *header.Base64URLEncodePayload {
+			t.Errorf("❌: jws.VerifyDetached: b64 != false: %v", header.Base64URLEncodePayload)
+		}
+		if _, err := jws.VerifyDetached(jws.UseHMACKey(hmacKey), detached, []byte("$.03")); !errors.Is(err, jwa.ErrFailedToVerifySignature) {
+			t.Errorf("❌: jws.VerifyDetached: err != jwa.ErrFailedToVerifySignature: %v", err)
+		}
+	})
+
+	t.Run("success(b64=false)", func(t *testing.T) {
+		t.Parallel()
+		detached, err := jws.SignDetached(jws.WithECDSAKey(privateKey), jose.NewHeader(jwa.ES256, jose.WithBase64URLEncodePayload(false)), payload)
+		if err != nil {
+			t.Fatalf("❌: jws.SignDetached: err != nil: %v", err)
+		}
+		header, err := jws.VerifyDetached(jws.UseECDSAKey(publicKey), detached, payload)
+		if err != nil {
+			t.Fatalf("❌: jws.VerifyDetached: err != nil: %v", err)
+		}
+		if len(header.Critical) != 1 || header.Critical[0] != "b64" {
+			t.Errorf("❌: jws.VerifyDetached: crit: %v", header.Critical)
+		}
+	})
+
+	t.Run("failure(jws.ErrPayloadIsNotDetached)", func(t *testing.T) {
+		t.Parallel()
+		const token = "eyJhbGciOiJIUzI1NiJ9.JC4wMg.5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"
+		if _, err := jws.VerifyDetached(jws.UseHMACKey(hmacKey), token, payload); !errors.Is(err, jws.ErrPayloadIsNotDetached) {
+			t.Errorf("❌: jws.VerifyDetached: err != jws.ErrPayloadIsNotDetached: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrInvalidTokenReceived)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jws.VerifyDetached(jws.UseHMACKey(hmacKey), "invalid", payload); !errors.Is(err, jws.ErrInvalidTokenReceived) {
+			t.Errorf("❌: jws.VerifyDetached: err != jws.ErrInvalidTokenReceived: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrInvalidCriticalHeaderParameter)", func(t *testing.T) {
+		t.Parallel()
+		b64 := false
+		header := jose.NewHeader(jwa.HS256)
+		header.Base64URLEncodePayload = &b64
+		detached := must.One(jws.SignDetached(jws.WithHMACKey(hmacKey), header, payload))
+		if _, err := jws.VerifyDetached(jws.UseHMACKey(hmacKey), detached, payload); !errors.Is(err, jws.ErrInvalidCriticalHeaderParameter) {
+			t.Errorf("❌: jws.VerifyDetached: err != jws.ErrInvalidCriticalHeaderParameter: %v", err)
+		}
+	})
+}
+
+func TestVerify_crit(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("your-256-bit-secret")
+	sign := func(header *jose.Header) string {
+		headerEncoded := must.One(header.Encode())
+		signingInput := headerEncoded + ".cGF5bG9hZA"
+		return signingInput + "." + must.One(jws.Sign(header.Algorithm, jws.WithHMACKey(key), signingInput))
+	}
+
+	t.Run("success(understood)", func(t *testing.T) {
+		t.Parallel()
+		token := sign(jose.NewHeader(jwa.HS256, jose.WithCritical([]string{"exp"}), jose.WithPrivateHeaderParameter("exp", 1363284000)))
+		header, err := jws.Verify(jws.UseHMACKey(key), token, jws.VerifyCriticalHeaderParameters("exp"))
+		if err != nil {
+			t.Fatalf("❌: jws.Verify: err != nil: %v", err)
+		}
+		if header.Algorithm != jwa.HS256 {
+			t.Errorf("❌: jws.Verify: alg: %s", header.Algorithm)
+		}
+	})
+
+	t.Run("failure(jws.ErrCriticalHeaderParameterIsNotUnderstood)", func(t *testing.T) {
+		t.Parallel()
+		token := sign(jose.NewHeader(jwa.HS256, jose.WithCritical([]string{"exp"}), jose.WithPrivateHeaderParameter("exp", 1363284000)))
+		if _, err := jws.Verify(jws.UseHMACKey(key), token); !errors.Is(err, jws.ErrCriticalHeaderParameterIsNotUnderstood) {
+			t.Errorf("❌: jws.Verify: err != jws.ErrCriticalHeaderParameterIsNotUnderstood: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrInvalidCriticalHeaderParameter)", func(t *testing.T) {
+		t.Parallel()
+		for _, header := range []*jose.Header{
+			jose.NewHeader(jwa.HS256, jose.WithCritical([]string{"alg"})),
+			jose.NewHeader(jwa.HS256, jose.WithCritical([]string{"exp"})),
+			jose.NewHeader(jwa.HS256, jose.WithCritical([]string{"b64"})),
+		} {
+			token := sign(header)
+			if _, err := jws.Verify(jws.UseHMACKey(key), token, jws.VerifyCriticalHeaderParameters("alg", "exp")); !errors.Is(err, jws.ErrInvalidCriticalHeaderParameter) {
+				t.Errorf("❌: jws.Verify: %s: err != jws.ErrInvalidCriticalHeaderParameter: %v", token, err)
+			}
+		}
+		headerEncoded := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","crit":[]}`))
+		signingInput := headerEncoded + ".cGF5bG9hZA"
+		token := signingInput + "." + must.One(jws.Sign(jwa.HS256, jws.WithHMACKey(key), signingInput))
+		if _, err := jws.Verify(jws.UseHMACKey(key), token); !errors.Is(err, jws.ErrInvalidCriticalHeaderParameter) {
+			t.Errorf("❌: jws.Verify: %s: err != jws.ErrInvalidCriticalHeaderParameter: %v", token, err)
+		}
+	})
+}
+
+func TestVerifyJSON_b64(t *testing.T) {
+	t.Parallel()
+
+	privateKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	publicKey := must.One(x509z.ParseECDSAPublicKeyPEM([]byte(testingz.TestECDSAPublicKey256BitPEM)))
+	payload := []byte(`{"amount":"100.00"}`)
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		s := must.One(jws.SignJSON(payload, jws.NewSigner(jws.WithECDSAKey(privateKey), jose.NewHeader(jwa.ES256, jose.WithBase64URLEncodePayload(false)), nil)))
+		if s.Payload != string(payload) {
+			t.Errorf("❌: jws.SignJSON: payload: %s", s.Payload)
+		}
+		_, actual, err := jws.VerifyJSON(jws.UseECDSAKey(publicKey), must.One(json.Marshal(s)))
+		if err != nil {
+			t.Fatalf("❌: jws.VerifyJSON: err != nil: %v", err)
+		}
+		if string(actual) != string(payload) {
+			t.Errorf("❌: jws.VerifyJSON: expect(%s) != actual(%s)", payload, actual)
+		}
+	})
+
+	t.Run("failure(jws.ErrInvalidCriticalHeaderParameter)", func(t *testing.T) {
+		t.Parallel()
+		_, err := jws.SignJSON(payload, jws.NewSigner(jws.WithECDSAKey(privateKey), jose.NewHeader(jwa.ES256), jose.NewHeader("", jose.WithBase64URLEncodePayload(false))))
+		if !errors.Is(err, jws.ErrInvalidCriticalHeaderParameter) {
+			t.Errorf("❌: jws.SignJSON: err != jws.ErrInvalidCriticalHeaderParameter: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrInvalidJSONSerializationReceived)", func(t *testing.T) {
+		t.Parallel()
+		_, err := jws.SignJSON(
+			payload,
+			jws.NewSigner(jws.WithECDSAKey(privateKey), jose.NewHeader(jwa.ES256, jose.WithBase64URLEncodePayload(false)), nil),
+			jws.NewSigner(jws.WithECDSAKey(privateKey), jose.NewHeader(jwa.ES256), nil),
+		)
+		if !errors.Is(err, jws.ErrInvalidJSONSerializationReceived) {
+			t.Errorf("❌: jws.SignJSON: err != jws.ErrInvalidJSONSerializationReceived: %v", err)
+		}
+	})
+}
@@ -0,0 +1,396 @@
+package jws
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kunitsucom/util.go/jose"
+)
+
+// - ref. JWS JSON Serialization https://www.rfc-editor.org/rfc/rfc7515#section-7.2
+
+var (
+	ErrInvalidJSONSerializationReceived = errors.New(`jws: invalid JSON serialization received`)
+	ErrSignerIsEmpty                    = errors.New(`jws: signer is empty`)
+	ErrHeaderParameterIsDuplicated      = errors.New(`jws: header parameter is duplicated in protected and unprotected header`)
+	ErrAlgorithmIsEmpty                 = errors.New(`jws: alg header parameter is empty`)
+	ErrNoSignatureVerified              = errors.New(`jws: no signature verified`)
+)
+
+// JSONSerialization
+//
+// The JWS JSON Serialization represents digitally signed or MACed
+// content as a JSON object.  This representation is neither optimized
+// for compactness nor URL-safe.
+//
+// Marshaling a JSONSerialization produces the general JWS JSON Serialization syntax.
+// Use (*JSONSerialization).MarshalFlattenedJSON for the flattened JWS JSON Serialization syntax.
+//
+//   - ref. General JWS JSON Serialization Syntax https://www.rfc-editor.org/rfc/rfc7515#section-7.2.1
+type JSONSerialization struct {
+	// Payload: "payload" member MUST be present and contain the value BASE64URL(JWS Payload).
+	Payload string `json:"payload"`
+
+	// Signatures: "signatures" member value MUST be an array of JSON objects.
+	Signatures []*JSONSignature `json:"signatures"`
+}
+
+// JSONSignature is a member of "signatures" in the JWS JSON Serialization.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-7.2.1
+type JSONSignature struct {
+	// Protected: "protected" member MUST be present and contain the value
+	// BASE64URL(UTF8(JWS Protected Header)) when the JWS Protected
+	// Header value is non-empty; otherwise, it MUST be absent.
+	Protected string `json:"protected,omitempty"`
+
+	// Header: "header" member MUST be present and contain the value JWS
+	// Unprotected Header when the JWS Unprotected Header value is non-
+	// empty; otherwise, it MUST be absent.
+	Header *jose.Header `json:"header,omitempty"`
+
+	// Signature: "signature" member MUST be present and contain the value
+	// BASE64URL(JWS Signature).
+	Signature string `json:"signature"`
+}
+
+// flattenedJSONSerialization
+//
+//   - ref. Flattened JWS JSON Serialization Syntax https://www.rfc-editor.org/rfc/rfc7515#section-7.2.2
+type flattenedJSONSerialization struct {
+	Payload    string           `json:"payload"`
+	Protected  string           `json:"protected,omitempty"`
+	Header     *jose.Header     `json:"header,omitempty"`
+	Signature  string           `json:"signature"`
+	Signatures *json.RawMessage `json:"signatures,omitempty"`
+}
+
+// MarshalFlattenedJSON returns the flattened JWS JSON Serialization.
+// It is only available when the JSONSerialization has exactly one signature.
+func (s *JSONSerialization) MarshalFlattenedJSON() ([]byte, error) {
+	if len(s.Signatures) != 1 {
+		return nil, fmt.Errorf("len(signatures)=%d: %w", len(s.Signatures), ErrInvalidJSONSerializationReceived)
+	}
+
+	b, err := json.Marshal(&flattenedJSONSerialization{
+		Payload:   s.Payload,
+		Protected: s.Signatures[0].Protected,
+		Header:    s.Signatures[0].Header,
+		Signature: s.Signatures[0].Signature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return b, nil
+}
+
+// ParseJSON parses both the general and the flattened JWS JSON Serialization.
+func ParseJSON(data []byte) (*JSONSerialization, error) {
+	v := new(flattenedJSONSerialization)
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	// general
+	if v.Signatures != nil {
+		if v.Protected != "" || v.Header != nil || v.Signature != "" {
+			return nil, fmt.Errorf("both signatures and signature are present: %w", ErrInvalidJSONSerializationReceived)
+		}
+		s := new(JSONSerialization)
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		if len(s.Signatures) == 0 {
+			return nil, fmt.Errorf("signatures is empty: %w", ErrInvalidJSONSerializationReceived)
+		}
+		for i, sig := range s.Signatures {
+			if sig == nil {
+				return nil, fmt.Errorf("signatures[%d] is null: %w", i, ErrInvalidJSONSerializationReceived)
+			}
+		}
+		return s, nil
+	}
+
+	// flattened
+	if v.Signature == "" {
+		return nil, fmt.Errorf("signature is empty: %w", ErrInvalidJSONSerializationReceived)
+	}
+
+	return &JSONSerialization{
+		Payload: v.Payload,
+		Signatures: []*JSONSignature{{
+			Protected: v.Protected,
+			Header:    v.Header,
+			Signature: v.Signature,
+		}},
+	}, nil
+}
+
+// Signer is a signing key and headers for a signature in the JWS JSON Serialization.
+type Signer struct {
+	keyOpt      SigningKeyOption
+	protected   *jose.Header
+	unprotected *jose.Header
+}
+
+// NewSigner returns a Signer.
+// The "alg" header parameter must be contained in either the protected or the unprotected header,
+// and the names of header parameters in the both headers must be disjoint.
+//
+// Example:
+//
+//	jws.NewSigner(
+//		jws.WithRSAKey(rsaPrivateKey),
+//		jose.NewHeader(jwa.RS256),
+//		jose.NewHeader("", jose.WithKeyID("rsa-key")),
+//	)
+func NewSigner(keyOpt SigningKeyOption, protected, unprotected *jose.Header) Signer {
+	return Signer{
+		keyOpt:      keyOpt,
+		protected:   protected,
+		unprotected: unprotected,
+	}
+}
+
+// SignJSON signs payload with each signer and returns the JWS JSON Serialization.
+//
+// Example:
+//
+//	s, err := jws.SignJSON(
+//		payload,
+//		jws.NewSigner(jws.WithRSAKey(rsaPrivateKey), jose.NewHeader(jwa.RS256), jose.NewHeader("", jose.WithKeyID("rsa-key"))),
+//		jws.NewSigner(jws.WithECDSAKey(ecdsaPrivateKey), jose.NewHeader(jwa.ES256), jose.NewHeader("", jose.WithKeyID("ec-key"))),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	b, err := json.Marshal(s)
+func SignJSON(payload []byte, signers ...Signer) (*JSONSerialization, error) {
+	if len(signers) == 0 {
+		return nil, ErrSignerIsEmpty
+	}
+
+	s := &JSONSerialization{
+		Signatures: make([]*JSONSignature, 0, len(signers)),
+	}
+
+	for i, signer := range signers {
+		if err := verifyUnprotectedHeader(signer.unprotected); err != nil {
+			return nil, fmt.Errorf("signers[%d]: %w", i, err)
+		}
+
+		signer, err := resolveSigner(signer)
+		if err != nil {
+			return nil, fmt.Errorf("signers[%d]: %w", i, err)
+		}
+
+		var protectedEncoded string
+		b64 := true
+		if signer.protected != nil {
+			encoded, err := signer.protected.Encode()
+			if err != nil {
+				return nil, fmt.Errorf("signers[%d]: (*jose.Header).Encode: %w", i, err)
+			}
+			protectedEncoded = encoded
+			b64 = isPayloadBase64URLEncoded(signer.protected)
+		}
+
+		// The "b64" values of all signatures MUST be the same.
+		//   - ref. https://www.rfc-editor.org/rfc/rfc7797#section-3
+		payloadEncoded := string(payload)
+		if b64 {
+			payloadEncoded = base64.RawURLEncoding.EncodeToString(payload)
+		}
+		if i > 0 && s.Payload != payloadEncoded {
+			return nil, fmt.Errorf("signers[%d]: b64 is not consistent: %w", i, ErrInvalidJSONSerializationReceived)
+		}
+		s.Payload = payloadEncoded
+
+		h, err := mergeHeader(signer.protected, signer.unprotected)
+		if err != nil {
+			return nil, fmt.Errorf("signers[%d]: %w", i, err)
+		}
+
+		signatureEncoded, err := Sign(h.Algorithm, signer.keyOpt, protectedEncoded+"."+s.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("signers[%d]: jws.Sign: %w", i, err)
+		}
+
+		s.Signatures = append(s.Signatures, &JSONSignature{
+			Protected: protectedEncoded,
+			Header:    signer.unprotected,
+			Signature: signatureEncoded,
+		})
+	}
+
+	return s, nil
+}
+
+// resolveSigner resolves the signing key of signer,
+// and sets "kid" and "alg" of the resolved JSON Web Key to the protected header if they are in neither header.
+func resolveSigner(signer Signer) (Signer, error) {
+	protected := signer.protected
+	if protected == nil {
+		protected = new(jose.Header)
+	}
+
+	keyOpt, resolved, err := ResolveSigningKey(signer.keyOpt, protected)
+	if err != nil {
+		return Signer{}, fmt.Errorf("jws.ResolveSigningKey: %w", err)
+	}
+
+	if signer.unprotected != nil {
+		if signer.unprotected.KeyID != "" {
+			resolved.KeyID = protected.KeyID
+		}
+		if signer.unprotected.Algorithm != "" {
+			resolved.Algorithm = protected.Algorithm
+		}
+	}
+	if signer.protected == nil && resolved.KeyID == "" && resolved.Algorithm == "" {
+		resolved = nil
+	}
+
+	return Signer{keyOpt: keyOpt, protected: resolved, unprotected: signer.unprotected}, nil
+}
+
+// VerifiedSignature is the signature verified by VerifyJSON.
+type VerifiedSignature struct {
+	// Index is the index of the verified signature in "signatures".
+	Index int
+	// Header is the JOSE Header, which is the union of the protected and the unprotected header.
+	Header *jose.Header
+}
+
+// VerifyJSON verifies the JWS JSON Serialization (general or flattened) and returns the first verified signature and the payload.
+// If no signature is verified, VerifyJSON returns ErrNoSignatureVerified wrapping the errors of each signature.
+//
+// Example:
+//
+//	verified, payload, err := jws.VerifyJSON(
+//		jws.UseECDSAKey(ecdsaPublicKey),
+//		data,
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	log.Printf("verified: signatures[%d] kid=%s", verified.Index, verified.Header.KeyID)
+func VerifyJSON(keyOption VerificationKeyOption, data []byte, opts ...VerifyOption) (verified *VerifiedSignature, payload []byte, err error) {
+	s, err := ParseJSON(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jws.ParseJSON: %w", err)
+	}
+
+	errs := make([]error, 0, len(s.Signatures))
+	for i, sig := range s.Signatures {
+		h, err := verifyJSONSignature(keyOption, s.Payload, sig, opts...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("signatures[%d]: %w", i, err))
+			continue
+		}
+
+		if !isPayloadBase64URLEncoded(h) {
+			return &VerifiedSignature{Index: i, Header: h}, []byte(s.Payload), nil
+		}
+
+		payload, err := base64.RawURLEncoding.DecodeString(s.Payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: payload: %w", err)
+		}
+
+		return &VerifiedSignature{Index: i, Header: h}, payload, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: %w", ErrNoSignatureVerified, errors.Join(errs...))
+}
+
+func verifyJSONSignature(keyOption VerificationKeyOption, payloadEncoded string, sig *JSONSignature, opts ...VerifyOption) (*jose.Header, error) {
+	if err := verifyUnprotectedHeader(sig.Header); err != nil {
+		return nil, err
+	}
+
+	var protected *jose.Header
+	if sig.Protected != "" {
+		protected = new(jose.Header)
+		if err := protected.Decode(sig.Protected); err != nil {
+			return nil, fmt.Errorf("(*jose.Header).Decode: %w", err)
+		}
+	}
+
+	h, err := mergeHeader(protected, sig.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyCritical(h, opts...); err != nil {
+		return nil, err
+	}
+
+	if err := verify(keyOption, h, sig.Protected+"."+payloadEncoded, sig.Signature); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// verifyUnprotectedHeader verifies that "crit" and "b64" are not in the unprotected header,
+// because they MUST be integrity protected.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.11
+//   - ref. https://www.rfc-editor.org/rfc/rfc7797#section-3
+func verifyUnprotectedHeader(unprotected *jose.Header) error {
+	if unprotected == nil {
+		return nil
+	}
+	if unprotected.Critical != nil {
+		return fmt.Errorf("crit in unprotected header: %w", ErrInvalidCriticalHeaderParameter)
+	}
+	if unprotected.Base64URLEncodePayload != nil {
+		return fmt.Errorf("b64 in unprotected header: %w", ErrInvalidCriticalHeaderParameter)
+	}
+	return nil
+}
+
+// mergeHeader returns the JOSE Header which is the union of the protected and the unprotected header.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-7.2.1
+func mergeHeader(protected, unprotected *jose.Header) (*jose.Header, error) {
+	merged := make(map[string]json.RawMessage)
+	for _, h := range []*jose.Header{protected, unprotected} {
+		if h == nil {
+			continue
+		}
+		b, err := json.Marshal(h)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %w", err)
+		}
+		m := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		for k, v := range m {
+			if _, ok := merged[k]; ok {
+				return nil, fmt.Errorf("name=%s: %w", k, ErrHeaderParameterIsDuplicated)
+			}
+			merged[k] = v
+		}
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	h := new(jose.Header)
+	if err := json.Unmarshal(b, h); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	if h.Algorithm == "" {
+		return nil, ErrAlgorithmIsEmpty
+	}
+
+	return h, nil
+}
@@ -0,0 +1,198 @@
+package jws_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/must"
+	testingz "github.com/kunitsucom/util.go/testing"
+)
+
+func TestSignJSON(t *testing.T) {
+	t.Parallel()
+
+	rsaPrivateKey := must.One(x509z.ParseRSAPrivateKeyPEM([]byte(testingz.TestRSAPrivateKey2048BitPEM)))
+	rsaPublicKey := must.One(x509z.ParseRSAPublicKeyPEM([]byte(testingz.TestRSAPublicKey2048BitPEM)))
+	ecdsaPrivateKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	ecdsaPublicKey := must.One(x509z.ParseECDSAPublicKeyPEM([]byte(testingz.TestECDSAPublicKey256BitPEM)))
+	payload := []byte(`{"iss":"joe","exp":1300819380,"http://example.com/is_root":true}`)
+
+	t.Run("success(general)", func(t *testing.T) {
+		t.Parallel()
+		s, err := jws.SignJSON(
+			payload,
+			jws.NewSigner(jws.WithRSAKey(rsaPrivateKey), jose.NewHeader(jwa.RS256), jose.NewHeader("", jose.WithKeyID("rsa-key"))),
+			jws.NewSigner(jws.WithECDSAKey(ecdsaPrivateKey), jose.NewHeader(jwa.ES256), jose.NewHeader("", jose.WithKeyID("ec-key"))),
+		)
+		if err != nil {
+			t.Fatalf("❌: jws.SignJSON: err != nil: %v", err)
+		}
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("❌: json.Marshal: err != nil: %v", err)
+		}
+		if expect := `"signatures":[{"protected":"eyJhbGciOiJSUzI1NiJ9","header":{"kid":"rsa-key"},"signature":`; !strings.Contains(string(data), expect) {
+			t.Errorf("❌: json.Marshal: expect(%s) not in actual(%s)", expect, data)
+		}
+
+		for _, tt := range []struct {
+			keyOption jws.VerificationKeyOption
+			index     int
+			kid       string
+		}{
+			{jws.UseRSAKey(rsaPublicKey), 0, "rsa-key"},
+			{jws.UseECDSAKey(ecdsaPublicKey), 1, "ec-key"},
+		} {
+			verified, actual, err := jws.VerifyJSON(tt.keyOption, data)
+			if err != nil {
+				t.Fatalf("❌: jws.VerifyJSON: err != nil: %v", err)
+			}
+			if verified.Index != tt.index || verified.Header.KeyID != tt.kid {
+				t.Errorf("❌: jws.VerifyJSON: expect(%d, %s) != actual(%d, %s)", tt.index, tt.kid, verified.Index, verified.Header.KeyID)
+			}
+			if string(payload) != string(actual) {
+				t.Errorf("❌: jws.VerifyJSON: expect(%s) != actual(%s)", payload, actual)
+			}
+		}
+	})
+
+	t.Run("success(flattened)", func(t *testing.T) {
+		t.Parallel()
+		s, err := jws.SignJSON(
+			payload,
+			jws.NewSigner(jws.WithECDSAKey(ecdsaPrivateKey), nil, jose.NewHeader(jwa.ES256, jose.WithKeyID("ec-key"))),
+		)
+		if err != nil {
+			t.Fatalf("❌: jws.SignJSON: err != nil: %v", err)
+		}
+		data, err := s.MarshalFlattenedJSON()
+		if err != nil {
+			t.Fatalf("❌: (*jws.JSONSerialization).MarshalFlattenedJSON: err != nil: %v", err)
+		}
+		if strings.Contains(string(data), `"signatures"`) || strings.Contains(string(data), `"protected"`) {
+			t.Errorf("❌: (*jws.JSONSerialization).MarshalFlattenedJSON: %s", data)
+		}
+		verified, actual, err := jws.VerifyJSON(jws.UseECDSAKey(ecdsaPublicKey), data)
+		if err != nil {
+			t.Fatalf("❌: jws.VerifyJSON: err != nil: %v", err)
+		}
+		if verified.Index != 0 || verified.Header.KeyID != "ec-key" || verified.Header.Algorithm != jwa.ES256 {
+			t.Errorf("❌: jws.VerifyJSON: %+v", verified.Header)
+		}
+		if string(payload) != string(actual) {
+			t.Errorf("❌: jws.VerifyJSON: expect(%s) != actual(%s)", payload, actual)
+		}
+	})
+
+	t.Run("failure(jws.ErrSignerIsEmpty)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jws.SignJSON(payload); !errors.Is(err, jws.ErrSignerIsEmpty) {
+			t.Errorf("❌: jws.SignJSON: err != jws.ErrSignerIsEmpty: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrHeaderParameterIsDuplicated)", func(t *testing.T) {
+		t.Parallel()
+		_, err := jws.SignJSON(payload, jws.NewSigner(jws.WithRSAKey(rsaPrivateKey), jose.NewHeader(jwa.RS256), jose.NewHeader(jwa.RS256)))
+		if !errors.Is(err, jws.ErrHeaderParameterIsDuplicated) {
+			t.Errorf("❌: jws.SignJSON: err != jws.ErrHeaderParameterIsDuplicated: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrAlgorithmIsEmpty)", func(t *testing.T) {
+		t.Parallel()
+		_, err := jws.SignJSON(payload, jws.NewSigner(jws.WithRSAKey(rsaPrivateKey), nil, jose.NewHeader("", jose.WithKeyID("rsa-key"))))
+		if !errors.Is(err, jws.ErrAlgorithmIsEmpty) {
+			t.Errorf("❌: jws.SignJSON: err != jws.ErrAlgorithmIsEmpty: %v", err)
+		}
+	})
+
+	t.Run("failure(jwa.ErrInvalidKeyReceived)", func(t *testing.T) {
+		t.Parallel()
+		_, err := jws.SignJSON(payload, jws.NewSigner(jws.WithECDSAKey(ecdsaPrivateKey), jose.NewHeader(jwa.RS256), nil))
+		if !errors.Is(err, jwa.ErrInvalidKeyReceived) {
+			t.Errorf("❌: jws.SignJSON: err != jwa.ErrInvalidKeyReceived: %v", err)
+		}
+	})
+
+	t.Run("failure(MarshalFlattenedJSON)", func(t *testing.T) {
+		t.Parallel()
+		s, err := jws.SignJSON(
+			payload,
+			jws.NewSigner(jws.WithRSAKey(rsaPrivateKey), jose.NewHeader(jwa.RS256), nil),
+			jws.NewSigner(jws.WithECDSAKey(ecdsaPrivateKey), jose.NewHeader(jwa.ES256), nil),
+		)
+		if err != nil {
+			t.Fatalf("❌: jws.SignJSON: err != nil: %v", err)
+		}
+		if _, err := s.MarshalFlattenedJSON(); !errors.Is(err, jws.ErrInvalidJSONSerializationReceived) {
+			t.Errorf("❌: (*jws.JSONSerialization).MarshalFlattenedJSON: err != jws.ErrInvalidJSONSerializationReceived: %v", err)
+		}
+	})
+}
+
+func TestVerifyJSON(t *testing.T) {
+	t.Parallel()
+
+	rsaPrivateKey := must.One(x509z.ParseRSAPrivateKeyPEM([]byte(testingz.TestRSAPrivateKey2048BitPEM)))
+	ecdsaPublicKey := must.One(x509z.ParseECDSAPublicKeyPEM([]byte(testingz.TestECDSAPublicKey256BitPEM)))
+
+	t.Run("success(RFC7515 A.7)", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc7515#appendix-A.7 (with HS256 key of Appendix A.1)
+		key := must.One(base64.RawURLEncoding.DecodeString("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"))
+		data := []byte(`{"payload":"eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ","protected":"eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9","signature":"dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"}`)
+		verified, _, err := jws.VerifyJSON(jws.UseHMACKey(key), data)
+		if err != nil {
+			t.Fatalf("❌: jws.VerifyJSON: err != nil: %v", err)
+		}
+		if verified.Header.Algorithm != jwa.HS256 || verified.Header.Type != "JWT" {
+			t.Errorf("❌: jws.VerifyJSON: %+v", verified.Header)
+		}
+	})
+
+	t.Run("failure(jws.ErrNoSignatureVerified)", func(t *testing.T) {
+		t.Parallel()
+		s := must.One(jws.SignJSON([]byte("payload"), jws.NewSigner(jws.WithRSAKey(rsaPrivateKey), jose.NewHeader(jwa.RS256), nil)))
+		data := must.One(json.Marshal(s))
+		if _, _, err := jws.VerifyJSON(jws.UseECDSAKey(ecdsaPublicKey), data); !errors.Is(err, jws.ErrNoSignatureVerified) || !errors.Is(err, jwa.ErrInvalidKeyReceived) {
+			t.Errorf("❌: jws.VerifyJSON: err != jws.ErrNoSignatureVerified: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrInvalidJSONSerializationReceived)", func(t *testing.T) {
+		t.Parallel()
+		for _, data := range []string{
+			`{"payload":"cGF5bG9hZA"}`,
+			`{"payload":"cGF5bG9hZA","signatures":[]}`,
+			`{"payload":"cGF5bG9hZA","signatures":[null]}`,
+			`{"payload":"cGF5bG9hZA","signatures":[{"signature":"c2ln"}],"signature":"c2ln"}`,
+		} {
+			if _, _, err := jws.VerifyJSON(jws.UseECDSAKey(ecdsaPublicKey), []byte(data)); !errors.Is(err, jws.ErrInvalidJSONSerializationReceived) {
+				t.Errorf("❌: jws.VerifyJSON: %s: err != jws.ErrInvalidJSONSerializationReceived: %v", data, err)
+			}
+		}
+	})
+
+	t.Run("failure(json.Unmarshal)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jws.ParseJSON([]byte(`invalid`)); err == nil || !strings.Contains(err.Error(), "json.Unmarshal: ") {
+			t.Errorf("❌: jws.ParseJSON: err != json.Unmarshal: %v", err)
+		}
+	})
+
+	t.Run("failure((*jose.Header).Decode)", func(t *testing.T) {
+		t.Parallel()
+		data := []byte(`{"payload":"cGF5bG9hZA","protected":"inv@lid","signature":"c2ln"}`)
+		if _, _, err := jws.VerifyJSON(jws.UseECDSAKey(ecdsaPublicKey), data); err == nil || !strings.Contains(err.Error(), "(*jose.Header).Decode: ") {
+			t.Errorf("❌: jws.VerifyJSON: err != (*jose.Header).Decode: %v", err)
+		}
+	})
+}
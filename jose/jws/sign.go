@@ -2,13 +2,28 @@ package jws
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
+	"errors"
+	"fmt"
 
+	"github.com/kunitsucom/util.go/jose"
 	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
 )
 
+var ErrSigningKeyIsEmpty = errors.New(`jws: signing key is empty`)
+
 type SigningKeyOption struct {
-	key any
+	key      any
+	jwk      *jwk.JSONWebKey
+	provider SigningKeyProvider
+}
+
+// SigningKeyProvider provides the current signing key.
+// *jwk.KeyManager implements SigningKeyProvider.
+type SigningKeyProvider interface {
+	SigningKey() (*jwk.JSONWebKey, error)
 }
 
 func WithKey(key any) SigningKeyOption {
@@ -29,6 +44,95 @@ func WithECDSAKey(key *ecdsa.PrivateKey) SigningKeyOption {
 	return WithKey(key)
 }
 
+func WithEd25519Key(key ed25519.PrivateKey) SigningKeyOption {
+	return WithKey(key)
+}
+
+// WithJSONWebKey is a SigningKeyOption to sign with the private (or symmetric) JSON Web Key.
+// The "kid" and "alg" of the JSON Web Key are set to the JOSE Header if they are empty.
+func WithJSONWebKey(key *jwk.JSONWebKey) SigningKeyOption {
+	return SigningKeyOption{
+		jwk: key,
+	}
+}
+
+// WithSigningKeyProvider is a SigningKeyOption to sign with the key provided by provider at the time of signing.
+// The "kid" and "alg" of the key are set to the JOSE Header if they are empty.
+//
+// Example:
+//
+//	keyManager, err := jwk.NewKeyManager("EC", jwa.ES256)
+//	if err != nil {
+//		return err
+//	}
+//	token, err := jwt.New(jws.WithSigningKeyProvider(keyManager), jose.NewHeader(jwa.ES256), claimsSet)
+func WithSigningKeyProvider(provider SigningKeyProvider) SigningKeyOption {
+	return SigningKeyOption{
+		provider: provider,
+	}
+}
+
+// ResolveSigningKey resolves the key of keyOpt, and returns the SigningKeyOption of the resolved key
+// and the copy of header which "kid" and "alg" are set from the resolved JSON Web Key if they are empty.
+// The returned SigningKeyOption signs with the same key even if the provider rotates the key.
+func ResolveSigningKey(keyOpt SigningKeyOption, header *jose.Header) (SigningKeyOption, *jose.Header, error) {
+	key, jsonWebKey, err := keyOpt.resolve()
+	if err != nil {
+		return SigningKeyOption{}, nil, err
+	}
+
+	if jsonWebKey == nil {
+		return WithKey(key), header, nil
+	}
+
+	h := new(jose.Header)
+	if header != nil {
+		*h = *header
+	}
+	if h.KeyID == "" {
+		h.KeyID = jsonWebKey.KeyID
+	}
+	if h.Algorithm == "" {
+		h.Algorithm = jsonWebKey.Algorithm
+	}
+
+	return WithKey(key), h, nil
+}
+
+func (keyOpt SigningKeyOption) resolve() (key any, jsonWebKey *jwk.JSONWebKey, err error) {
+	if keyOpt.key != nil {
+		return keyOpt.key, nil, nil
+	}
+
+	jsonWebKey = keyOpt.jwk
+	if keyOpt.provider != nil {
+		k, err := keyOpt.provider.SigningKey()
+		if err != nil {
+			return nil, nil, fmt.Errorf("jws.SigningKeyProvider.SigningKey: %w", err)
+		}
+		jsonWebKey = k
+	}
+
+	if jsonWebKey == nil {
+		return nil, nil, ErrSigningKeyIsEmpty
+	}
+
+	key, err = jsonWebKey.DecodePrivateKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("(*jwk.JSONWebKey).DecodePrivateKey: %w", err)
+	}
+
+	return key, jsonWebKey, nil
+}
+
 func Sign(alg string, keyOpt SigningKeyOption, signingInput string) (signatureEncoded string, err error) {
+	if keyOpt.key == nil && (keyOpt.jwk != nil || keyOpt.provider != nil) {
+		key, _, err := keyOpt.resolve()
+		if err != nil {
+			return "", err
+		}
+		keyOpt = WithKey(key)
+	}
+
 	return jwa.JWS(alg).Sign(keyOpt.key, signingInput) //nolint:wrapcheck
 }
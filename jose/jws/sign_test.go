@@ -0,0 +1,79 @@
+package jws_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/must"
+)
+
+func TestResolveSigningKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(WithSigningKeyProvider)", func(t *testing.T) {
+		t.Parallel()
+		m := must.One(jwk.NewKeyManager("EC", jwa.ES256))
+		key := must.One(m.SigningKey())
+
+		detached, err := jws.SignDetached(jws.WithSigningKeyProvider(m), jose.NewHeader(""), []byte("payload"))
+		if err != nil {
+			t.Fatalf("❌: jws.SignDetached: err != nil: %v", err)
+		}
+		header, err := jws.VerifyDetached(jws.UseKey(must.One(key.DecodePublicKey())), detached, []byte("payload"))
+		if err != nil {
+			t.Fatalf("❌: jws.VerifyDetached: err != nil: %v", err)
+		}
+		if header.KeyID != key.KeyID || header.Algorithm != jwa.ES256 {
+			t.Errorf("❌: jws.SignDetached: kid=%s alg=%s", header.KeyID, header.Algorithm)
+		}
+
+		s, err := jws.SignJSON([]byte("payload"), jws.NewSigner(jws.WithSigningKeyProvider(m), nil, jose.NewHeader("", jose.WithKeyID("unprotected"))))
+		if err != nil {
+			t.Fatalf("❌: jws.SignJSON: err != nil: %v", err)
+		}
+		h := new(jose.Header)
+		if err := h.Decode(s.Signatures[0].Protected); err != nil {
+			t.Fatalf("❌: (*jose.Header).Decode: err != nil: %v", err)
+		}
+		if h.KeyID != "" || h.Algorithm != jwa.ES256 {
+			t.Errorf("❌: jws.SignJSON: kid=%s alg=%s", h.KeyID, h.Algorithm)
+		}
+	})
+
+	t.Run("success(WithJSONWebKey)", func(t *testing.T) {
+		t.Parallel()
+		key := must.One(jwk.Generate("oct", jwa.HS256))
+		keyOpt, header, err := jws.ResolveSigningKey(jws.WithJSONWebKey(key), jose.NewHeader(jwa.HS256, jose.WithKeyID("kid")))
+		if err != nil {
+			t.Fatalf("❌: jws.ResolveSigningKey: err != nil: %v", err)
+		}
+		if header.KeyID != "kid" {
+			t.Errorf("❌: jws.ResolveSigningKey: kid is overwritten: %s", header.KeyID)
+		}
+		signature := must.One(jws.Sign(jwa.HS256, keyOpt, "input"))
+		if err := jwa.JWS(jwa.HS256).Verify(must.One(key.DecodeSymmetricKey()), "input", signature); err != nil {
+			t.Errorf("❌: jwa.JWS.Verify: err != nil: %v", err)
+		}
+		if actual := must.One(jws.Sign(jwa.HS256, jws.WithJSONWebKey(key), "input")); actual != signature {
+			t.Errorf("❌: jws.Sign: expect(%s) != actual(%s)", signature, actual)
+		}
+	})
+
+	t.Run("failure(jws.ErrSigningKeyIsEmpty)", func(t *testing.T) {
+		t.Parallel()
+		if _, _, err := jws.ResolveSigningKey(jws.WithJSONWebKey(nil), jose.NewHeader(jwa.HS256)); !errors.Is(err, jws.ErrSigningKeyIsEmpty) {
+			t.Errorf("❌: jws.ResolveSigningKey: err != jws.ErrSigningKeyIsEmpty: %v", err)
+		}
+	})
+
+	t.Run("failure(jwk.ErrInvalidKey)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jws.Sign(jwa.HS256, jws.WithJSONWebKey(&jwk.JSONWebKey{KeyType: "oct"}), "input"); !errors.Is(err, jwk.ErrInvalidKey) {
+			t.Errorf("❌: jws.Sign: err != jwk.ErrInvalidKey: %v", err)
+		}
+	})
+}
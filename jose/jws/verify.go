@@ -3,6 +3,7 @@ package jws
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"errors"
 	"fmt"
@@ -10,13 +11,16 @@ import (
 	"github.com/kunitsucom/util.go/jose"
 	"github.com/kunitsucom/util.go/jose/jwa"
 	"github.com/kunitsucom/util.go/jose/jwk"
+	slicez "github.com/kunitsucom/util.go/slices"
 )
 
 // - ref. JSON Web Signature (JWS) https://www.rfc-editor.org/rfc/rfc7515
 
 var (
-	ErrInvalidTokenReceived = errors.New(`jws: invalid token received, token must have 3 parts`)
-	ErrInvalidKeyOption     = errors.New(`jws: invalid key option`)
+	ErrInvalidTokenReceived                   = errors.New(`jws: invalid token received, token must have 3 parts`)
+	ErrInvalidKeyOption                       = errors.New(`jws: invalid key option`)
+	ErrInvalidCriticalHeaderParameter         = errors.New(`jws: invalid crit header parameter`)
+	ErrCriticalHeaderParameterIsNotUnderstood = errors.New(`jws: crit header parameter is not understood`)
 )
 
 type VerificationKeyOption struct {
@@ -26,6 +30,8 @@ type VerificationKeyOption struct {
 	useJWKSetURL                bool
 	ctx                         context.Context //nolint:containedctx
 	jwkSetURL                   string
+	jwkClient                   *jwk.Client
+	x509CertificateChain        *x509CertificateChainOption
 }
 
 func UseKey(key any) VerificationKeyOption {
@@ -46,6 +52,10 @@ func UseECDSAKey(key *ecdsa.PublicKey) VerificationKeyOption {
 	return UseKey(key)
 }
 
+func UseEd25519Key(key ed25519.PublicKey) VerificationKeyOption {
+	return UseKey(key)
+}
+
 func UseJSONWebKey() VerificationKeyOption {
 	return VerificationKeyOption{
 		useJSONWebKey: true,
@@ -68,7 +78,42 @@ func UseJWKSetURL(ctx context.Context, jwkSetURL string) VerificationKeyOption {
 	}
 }
 
-func Verify(keyOption VerificationKeyOption, jwt string) (header *jose.Header, err error) {
+// UseJWKSetURLWithClient is a VerificationKeyOption to verify with jwkSetURL, fetched by client instead of jwk.Default.
+func UseJWKSetURLWithClient(ctx context.Context, client *jwk.Client, jwkSetURL string) VerificationKeyOption {
+	return VerificationKeyOption{
+		ctx:          ctx,
+		useJWKSetURL: true,
+		jwkSetURL:    jwkSetURL,
+		jwkClient:    client,
+	}
+}
+
+type verifyOption struct {
+	crit []string
+}
+
+type VerifyOption func(*verifyOption)
+
+// VerifyCriticalHeaderParameters is a VerifyOption to specify the names of
+// the "crit" header parameters which the application understands and processes.
+// "b64" is always understood.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.11
+func VerifyCriticalHeaderParameters(names ...string) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.crit = append(vo.crit, names...)
+	}
+}
+
+// Verify
+//
+// Example:
+//
+//	header, err := jws.Verify(
+//		jws.UseRSAKey(publicKey),
+//		token,
+//	)
+func Verify(keyOption VerificationKeyOption, jwt string, opts ...VerifyOption) (header *jose.Header, err error) {
 	headerEncoded, payloadEncoded, signatureEncoded, err := Parse(jwt)
 	if err != nil {
 		return nil, fmt.Errorf("jws.ParseHeader: %w", err)
@@ -79,25 +124,91 @@ func Verify(keyOption VerificationKeyOption, jwt string) (header *jose.Header, e
 		return nil, fmt.Errorf("(*jose.Header).Decode: %w", err)
 	}
 
+	if err := verifyCritical(h, opts...); err != nil {
+		return nil, err
+	}
+
 	signingInput := headerEncoded + "." + payloadEncoded
 
+	if err := verify(keyOption, h, signingInput, signatureEncoded); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func verify(keyOption VerificationKeyOption, h *jose.Header, signingInput, signatureEncoded string) error {
 	if keyOption.key != nil {
-		return nil, verifyWithKey(h.Algorithm, keyOption.key, signingInput, signatureEncoded)
+		return verifyWithKey(h.Algorithm, keyOption.key, signingInput, signatureEncoded)
 	}
 
 	if keyOption.useJSONWebKey {
-		return nil, verifyWithJSONWebKey(h, signingInput, signatureEncoded)
+		return verifyWithJSONWebKey(h, signingInput, signatureEncoded)
 	}
 
 	if keyOption.useJWKSetURLHeaderParameter {
-		return nil, verifyWithJWKSetURL(keyOption.ctx, h.JWKSetURL, h, signingInput, signatureEncoded)
+		return verifyWithJWKSetURL(keyOption.ctx, jwk.Default, h.JWKSetURL, h, signingInput, signatureEncoded)
 	}
 
 	if keyOption.useJWKSetURL {
-		return nil, verifyWithJWKSetURL(keyOption.ctx, keyOption.jwkSetURL, h, signingInput, signatureEncoded)
+		client := keyOption.jwkClient
+		if client == nil {
+			client = jwk.Default
+		}
+		return verifyWithJWKSetURL(keyOption.ctx, client, keyOption.jwkSetURL, h, signingInput, signatureEncoded)
+	}
+
+	if keyOption.x509CertificateChain != nil {
+		return verifyWithX509CertificateChain(keyOption.x509CertificateChain, h, signingInput, signatureEncoded)
+	}
+
+	return ErrInvalidKeyOption
+}
+
+//nolint:gochecknoglobals
+var registeredHeaderParameterNames = map[string]bool{
+	"alg": true, "jku": true, "jwk": true, "kid": true, "x5u": true, "x5c": true, "x5t": true, "x5t#S256": true, "typ": true, "cty": true, "crit": true,
+}
+
+// verifyCritical processes "crit" header parameter.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.11
+func verifyCritical(h *jose.Header, opts ...VerifyOption) error {
+	if h.Critical == nil {
+		if h.Base64URLEncodePayload != nil && !*h.Base64URLEncodePayload {
+			return fmt.Errorf("b64=false is not in crit: %w", ErrInvalidCriticalHeaderParameter)
+		}
+		return nil
+	}
+
+	if len(h.Critical) == 0 {
+		return fmt.Errorf("crit is empty: %w", ErrInvalidCriticalHeaderParameter)
+	}
+
+	vo := &verifyOption{crit: []string{"b64"}}
+	for _, opt := range opts {
+		opt(vo)
+	}
+
+	for _, name := range h.Critical {
+		if registeredHeaderParameterNames[name] {
+			return fmt.Errorf("crit=%s: %w", name, ErrInvalidCriticalHeaderParameter)
+		}
+		if !slicez.Contains(vo.crit, name) {
+			return fmt.Errorf("crit=%s: %w", name, ErrCriticalHeaderParameterIsNotUnderstood)
+		}
+		if name == "b64" {
+			if h.Base64URLEncodePayload == nil {
+				return fmt.Errorf("crit=%s: not present: %w", name, ErrInvalidCriticalHeaderParameter)
+			}
+			continue
+		}
+		if _, ok := h.PrivateHeaderParameters[name]; !ok {
+			return fmt.Errorf("crit=%s: not present: %w", name, ErrInvalidCriticalHeaderParameter)
+		}
 	}
 
-	return nil, ErrInvalidKeyOption
+	return nil
 }
 
 func verifyWithKey(alg string, key any, signingInput, signatureEncoded string) error {
@@ -117,20 +228,21 @@ func verifyWithJSONWebKey(header *jose.Header, signingInput, signatureEncoded st
 	return jwa.JWS(header.Algorithm).Verify(pub, signingInput, signatureEncoded) //nolint:wrapcheck
 }
 
-func verifyWithJWKSetURL(ctx context.Context, jwkSetURL string, header *jose.Header, signingInput, signatureEncoded string) error {
-	jwks, err := jwk.GetJWKSet(ctx, jwkSetURL)
+func verifyWithJWKSetURL(ctx context.Context, client *jwk.Client, jwkSetURL string, header *jose.Header, signingInput, signatureEncoded string) error {
+	jwks, err := client.GetJWKSet(ctx, jwkSetURL)
 	if err != nil {
-		return fmt.Errorf("jwk.GetJWKSet: %w", err)
+		return fmt.Errorf("(*jwk.Client).GetJWKSet: %w", err)
 	}
 
 	var jsonWebKey *jwk.JSONWebKey
 
-	if len(jwks.Keys) == 1 {
+	if len(jwks.Keys) == 1 && (header.KeyID == "" || jwks.Keys[0].KeyID == "") {
 		jsonWebKey = jwks.Keys[0]
 	} else {
-		key, err := jwks.GetJSONWebKey(header.KeyID)
+		// NOTE: refetch JWK Set if kid is not found, for following key rotation.
+		key, err := client.GetJSONWebKey(ctx, jwkSetURL, header.KeyID)
 		if err != nil {
-			return fmt.Errorf("jwks.GetJSONWebKey: %w", err)
+			return fmt.Errorf("(*jwk.Client).GetJSONWebKey: %w", err)
 		}
 		jsonWebKey = key
 	}
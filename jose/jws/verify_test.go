@@ -44,6 +44,26 @@ func TestVerify(t *testing.T) {
 		}
 	})
 
+	t.Run("success(EdDSA)", func(t *testing.T) {
+		t.Parallel()
+		// - ref. https://www.rfc-editor.org/rfc/rfc8037#appendix-A.4
+		privateKey := must.One(x509z.ParseEd25519PrivateKeyPEM([]byte(testingz.TestEd25519PrivateKeyPEM)))
+		publicKey := must.One(x509z.ParseEd25519PublicKeyPEM([]byte(testingz.TestEd25519PublicKeyPEM)))
+		signingInput := "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc"
+		signatureEncodedExpected := "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+		signatureEncoded, err := jws.Sign(jwa.EdDSA, jws.WithEd25519Key(privateKey), signingInput)
+		if err != nil {
+			t.Errorf("❌: jws.Sign: err != nil: %v", err)
+		}
+		if actual, expect := signatureEncoded, signatureEncodedExpected; actual != expect {
+			t.Errorf("❌: jws.Sign: actual != expect: %v", actual)
+		}
+		jwt := signingInput + "." + signatureEncoded
+		if _, err := jws.Verify(jws.UseEd25519Key(publicKey), jwt); err != nil {
+			t.Errorf("❌: jws.Verify: err != nil: %v", err)
+		}
+	})
+
 	t.Run("success(RS256)", func(t *testing.T) {
 		t.Parallel()
 		publicKey := must.One(x509z.ParseRSAPublicKeyPEM([]byte(testingz.TestRSAPublicKey2048BitPEM)))
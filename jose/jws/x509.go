@@ -0,0 +1,250 @@
+package jws
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // for x5t
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kunitsucom/util.go/jose"
+	slicez "github.com/kunitsucom/util.go/slices"
+	timez "github.com/kunitsucom/util.go/time"
+)
+
+var (
+	ErrX509CertificateChainIsEmpty         = errors.New(`jws: x5c and x5u are empty`)
+	ErrX509CertificateThumbprintIsNotMatch = errors.New(`jws: x5t or x5t#S256 is not match`)
+	ErrX509URLResponseIsNotOK              = errors.New(`jws: x5u response is not ok`)
+	ErrX509URLIsNotHTTPS                   = errors.New(`jws: x5u is not https`)
+	ErrX509URLResponseIsTooLarge           = errors.New(`jws: x5u response is too large`)
+	ErrX509RootsIsNil                      = errors.New(`jws: x509 roots is nil`)
+)
+
+type x509CertificateChainOption struct {
+	roots      *x509.CertPool
+	ctx        context.Context //nolint:containedctx
+	keyUsages  []x509.ExtKeyUsage
+	dnsName    string
+	useX509URL bool
+	client     *http.Client
+}
+
+type X509CertificateChainOption func(*x509CertificateChainOption)
+
+// WithX509Context sets the context to fetch "x5u" and to get the current time by timez.Now for validating the certificates.
+func WithX509Context(ctx context.Context) X509CertificateChainOption {
+	return func(o *x509CertificateChainOption) {
+		o.ctx = ctx
+	}
+}
+
+// WithX509KeyUsages sets the acceptable extended key usages of the leaf certificate.
+// The default is x509.ExtKeyUsageAny.
+func WithX509KeyUsages(keyUsages ...x509.ExtKeyUsage) X509CertificateChainOption {
+	return func(o *x509CertificateChainOption) {
+		o.keyUsages = append(o.keyUsages, keyUsages...)
+	}
+}
+
+// WithX509DNSName sets the name which the leaf certificate must be valid for.
+func WithX509DNSName(dnsName string) X509CertificateChainOption {
+	return func(o *x509CertificateChainOption) {
+		o.dnsName = dnsName
+	}
+}
+
+// WithX509URL enables fetching the certificate chain from "x5u" with client when "x5c" is empty.
+// If client is nil, a client which does not follow redirects is used.
+// "x5u" must be https, and the response must not exceed 1 MiB.
+func WithX509URL(client *http.Client) X509CertificateChainOption {
+	return func(o *x509CertificateChainOption) {
+		o.useX509URL = true
+		o.client = client
+	}
+}
+
+// UseX509CertificateChain is a VerificationKeyOption to verify with the public key of the leaf certificate in "x5c" (or "x5u").
+// The certificate chain is validated against roots, and the leaf certificate is checked against "x5t" and "x5t#S256" if present.
+//
+// roots must not be nil, otherwise the verification fails with ErrX509RootsIsNil,
+// since the system roots would trust any certificate issued by the public CAs as the signer.
+// If it is intended, pass x509.SystemCertPool() explicitly.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.5
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.6
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.7
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.8
+//
+// Example:
+//
+//	header, err := jws.Verify(
+//		jws.UseX509CertificateChain(roots, jws.WithX509KeyUsages(x509.ExtKeyUsageClientAuth)),
+//		token,
+//	)
+func UseX509CertificateChain(roots *x509.CertPool, opts ...X509CertificateChainOption) VerificationKeyOption {
+	o := &x509CertificateChainOption{
+		roots: roots,
+		ctx:   context.Background(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return VerificationKeyOption{
+		x509CertificateChain: o,
+	}
+}
+
+func verifyWithX509CertificateChain(o *x509CertificateChainOption, h *jose.Header, signingInput, signatureEncoded string) error {
+	if o.roots == nil {
+		return ErrX509RootsIsNil
+	}
+
+	certs, err := x509CertificateChain(o, h)
+	if err != nil {
+		return err
+	}
+
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	keyUsages := o.keyUsages
+	if len(keyUsages) == 0 {
+		keyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         o.roots,
+		Intermediates: intermediates,
+		CurrentTime:   timez.Now(o.ctx),
+		KeyUsages:     keyUsages,
+		DNSName:       o.dnsName,
+	}); err != nil {
+		return fmt.Errorf("(*x509.Certificate).Verify: %w", err)
+	}
+
+	if err := verifyX509CertificateThumbprint(h, leaf); err != nil {
+		return err
+	}
+
+	return verifyWithKey(h.Algorithm, leaf.PublicKey, signingInput, signatureEncoded)
+}
+
+func x509CertificateChain(o *x509CertificateChainOption, h *jose.Header) ([]*x509.Certificate, error) {
+	if len(h.X509CertificateChain) > 0 {
+		certs := make([]*x509.Certificate, 0, len(h.X509CertificateChain))
+		for i, x5c := range h.X509CertificateChain {
+			// Each string in the array is a base64-encoded (not base64url-encoded) DER PKIX certificate value.
+			der, err := base64.StdEncoding.DecodeString(x5c)
+			if err != nil {
+				return nil, fmt.Errorf("x5c[%d]: base64.StdEncoding.DecodeString: %w", i, err)
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("x5c[%d]: x509.ParseCertificate: %w", i, err)
+			}
+			certs = append(certs, cert)
+		}
+		return certs, nil
+	}
+
+	if o.useX509URL && h.X509URL != "" {
+		return fetchX509CertificateChain(o, h.X509URL)
+	}
+
+	return nil, ErrX509CertificateChainIsEmpty
+}
+
+func fetchX509CertificateChain(o *x509CertificateChainOption, x5u string) ([]*x509.Certificate, error) {
+	client := o.client
+	if client == nil {
+		client = &http.Client{
+			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+
+	req, err := http.NewRequestWithContext(o.ctx, http.MethodGet, x5u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	// The protocol used to acquire the resource MUST provide integrity protection, i.e. TLS.
+	//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.5
+	if req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("x5u=%s: %w", x5u, ErrX509URLIsNotHTTPS)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("(*http.Client).Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Limit the response size to mitigate memory exhaustion by a malicious "x5u".
+	const limit = 1024 * 1024
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if len(body) > limit {
+		return nil, fmt.Errorf("limit=%d: %w", limit, ErrX509URLResponseIsTooLarge)
+	}
+
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		const cutOffSize = 100
+		return nil, fmt.Errorf("code=%d body=%q: %w", resp.StatusCode, string(slicez.CutOff(body, cutOffSize)), ErrX509URLResponseIsNotOK)
+	}
+
+	// The certificate or certificate chain is in PEM-encoded form.
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, body = pem.Decode(body)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("x509.ParseCertificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("x5u=%s: %w", x5u, ErrX509CertificateChainIsEmpty)
+	}
+
+	return certs, nil
+}
+
+func verifyX509CertificateThumbprint(h *jose.Header, leaf *x509.Certificate) error {
+	if h.X509CertificateSHA1Thumbprint != "" {
+		sum := sha1.Sum(leaf.Raw) //nolint:gosec // for x5t
+		if h.X509CertificateSHA1Thumbprint != base64.RawURLEncoding.EncodeToString(sum[:]) {
+			return fmt.Errorf("x5t=%s: %w", h.X509CertificateSHA1Thumbprint, ErrX509CertificateThumbprintIsNotMatch)
+		}
+	}
+
+	if h.X509CertificateSHA256Thumbprint != "" {
+		sum := sha256.Sum256(leaf.Raw)
+		if h.X509CertificateSHA256Thumbprint != base64.RawURLEncoding.EncodeToString(sum[:]) {
+			return fmt.Errorf("x5t#S256=%s: %w", h.X509CertificateSHA256Thumbprint, ErrX509CertificateThumbprintIsNotMatch)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package jws_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/must"
+	testingz "github.com/kunitsucom/util.go/testing"
+	timez "github.com/kunitsucom/util.go/time"
+)
+
+func newTestCertificate(t *testing.T, template, parent *x509.Certificate, pub crypto.PublicKey, priv crypto.Signer) *x509.Certificate {
+	t.Helper()
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+	if err != nil {
+		t.Fatalf("❌: x509.CreateCertificate: err != nil: %v", err)
+	}
+	return must.One(x509.ParseCertificate(der))
+}
+
+func TestUseX509CertificateChain(t *testing.T) {
+	t.Parallel()
+
+	notBefore := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.AddDate(1, 0, 0)
+	ctx := timez.WithContext(context.Background(), notBefore.AddDate(0, 6, 0))
+
+	rootKey := must.One(ecdsa.GenerateKey(elliptic.P256(), rand.Reader))
+	rootTemplate := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "root"}, NotBefore: notBefore, NotAfter: notAfter, IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}
+	root := newTestCertificate(t, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	intermediateKey := must.One(ecdsa.GenerateKey(elliptic.P256(), rand.Reader))
+	intermediateTemplate := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "intermediate"}, NotBefore: notBefore, NotAfter: notAfter, IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}
+	intermediate := newTestCertificate(t, intermediateTemplate, root, &intermediateKey.PublicKey, rootKey)
+	leafKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	leafTemplate := &x509.Certificate{SerialNumber: big.NewInt(3), Subject: pkix.Name{CommonName: "partner.example.com"}, DNSNames: []string{"partner.example.com"}, NotBefore: notBefore, NotAfter: notAfter, KeyUsage: x509.KeyUsageDigitalSignature, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+	leaf := newTestCertificate(t, leafTemplate, intermediate, &leafKey.PublicKey, intermediateKey)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	x5c := []string{base64.StdEncoding.EncodeToString(leaf.Raw), base64.StdEncoding.EncodeToString(intermediate.Raw)}
+	x5tS256 := sha256.Sum256(leaf.Raw)
+
+	sign := func(header *jose.Header) string {
+		headerEncoded := must.One(header.Encode())
+		signingInput := headerEncoded + ".cGF5bG9hZA"
+		return signingInput + "." + must.One(jws.Sign(header.Algorithm, jws.WithECDSAKey(leafKey), signingInput))
+	}
+
+	t.Run("success(x5c)", func(t *testing.T) {
+		t.Parallel()
+		token := sign(jose.NewHeader(jwa.ES256, jose.WithX509CertificateChain(x5c), jose.WithX509CertificateSHA256Thumbprint(base64.RawURLEncoding.EncodeToString(x5tS256[:]))))
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx), jws.WithX509KeyUsages(x509.ExtKeyUsageClientAuth), jws.WithX509DNSName("partner.example.com")), token); err != nil {
+			t.Errorf("❌: jws.Verify: err != nil: %v", err)
+		}
+	})
+
+	t.Run("success(x5u)", func(t *testing.T) {
+		t.Parallel()
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_ = pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})
+			_ = pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: intermediate.Raw})
+		}))
+		t.Cleanup(s.Close)
+		token := sign(jose.NewHeader(jwa.ES256, jose.WithX509URL(s.URL)))
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx), jws.WithX509URL(s.Client())), token); err != nil {
+			t.Errorf("❌: jws.Verify: err != nil: %v", err)
+		}
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx)), token); !errors.Is(err, jws.ErrX509CertificateChainIsEmpty) {
+			t.Errorf("❌: jws.Verify: err != jws.ErrX509CertificateChainIsEmpty: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrX509URLResponseIsNotOK)", func(t *testing.T) {
+		t.Parallel()
+		s := httptest.NewTLSServer(http.NotFoundHandler())
+		t.Cleanup(s.Close)
+		token := sign(jose.NewHeader(jwa.ES256, jose.WithX509URL(s.URL)))
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx), jws.WithX509URL(s.Client())), token); !errors.Is(err, jws.ErrX509URLResponseIsNotOK) {
+			t.Errorf("❌: jws.Verify: err != jws.ErrX509URLResponseIsNotOK: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrX509URLIsNotHTTPS)", func(t *testing.T) {
+		t.Parallel()
+		s := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(s.Close)
+		token := sign(jose.NewHeader(jwa.ES256, jose.WithX509URL(s.URL)))
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx), jws.WithX509URL(s.Client())), token); !errors.Is(err, jws.ErrX509URLIsNotHTTPS) {
+			t.Errorf("❌: jws.Verify: err != jws.ErrX509URLIsNotHTTPS: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrX509URLResponseIsTooLarge)", func(t *testing.T) {
+		t.Parallel()
+		s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(make([]byte, 1024*1024+1))
+		}))
+		t.Cleanup(s.Close)
+		token := sign(jose.NewHeader(jwa.ES256, jose.WithX509URL(s.URL)))
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx), jws.WithX509URL(s.Client())), token); !errors.Is(err, jws.ErrX509URLResponseIsTooLarge) {
+			t.Errorf("❌: jws.Verify: err != jws.ErrX509URLResponseIsTooLarge: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrX509CertificateThumbprintIsNotMatch)", func(t *testing.T) {
+		t.Parallel()
+		for _, header := range []*jose.Header{
+			jose.NewHeader(jwa.ES256, jose.WithX509CertificateChain(x5c), jose.WithX509CertificateSHA1Thumbprint("invalid")),
+			jose.NewHeader(jwa.ES256, jose.WithX509CertificateChain(x5c), jose.WithX509CertificateSHA256Thumbprint("invalid")),
+		} {
+			if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx)), sign(header)); !errors.Is(err, jws.ErrX509CertificateThumbprintIsNotMatch) {
+				t.Errorf("❌: jws.Verify: err != jws.ErrX509CertificateThumbprintIsNotMatch: %v", err)
+			}
+		}
+	})
+
+	t.Run("failure(jws.ErrX509RootsIsNil)", func(t *testing.T) {
+		t.Parallel()
+		token := sign(jose.NewHeader(jwa.ES256, jose.WithX509CertificateChain(x5c)))
+		if _, err := jws.Verify(jws.UseX509CertificateChain(nil, jws.WithX509Context(ctx)), token); !errors.Is(err, jws.ErrX509RootsIsNil) {
+			t.Errorf("❌: jws.Verify: err != jws.ErrX509RootsIsNil: %v", err)
+		}
+	})
+
+	t.Run("failure(x509.UnknownAuthorityError)", func(t *testing.T) {
+		t.Parallel()
+		token := sign(jose.NewHeader(jwa.ES256, jose.WithX509CertificateChain(x5c[:1])))
+		var e x509.UnknownAuthorityError
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx)), token); !errors.As(err, &e) {
+			t.Errorf("❌: jws.Verify: err != x509.UnknownAuthorityError: %v", err)
+		}
+	})
+
+	t.Run("failure(x509.CertificateInvalidError)", func(t *testing.T) {
+		t.Parallel()
+		token := sign(jose.NewHeader(jwa.ES256, jose.WithX509CertificateChain(x5c)))
+		var e x509.CertificateInvalidError
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(timez.WithContext(context.Background(), notAfter.AddDate(0, 0, 1)))), token); !errors.As(err, &e) {
+			t.Errorf("❌: jws.Verify: err != x509.CertificateInvalidError: %v", err)
+		}
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx), jws.WithX509KeyUsages(x509.ExtKeyUsageServerAuth)), token); !errors.As(err, &e) {
+			t.Errorf("❌: jws.Verify: err != x509.CertificateInvalidError: %v", err)
+		}
+	})
+
+	t.Run("failure(jwa.ErrFailedToVerifySignature)", func(t *testing.T) {
+		t.Parallel()
+		headerEncoded := must.One(jose.NewHeader(jwa.ES256, jose.WithX509CertificateChain(x5c)).Encode())
+		signingInput := headerEncoded + ".cGF5bG9hZA"
+		token := signingInput + "." + must.One(jws.Sign(jwa.ES256, jws.WithECDSAKey(intermediateKey), signingInput))
+		if _, err := jws.Verify(jws.UseX509CertificateChain(roots, jws.WithX509Context(ctx)), token); !errors.Is(err, jwa.ErrFailedToVerifySignature) {
+			t.Errorf("❌: jws.Verify: err != jwa.ErrFailedToVerifySignature: %v", err)
+		}
+	})
+
+	t.Run("failure(x5c)", func(t *testing.T) {
+		t.Parallel()
+		for _, x5c := range [][]string{{"!"}, {"aW52YWxpZA=="}} {
+			if _, err := jws.Verify(jws.UseX509CertificateChain(roots), sign(jose.NewHeader(jwa.ES256, jose.WithX509CertificateChain(x5c)))); err == nil {
+				t.Errorf("❌: jws.Verify: err == nil")
+			}
+		}
+	})
+}
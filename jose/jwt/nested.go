@@ -0,0 +1,111 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwe"
+	"github.com/kunitsucom/util.go/jose/jws"
+)
+
+// - ref. Nested JWT https://www.rfc-editor.org/rfc/rfc7519#section-5.2
+// - ref. Creating a JWT https://www.rfc-editor.org/rfc/rfc7519#section-7.1
+// - ref. Validating a JWT https://www.rfc-editor.org/rfc/rfc7519#section-7.2
+
+var ErrContentTypeIsNotJWT = errors.New(`jwt: content type is not "JWT"`)
+
+const contentTypeJWT = "JWT"
+
+// NewNested signs claimsSet with signingKeyOpt, and encrypts the signed JWT with encryptionKeyOpt.
+// The "cty" of encryptionHeader is set to "JWT" if it is empty.
+//
+// Example:
+//
+//	token, err := jwt.NewNested(
+//		jws.WithECDSAKey(signingPrivateKey),
+//		jose.NewHeader(jwa.ES256, jose.WithType("JWT")),
+//		jwt.NewClaimsSet(jwt.WithSubject("userID"), jwt.WithExpirationTime(time.Now().Add(1*time.Hour))),
+//		jwe.WithRSAKey(recipientPublicKey),
+//		jose.NewHeader(jwa.RSAOAEP256, jose.WithEncryptionAlgorithm(jwa.A256GCM)),
+//	)
+func NewNested(signingKeyOpt jws.SigningKeyOption, header *jose.Header, claimsSet *ClaimsSet, encryptionKeyOpt jwe.EncryptionKeyOption, encryptionHeader *jose.Header) (token string, err error) {
+	signed, err := New(signingKeyOpt, header, claimsSet)
+	if err != nil {
+		return "", fmt.Errorf("jwt.New: %w", err)
+	}
+
+	return encryptNested(encryptionKeyOpt, encryptionHeader, signed)
+}
+
+func encryptNested(encryptionKeyOpt jwe.EncryptionKeyOption, encryptionHeader *jose.Header, signed string) (token string, err error) {
+	// copy not to modify the caller's header
+	h := *encryptionHeader
+	if h.ContentType == "" {
+		h.ContentType = contentTypeJWT
+	}
+
+	token, err = jwe.Encrypt(encryptionKeyOpt, &h, []byte(signed))
+	if err != nil {
+		return "", fmt.Errorf("jwe.Encrypt: %w", err)
+	}
+
+	return token, nil
+}
+
+// VerifyNested decrypts token with decryptionKeyOpt, and verifies the nested JWT in the same way as Verify.
+// The returned header is the JOSE Header of the nested JWS.
+//
+// Example:
+//
+//	header, claimsSet, err := jwt.VerifyNested(
+//		jwe.UseRSAKey(recipientPrivateKey),
+//		jws.UseECDSAKey(signingPublicKey),
+//		token,
+//		jwt.VerifyAlgorithm(jwa.ES256),
+//	)
+func VerifyNested(decryptionKeyOpt jwe.DecryptionKeyOption, keyOption jws.VerificationKeyOption, token string, opts ...VerifyOption) (header *jose.Header, claimsSet *ClaimsSet, err error) {
+	signed, err := decryptNested(decryptionKeyOpt, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return Verify(keyOption, signed, opts...)
+}
+
+func decryptNested(decryptionKeyOpt jwe.DecryptionKeyOption, token string) (signed string, err error) {
+	encryptionHeader, plaintext, err := jwe.Decrypt(decryptionKeyOpt, token)
+	if err != nil {
+		return "", fmt.Errorf("jwe.Decrypt: %w", err)
+	}
+
+	// The "cty" value MUST be "JWT" for Nested JWTs, and it is recommended to be compared case-insensitively.
+	//
+	//   - ref. https://www.rfc-editor.org/rfc/rfc7519#section-5.2
+	if !strings.EqualFold(encryptionHeader.ContentType, contentTypeJWT) {
+		return "", fmt.Errorf("cty=%s: %w", encryptionHeader.ContentType, ErrContentTypeIsNotJWT)
+	}
+
+	return string(plaintext), nil
+}
+
+// NewNestedWithClaims is the same as NewNested, but the payload is the user-defined claims as with NewWithClaims.
+func NewNestedWithClaims[T Claims](signingKeyOpt jws.SigningKeyOption, header *jose.Header, claims T, encryptionKeyOpt jwe.EncryptionKeyOption, encryptionHeader *jose.Header) (token string, err error) {
+	signed, err := NewWithClaims(signingKeyOpt, header, claims)
+	if err != nil {
+		return "", fmt.Errorf("jwt.NewWithClaims: %w", err)
+	}
+
+	return encryptNested(encryptionKeyOpt, encryptionHeader, signed)
+}
+
+// VerifyNestedInto is the same as VerifyNested, but decodes the payload into claims as with VerifyInto.
+func VerifyNestedInto[T Claims](decryptionKeyOpt jwe.DecryptionKeyOption, keyOption jws.VerificationKeyOption, token string, claims T, opts ...VerifyOption) (header *jose.Header, err error) {
+	signed, err := decryptNested(decryptionKeyOpt, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return VerifyInto(keyOption, signed, claims, opts...)
+}
@@ -0,0 +1,109 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwe"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	testingz "github.com/kunitsucom/util.go/testing"
+)
+
+func TestVerifyNested(t *testing.T) {
+	t.Parallel()
+
+	signingPrivateKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	signingPublicKey := must.One(x509z.ParseECDSAPublicKeyPEM([]byte(testingz.TestECDSAPublicKey256BitPEM)))
+	recipientPrivateKey := must.One(x509z.ParseRSAPrivateKeyPEM([]byte(testingz.TestRSAPrivateKey2048BitPEM)))
+	encryptionHeader := jose.NewHeader(jwa.RSAOAEP256, jose.WithEncryptionAlgorithm(jwa.A256GCM))
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		token, err := jwt.NewNested(
+			jws.WithECDSAKey(signingPrivateKey),
+			jose.NewHeader(jwa.ES256, jose.WithType("JWT")),
+			jwt.NewClaimsSet(jwt.WithSubject("userID"), jwt.WithExpirationTime(time.Now().Add(time.Hour))),
+			jwe.WithRSAKey(&recipientPrivateKey.PublicKey),
+			encryptionHeader,
+		)
+		if err != nil {
+			t.Fatalf("❌: jwt.NewNested: err != nil: %v", err)
+		}
+		if encryptionHeader.ContentType != "" {
+			t.Errorf("❌: jwt.NewNested: encryptionHeader is modified: cty=%s", encryptionHeader.ContentType)
+		}
+		header, claimsSet, err := jwt.VerifyNested(jwe.UseRSAKey(recipientPrivateKey), jws.UseECDSAKey(signingPublicKey), token, jwt.VerifyAlgorithm(jwa.ES256), jwt.VerifyRequiredClaims("sub"))
+		if err != nil {
+			t.Fatalf("❌: jwt.VerifyNested: err != nil: %v", err)
+		}
+		if header.Algorithm != jwa.ES256 || claimsSet.Subject != "userID" {
+			t.Errorf("❌: jwt.VerifyNested: alg=%s sub=%s", header.Algorithm, claimsSet.Subject)
+		}
+	})
+
+	t.Run("success(typed)", func(t *testing.T) {
+		t.Parallel()
+		token, err := jwt.NewNestedWithClaims(
+			jws.WithECDSAKey(signingPrivateKey),
+			jose.NewHeader(jwa.ES256),
+			&testClaims{ClaimsSet: *jwt.NewClaimsSet(jwt.WithSubject("userID")), Email: "user@example.com"},
+			jwe.WithRSAKey(&recipientPrivateKey.PublicKey),
+			encryptionHeader,
+		)
+		if err != nil {
+			t.Fatalf("❌: jwt.NewNestedWithClaims: err != nil: %v", err)
+		}
+		var claims testClaims
+		if _, err := jwt.VerifyNestedInto(jwe.UseRSAKey(recipientPrivateKey), jws.UseECDSAKey(signingPublicKey), token, &claims); err != nil {
+			t.Fatalf("❌: jwt.VerifyNestedInto: err != nil: %v", err)
+		}
+		if claims.Subject != "userID" || claims.Email != "user@example.com" {
+			t.Errorf("❌: jwt.VerifyNestedInto: %+v", claims)
+		}
+	})
+
+	t.Run("failure(jwt.ErrTokenIsExpired)", func(t *testing.T) {
+		t.Parallel()
+		token := must.One(jwt.NewNested(
+			jws.WithECDSAKey(signingPrivateKey),
+			jose.NewHeader(jwa.ES256),
+			jwt.NewClaimsSet(jwt.WithExpirationTime(time.Now().Add(-time.Hour))),
+			jwe.WithRSAKey(&recipientPrivateKey.PublicKey),
+			encryptionHeader,
+		))
+		if _, _, err := jwt.VerifyNested(jwe.UseRSAKey(recipientPrivateKey), jws.UseECDSAKey(signingPublicKey), token); !errors.Is(err, jwt.ErrTokenIsExpired) {
+			t.Errorf("❌: jwt.VerifyNested: err != jwt.ErrTokenIsExpired: %v", err)
+		}
+	})
+
+	t.Run("failure(jwt.ErrContentTypeIsNotJWT)", func(t *testing.T) {
+		t.Parallel()
+		token := must.One(jwe.Encrypt(jwe.WithRSAKey(&recipientPrivateKey.PublicKey), encryptionHeader, []byte("plaintext")))
+		if _, _, err := jwt.VerifyNested(jwe.UseRSAKey(recipientPrivateKey), jws.UseECDSAKey(signingPublicKey), token); !errors.Is(err, jwt.ErrContentTypeIsNotJWT) {
+			t.Errorf("❌: jwt.VerifyNested: err != jwt.ErrContentTypeIsNotJWT: %v", err)
+		}
+		if _, err := jwt.VerifyNestedInto(jwe.UseRSAKey(recipientPrivateKey), jws.UseECDSAKey(signingPublicKey), token, new(testClaims)); !errors.Is(err, jwt.ErrContentTypeIsNotJWT) {
+			t.Errorf("❌: jwt.VerifyNestedInto: err != jwt.ErrContentTypeIsNotJWT: %v", err)
+		}
+	})
+
+	t.Run("failure(jwe.Decrypt)", func(t *testing.T) {
+		t.Parallel()
+		if _, _, err := jwt.VerifyNested(jwe.UseRSAKey(recipientPrivateKey), jws.UseECDSAKey(signingPublicKey), "header.payload.signature"); !errors.Is(err, jwe.ErrInvalidTokenReceived) {
+			t.Errorf("❌: jwt.VerifyNested: err != jwe.ErrInvalidTokenReceived: %v", err)
+		}
+	})
+
+	t.Run("failure(jwt.New)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jwt.NewNested(jws.WithKey(nil), jose.NewHeader(jwa.ES256), jwt.NewClaimsSet(), jwe.WithRSAKey(&recipientPrivateKey.PublicKey), encryptionHeader); err == nil {
+			t.Errorf("❌: jwt.NewNested: err == nil")
+		}
+	})
+}
@@ -17,6 +17,11 @@ import (
 //		jwt.NewClaimsSet(jwt.WithSubject("userID"), jwt.WithExpirationTime(time.Now().Add(1*time.Hour))),
 //	)
 func Sign(keyOpt jws.SigningKeyOption, header *jose.Header, claimsSet *ClaimsSet) (signingInput, signatureEncoded string, err error) {
+	keyOpt, header, err = jws.ResolveSigningKey(keyOpt, header)
+	if err != nil {
+		return "", "", fmt.Errorf("jws.ResolveSigningKey: %w", err)
+	}
+
 	headerEncoded, err := header.Encode()
 	if err != nil {
 		return "", "", fmt.Errorf("(*jose.Header).Encode: %w", err)
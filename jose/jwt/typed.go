@@ -0,0 +1,202 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jws"
+)
+
+// Claims is the interface implemented by a pointer to a user-defined struct which embeds ClaimsSet.
+//
+// Example:
+//
+//	type MyClaims struct {
+//		jwt.ClaimsSet
+//		Email string   `json:"email"`
+//		Roles []string `json:"roles,omitempty"`
+//	}
+//
+//	var _ jwt.Claims = (*MyClaims)(nil)
+type Claims interface {
+	claimsSet() *ClaimsSet
+}
+
+func (c *ClaimsSet) claimsSet() *ClaimsSet { return c }
+
+var _ Claims = (*ClaimsSet)(nil)
+
+// userFields is the struct type which has the fields of a user-defined claims struct except the embedded ClaimsSet.
+type userFields struct {
+	typ     reflect.Type
+	indexes []int
+}
+
+//nolint:gochecknoglobals
+var userFieldsCache sync.Map // map[reflect.Type]*userFields
+
+func userFieldsOf(typ reflect.Type) *userFields {
+	if v, ok := userFieldsCache.Load(typ); ok {
+		return v.(*userFields) //nolint:forcetypeassert
+	}
+
+	claimsSetType := reflect.TypeOf(ClaimsSet{})
+	fields := make([]reflect.StructField, 0, typ.NumField())
+	uf := &userFields{}
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if f.Anonymous && (f.Type == claimsSetType || f.Type == reflect.PointerTo(claimsSetType)) {
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if strings.Split(f.Tag.Get("json"), ",")[0] == "-" {
+			continue
+		}
+		fields = append(fields, reflect.StructField{Name: f.Name, Type: f.Type, Tag: f.Tag, Anonymous: f.Anonymous})
+		uf.indexes = append(uf.indexes, i)
+	}
+	uf.typ = reflect.StructOf(fields)
+
+	v, _ := userFieldsCache.LoadOrStore(typ, uf)
+	return v.(*userFields) //nolint:forcetypeassert
+}
+
+func structValueOf(claims Claims) (reflect.Value, error) {
+	v := reflect.ValueOf(claims)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("claims.(type)==%T: %w", claims, ErrVIsNotPointerOrInterface)
+	}
+	return v.Elem(), nil
+}
+
+// MarshalClaims returns the JSON encoding of claims, which is the union of the embedded ClaimsSet and the other fields.
+// If a claim is in both PrivateClaims and the other fields, the field takes precedence.
+func MarshalClaims(claims Claims) ([]byte, error) {
+	v, err := structValueOf(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	claimsSetJSON, err := json.Marshal(claims.claimsSet())
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	uf := userFieldsOf(v.Type())
+	if len(uf.indexes) == 0 {
+		return claimsSetJSON, nil
+	}
+
+	shadow := reflect.New(uf.typ).Elem()
+	for i, index := range uf.indexes {
+		shadow.Field(i).Set(v.Field(index))
+	}
+	userFieldsJSON, err := json.Marshal(shadow.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	merged := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(claimsSetJSON, &merged); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if err := json.Unmarshal(userFieldsJSON, &merged); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return json.Marshal(merged) //nolint:wrapcheck
+}
+
+// UnmarshalClaims parses the JSON-encoded data into claims.
+// The claims are stored in the embedded ClaimsSet as with (*ClaimsSet).UnmarshalJSON,
+// and the claims corresponding to the other fields are also stored in the fields.
+func UnmarshalClaims(data []byte, claims Claims) error {
+	v, err := structValueOf(claims)
+	if err != nil {
+		return err
+	}
+
+	cs := claims.claimsSet()
+	if err := json.Unmarshal(data, cs); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	uf := userFieldsOf(v.Type())
+	if len(uf.indexes) == 0 {
+		return nil
+	}
+
+	shadow := reflect.New(uf.typ)
+	if err := json.Unmarshal(data, shadow.Interface()); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	for i, index := range uf.indexes {
+		v.Field(index).Set(shadow.Elem().Field(i))
+	}
+	return nil
+}
+
+// NewWithClaims returns a new JWT signed with keyOpt, whose payload is claims.
+//
+// Example:
+//
+//	token, err := jwt.NewWithClaims(
+//		jws.WithHMACKey([]byte("YOUR_HMAC_KEY")),
+//		jose.NewHeader(jwa.HS256, jose.WithType("JWT")),
+//		&MyClaims{ClaimsSet: *jwt.NewClaimsSet(jwt.WithSubject("userID")), Email: "user@example.com"},
+//	)
+func NewWithClaims[T Claims](keyOpt jws.SigningKeyOption, header *jose.Header, claims T) (token string, err error) {
+	keyOpt, header, err = jws.ResolveSigningKey(keyOpt, header)
+	if err != nil {
+		return "", fmt.Errorf("jws.ResolveSigningKey: %w", err)
+	}
+
+	headerEncoded, err := header.Encode()
+	if err != nil {
+		return "", fmt.Errorf("(*jose.Header).Encode: %w", err)
+	}
+
+	b, err := MarshalClaims(claims)
+	if err != nil {
+		return "", fmt.Errorf("jwt.MarshalClaims: %w", err)
+	}
+
+	signingInput := headerEncoded + "." + base64.RawURLEncoding.EncodeToString(b)
+	signatureEncoded, err := jws.Sign(header.Algorithm, keyOpt, signingInput)
+	if err != nil {
+		return "", fmt.Errorf("jws.Sign: %w", err)
+	}
+
+	return signingInput + "." + signatureEncoded, nil
+}
+
+// VerifyInto verifies jwt in the same way as Verify, and decodes the payload into claims.
+//
+// Example:
+//
+//	var claims MyClaims
+//	header, err := jwt.VerifyInto(
+//		jws.UseHMACKey([]byte("YOUR_HMAC_KEY")),
+//		token,
+//		&claims,
+//		jwt.VerifyAlgorithm(jwa.HS256),
+//	)
+func VerifyInto[T Claims](keyOption jws.VerificationKeyOption, jwt string, claims T, opts ...VerifyOption) (header *jose.Header, err error) {
+	return verify(keyOption, jwt, func(payloadEncoded string) (*ClaimsSet, error) {
+		decoded, err := base64.RawURLEncoding.DecodeString(payloadEncoded)
+		if err != nil {
+			return nil, fmt.Errorf("base64.RawURLEncoding.DecodeString: %w", err)
+		}
+		if err := UnmarshalClaims(decoded, claims); err != nil {
+			return nil, fmt.Errorf("jwt.UnmarshalClaims: %w", err)
+		}
+		return claims.claimsSet(), nil
+	}, opts...)
+}
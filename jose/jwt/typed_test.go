@@ -0,0 +1,123 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+)
+
+type testClaims struct {
+	jwt.ClaimsSet
+	Email    string   `json:"email"`
+	Roles    []string `json:"roles,omitempty"`
+	Ignored  string   `json:"-"`
+	internal string
+}
+
+func TestNewWithClaims(t *testing.T) {
+	t.Parallel()
+
+	key := []byte(`your-256-bit-secret`)
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		claims := &testClaims{
+			ClaimsSet: *jwt.NewClaimsSet(jwt.WithSubject("userID"), jwt.WithExpirationTime(time.Now().Add(time.Hour)), jwt.WithPrivateClaim("extra", "value")),
+			Email:     "user@example.com",
+			Roles:     []string{"admin"},
+			Ignored:   "ignored",
+			internal:  "internal",
+		}
+		token, err := jwt.NewWithClaims(jws.WithHMACKey(key), jose.NewHeader(jwa.HS256), claims)
+		if err != nil {
+			t.Fatalf("❌: jwt.NewWithClaims: err != nil: %v", err)
+		}
+
+		var actual testClaims
+		header, err := jwt.VerifyInto(jws.UseHMACKey(key), token, &actual, jwt.VerifyAlgorithm(jwa.HS256), jwt.VerifyRequiredClaims("sub", "email"))
+		if err != nil {
+			t.Fatalf("❌: jwt.VerifyInto: err != nil: %v", err)
+		}
+		if header.Algorithm != jwa.HS256 {
+			t.Errorf("❌: jwt.VerifyInto: alg: %s", header.Algorithm)
+		}
+		if actual.Subject != "userID" || actual.Email != "user@example.com" || len(actual.Roles) != 1 || actual.Roles[0] != "admin" || actual.Ignored != "" || actual.internal != "" {
+			t.Errorf("❌: jwt.VerifyInto: %+v", actual)
+		}
+		if actual.PrivateClaims["email"] != "user@example.com" || actual.PrivateClaims["extra"] != "value" {
+			t.Errorf("❌: jwt.VerifyInto: PrivateClaims: %v", actual.PrivateClaims)
+		}
+
+		_, cs, err := jwt.Verify(jws.UseHMACKey(key), token)
+		if err != nil {
+			t.Fatalf("❌: jwt.Verify: err != nil: %v", err)
+		}
+		if cs.PrivateClaims["email"] != "user@example.com" {
+			t.Errorf("❌: jwt.Verify: PrivateClaims: %v", cs.PrivateClaims)
+		}
+	})
+
+	t.Run("success(*jwt.ClaimsSet)", func(t *testing.T) {
+		t.Parallel()
+		token := must.One(jwt.NewWithClaims(jws.WithHMACKey(key), jose.NewHeader(jwa.HS256), jwt.NewClaimsSet(jwt.WithSubject("userID"))))
+		actual := new(jwt.ClaimsSet)
+		if _, err := jwt.VerifyInto(jws.UseHMACKey(key), token, actual); err != nil {
+			t.Fatalf("❌: jwt.VerifyInto: err != nil: %v", err)
+		}
+		if actual.Subject != "userID" {
+			t.Errorf("❌: jwt.VerifyInto: %+v", actual)
+		}
+	})
+
+	t.Run("failure(jwt.ErrTokenIsExpired)", func(t *testing.T) {
+		t.Parallel()
+		claims := &testClaims{ClaimsSet: *jwt.NewClaimsSet(jwt.WithExpirationTime(time.Now().Add(-time.Hour))), Email: "user@example.com"}
+		token := must.One(jwt.NewWithClaims(jws.WithHMACKey(key), jose.NewHeader(jwa.HS256), claims))
+		if _, err := jwt.VerifyInto(jws.UseHMACKey(key), token, new(testClaims)); !errors.Is(err, jwt.ErrTokenIsExpired) {
+			t.Errorf("❌: jwt.VerifyInto: err != jwt.ErrTokenIsExpired: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrFailedToVerifySignature)", func(t *testing.T) {
+		t.Parallel()
+		token := must.One(jwt.NewWithClaims(jws.WithHMACKey([]byte("attacker-key")), jose.NewHeader(jwa.HS256), &testClaims{ClaimsSet: *jwt.NewClaimsSet(jwt.WithSubject("admin")), Email: "attacker@example.com"}))
+		var actual testClaims
+		if _, err := jwt.VerifyInto(jws.UseHMACKey(key), token, &actual); !errors.Is(err, jwa.ErrFailedToVerifySignature) {
+			t.Errorf("❌: jwt.VerifyInto: err != jwa.ErrFailedToVerifySignature: %v", err)
+		}
+		if actual.Subject != "" || actual.Email != "" {
+			t.Errorf("❌: jwt.VerifyInto: unverified claims are decoded: %+v", actual)
+		}
+	})
+
+	t.Run("failure(jwt.ErrRequiredClaimIsMissing)", func(t *testing.T) {
+		t.Parallel()
+		token := must.One(jwt.NewWithClaims(jws.WithHMACKey(key), jose.NewHeader(jwa.HS256), &testClaims{ClaimsSet: *jwt.NewClaimsSet()}))
+		if _, err := jwt.VerifyInto(jws.UseHMACKey(key), token, new(testClaims), jwt.VerifyRequiredClaims("roles")); !errors.Is(err, jwt.ErrRequiredClaimIsMissing) {
+			t.Errorf("❌: jwt.VerifyInto: err != jwt.ErrRequiredClaimIsMissing: %v", err)
+		}
+	})
+
+	t.Run("failure(json.Unmarshal)", func(t *testing.T) {
+		t.Parallel()
+		token := must.One(jwt.New(jws.WithHMACKey(key), jose.NewHeader(jwa.HS256), jwt.NewClaimsSet(jwt.WithPrivateClaim("email", 1))))
+		var e *json.UnmarshalTypeError
+		if _, err := jwt.VerifyInto(jws.UseHMACKey(key), token, new(testClaims)); !errors.As(err, &e) {
+			t.Errorf("❌: jwt.VerifyInto: err != *json.UnmarshalTypeError: %v", err)
+		}
+	})
+
+	t.Run("failure(jwt.ErrVIsNotPointerOrInterface)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := jwt.MarshalClaims((*testClaims)(nil)); !errors.Is(err, jwt.ErrVIsNotPointerOrInterface) {
+			t.Errorf("❌: jwt.MarshalClaims: err != jwt.ErrVIsNotPointerOrInterface: %v", err)
+		}
+	})
+}
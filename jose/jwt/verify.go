@@ -1,25 +1,73 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/kunitsucom/util.go/jose"
 	"github.com/kunitsucom/util.go/jose/jws"
+	slicez "github.com/kunitsucom/util.go/slices"
+	timez "github.com/kunitsucom/util.go/time"
 )
 
 var (
-	ErrTokenIsExpired     = errors.New("jwt: token is expired")
-	ErrTokenIsNotBefore   = errors.New("jwt: token is not before")
-	ErrAudienceIsNotMatch = errors.New("jwt: audience is not match")
-	ErrIssuerIsNotMatch   = errors.New("jwt: issuer is not match")
+	ErrTokenIsExpired           = errors.New("jwt: token is expired")
+	ErrTokenIsNotBefore         = errors.New("jwt: token is not before")
+	ErrTokenIsIssuedInTheFuture = errors.New("jwt: token is issued in the future")
+	ErrTokenIsTooOld            = errors.New("jwt: token is too old")
+	ErrAudienceIsNotMatch       = errors.New("jwt: audience is not match")
+	ErrIssuerIsNotMatch         = errors.New("jwt: issuer is not match")
+	ErrRequiredClaimIsMissing   = errors.New("jwt: required claim is missing")
+	ErrAlgorithmIsNotAllowed    = errors.New("jwt: algorithm is not allowed")
+	ErrTypeIsNotMatch           = errors.New("jwt: type is not match")
 )
 
+// ValidationError is the error returned when the token does not satisfy a validation rule.
+// Rule is the name of the claim or the header parameter which failed, e.g. "exp", "aud", "alg".
+//
+// Example:
+//
+//	var e *jwt.ValidationError
+//	if errors.As(err, &e) && e.Rule == "exp" {
+//		// token is expired
+//	}
+type ValidationError struct {
+	Rule   string
+	Reason string
+	Err    error
+}
+
+var (
+	_ interface{ Error() string } = (*ValidationError)(nil)
+	_ interface{ Unwrap() error } = (*ValidationError)(nil)
+)
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Rule, e.Reason, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func newValidationError(rule string, err error, format string, a ...any) error {
+	return &ValidationError{Rule: rule, Reason: fmt.Sprintf(format, a...), Err: err}
+}
+
 type verifyOption struct {
+	ctx                     context.Context //nolint:containedctx
+	leeway                  time.Duration
+	maxAge                  time.Duration
+	requiredClaims          []string
+	alg                     []string
+	typ                     []string
 	aud                     []string
 	iss                     string
 	verifyPrivateClaimsFunc func(privateClaims PrivateClaims) error
+	jwsVerifyOptions        []jws.VerifyOption
 }
 
 type VerifyOption func(*verifyOption)
@@ -42,6 +90,62 @@ func VerifyPrivateClaims(verifyPrivateClaimsFunc func(privateClaims PrivateClaim
 	}
 }
 
+// VerifyLeeway sets the leeway for clock skew in verifying "exp", "nbf" and "iat".
+func VerifyLeeway(leeway time.Duration) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.leeway = leeway
+	}
+}
+
+// VerifyRequiredClaims sets the names of the claims which must be present, e.g. "sub", "jti".
+func VerifyRequiredClaims(claimNames ...string) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.requiredClaims = append(vo.requiredClaims, claimNames...)
+	}
+}
+
+// VerifyMaxAge sets the maximum age of the token since "iat". If set, "iat" is required.
+func VerifyMaxAge(maxAge time.Duration) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.maxAge = maxAge
+	}
+}
+
+// VerifyAlgorithm sets the allow-list of "alg".
+// It is strongly recommended to pin the algorithm to prevent algorithm confusion attacks.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc8725#section-3.1
+func VerifyAlgorithm(alg ...string) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.alg = append(vo.alg, alg...)
+	}
+}
+
+// VerifyType sets the allow-list of "typ", e.g. "JWT", "at+jwt".
+// "typ" is compared case-insensitively, and "application/" prefix is omitted.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7515#section-4.1.9
+//   - ref. https://www.rfc-editor.org/rfc/rfc9068#section-4
+func VerifyType(typ ...string) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.typ = append(vo.typ, typ...)
+	}
+}
+
+// VerifyContext sets the context to get the current time by timez.Now.
+func VerifyContext(ctx context.Context) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.ctx = ctx
+	}
+}
+
+// VerifyJWSOptions sets jws.VerifyOption passed to jws.Verify.
+func VerifyJWSOptions(opts ...jws.VerifyOption) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.jwsVerifyOptions = append(vo.jwsVerifyOptions, opts...)
+	}
+}
+
 // Verify
 //
 // Example:
@@ -49,43 +153,120 @@ func VerifyPrivateClaims(verifyPrivateClaimsFunc func(privateClaims PrivateClaim
 //	header, claimsSet, err := jwt.Verify(
 //		jws.UseHMACKey([]byte("YOUR_HMAC_KEY"),
 //		token,
+//		jwt.VerifyAlgorithm(jwa.HS256),
+//		jwt.VerifyLeeway(30*time.Second),
 //	)
 func Verify(keyOption jws.VerificationKeyOption, jwt string, opts ...VerifyOption) (header *jose.Header, claimsSet *ClaimsSet, err error) {
-	vo := new(verifyOption)
+	cs := new(ClaimsSet)
+	h, err := verify(keyOption, jwt, func(payloadEncoded string) (*ClaimsSet, error) {
+		if err := cs.Decode(payloadEncoded); err != nil {
+			return nil, fmt.Errorf("(*jwt.ClaimsSet).Decode: %w", err)
+		}
+		return cs, nil
+	}, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return h, cs, nil
+}
+
+func verify(keyOption jws.VerificationKeyOption, jwt string, decode func(payloadEncoded string) (*ClaimsSet, error), opts ...VerifyOption) (header *jose.Header, err error) {
+	vo := &verifyOption{
+		ctx: context.Background(),
+	}
 	for _, opt := range opts {
 		opt(vo)
 	}
 
-	_, payloadEncoded, _, err := jws.Parse(jwt)
+	headerEncoded, payloadEncoded, _, err := jws.Parse(jwt)
 	if err != nil {
-		return nil, nil, fmt.Errorf("jws.Parse: %w", err)
+		return nil, fmt.Errorf("jws.Parse: %w", err)
 	}
 
-	cs := new(ClaimsSet)
-	if err := cs.Decode(payloadEncoded); err != nil {
-		return nil, nil, fmt.Errorf("(*jwt.ClaimsSet).Decode: %w", err)
+	h := new(jose.Header)
+	if err := h.Decode(headerEncoded); err != nil {
+		return nil, fmt.Errorf("(*jose.Header).Decode: %w", err)
 	}
 
-	if err := verifyClaimsSet(cs, vo, time.Now().UTC()); err != nil {
-		return nil, nil, err
+	if err := verifyHeader(h, vo); err != nil {
+		return nil, err
 	}
 
-	h, err := jws.Verify(keyOption, jwt)
+	// NOTE: decode the payload only after the signature is verified, so that the unverified claims are not exposed to the caller.
+	h, err = jws.Verify(keyOption, jwt, vo.jwsVerifyOptions...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("jws.Verify: %w", err)
+		return nil, fmt.Errorf("jws.Verify: %w", err)
 	}
 
-	return h, cs, nil
+	cs, err := decode(payloadEncoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyClaimsSet(cs, vo, timez.Now(vo.ctx)); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func verifyHeader(h *jose.Header, vo *verifyOption) error {
+	if len(vo.alg) > 0 && !slicez.Contains(vo.alg, h.Algorithm) {
+		return newValidationError("alg", ErrAlgorithmIsNotAllowed, "want=%v got=%s", vo.alg, h.Algorithm)
+	}
+
+	if len(vo.typ) > 0 {
+		if err := verifyType(h.Type, vo.typ); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func verifyType(typ string, allowed []string) error {
+	normalize := func(typ string) string {
+		typ = strings.ToLower(typ)
+		return strings.TrimPrefix(typ, "application/")
+	}
+
+	for _, want := range allowed {
+		if normalize(want) == normalize(typ) {
+			return nil
+		}
+	}
+
+	return newValidationError("typ", ErrTypeIsNotMatch, "want=%v got=%s", allowed, typ)
 }
 
 //nolint:cyclop
 func verifyClaimsSet(cs *ClaimsSet, vo *verifyOption, now time.Time) error {
-	if cs.ExpirationTime != 0 && cs.ExpirationTime <= now.Unix() {
-		return fmt.Errorf("exp=%d <= now=%d: %w", cs.ExpirationTime, now.Unix(), ErrTokenIsExpired)
+	for _, claimName := range vo.requiredClaims {
+		if !hasClaim(cs, claimName) {
+			return newValidationError(claimName, ErrRequiredClaimIsMissing, "claim is missing")
+		}
 	}
 
-	if cs.NotBefore != 0 && cs.NotBefore >= now.Unix() {
-		return fmt.Errorf("nbf=%d >= now=%d: %w", cs.NotBefore, now.Unix(), ErrTokenIsExpired)
+	if cs.ExpirationTime != 0 && !now.Before(time.Unix(cs.ExpirationTime, 0).Add(vo.leeway)) {
+		return newValidationError("exp", ErrTokenIsExpired, "exp=%d <= now=%d leeway=%s", cs.ExpirationTime, now.Unix(), vo.leeway)
+	}
+
+	if cs.NotBefore != 0 && now.Before(time.Unix(cs.NotBefore, 0).Add(-vo.leeway)) {
+		return newValidationError("nbf", ErrTokenIsNotBefore, "nbf=%d > now=%d leeway=%s", cs.NotBefore, now.Unix(), vo.leeway)
+	}
+
+	if vo.maxAge > 0 {
+		if cs.IssuedAt == 0 {
+			return newValidationError("iat", ErrRequiredClaimIsMissing, "claim is missing")
+		}
+		iat := time.Unix(cs.IssuedAt, 0)
+		if now.Before(iat.Add(-vo.leeway)) {
+			return newValidationError("iat", ErrTokenIsIssuedInTheFuture, "iat=%d > now=%d leeway=%s", cs.IssuedAt, now.Unix(), vo.leeway)
+		}
+		if !now.Before(iat.Add(vo.maxAge + vo.leeway)) {
+			return newValidationError("iat", ErrTokenIsTooOld, "iat=%d maxAge=%s now=%d leeway=%s", cs.IssuedAt, vo.maxAge, now.Unix(), vo.leeway)
+		}
 	}
 
 	if len(vo.aud) > 0 {
@@ -109,6 +290,28 @@ func verifyClaimsSet(cs *ClaimsSet, vo *verifyOption, now time.Time) error {
 	return nil
 }
 
+func hasClaim(cs *ClaimsSet, claimName string) bool {
+	switch claimName {
+	case "iss":
+		return cs.Issuer != ""
+	case "sub":
+		return cs.Subject != ""
+	case "aud":
+		return len(cs.Audience) > 0
+	case "exp":
+		return cs.ExpirationTime != 0
+	case "nbf":
+		return cs.NotBefore != 0
+	case "iat":
+		return cs.IssuedAt != 0
+	case "jti":
+		return cs.JWTID != ""
+	}
+
+	_, ok := cs.PrivateClaims[claimName]
+	return ok
+}
+
 func verifyAudience(cs *ClaimsSet, aud []string) error {
 	for _, want := range aud {
 		for _, got := range cs.Audience {
@@ -117,7 +320,7 @@ func verifyAudience(cs *ClaimsSet, aud []string) error {
 			}
 		}
 	}
-	return fmt.Errorf("want=%v got=%v: %w", aud, cs.Audience, ErrAudienceIsNotMatch)
+	return newValidationError("aud", ErrAudienceIsNotMatch, "want=%v got=%v", aud, cs.Audience)
 }
 
 func verifyIssuer(cs *ClaimsSet, iss string) error {
@@ -125,5 +328,5 @@ func verifyIssuer(cs *ClaimsSet, iss string) error {
 		return nil
 	}
 
-	return fmt.Errorf("want=%v got=%v: %w", iss, cs.Issuer, ErrIssuerIsNotMatch)
+	return newValidationError("iss", ErrIssuerIsNotMatch, "want=%v got=%v", iss, cs.Issuer)
 }
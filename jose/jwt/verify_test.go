@@ -1,6 +1,8 @@
 package jwt_test
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"strings"
 	"testing"
@@ -11,7 +13,9 @@ import (
 	"github.com/kunitsucom/util.go/jose/jwa"
 	"github.com/kunitsucom/util.go/jose/jws"
 	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
 	testingz "github.com/kunitsucom/util.go/testing"
+	timez "github.com/kunitsucom/util.go/time"
 )
 
 func TestVerify(t *testing.T) {
@@ -37,7 +41,10 @@ func TestVerify(t *testing.T) {
 	t.Run("failure(cs.Decode)", func(t *testing.T) {
 		t.Parallel()
 		expect := "invalid character"
-		if _, _, err := jwt.Verify(jws.UseKey(testHS256Key), "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.invalid.jwt"); err == nil || !strings.Contains(err.Error(), expect) {
+		// NOTE: the payload is decoded after the signature is verified, so sign the invalid payload.
+		signingInput := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.invalid"
+		signatureEncoded := must.One(jws.Sign(jwa.HS256, jws.WithHMACKey(testHS256Key), signingInput))
+		if _, _, err := jwt.Verify(jws.UseKey(testHS256Key), signingInput+"."+signatureEncoded); err == nil || !strings.Contains(err.Error(), expect) {
 			t.Errorf("❌: jwt.Verify: err != %s: %v", expect, err)
 		}
 	})
@@ -53,14 +60,14 @@ func TestVerify(t *testing.T) {
 		}
 	})
 
-	t.Run("failure(nbf,jwt.ErrTokenIsExpired)", func(t *testing.T) {
+	t.Run("failure(nbf,jwt.ErrTokenIsNotBefore)", func(t *testing.T) {
 		t.Parallel()
 		signingInput, signatureEncoded, err := jwt.Sign(jws.WithHMACKey(testHS256Key), jose.NewHeader(jwa.HS256), jwt.NewClaimsSet(jwt.WithNotBefore(time.Now().Add(1*time.Hour))))
 		if err != nil {
 			t.Fatalf("❌: jwt.New: err != nil: %v", err)
 		}
-		if _, _, err := jwt.Verify(jws.UseKey(testHS256Key), signingInput+"."+signatureEncoded); err == nil || !errors.Is(err, jwt.ErrTokenIsExpired) {
-			t.Errorf("❌: jwt.Verify: err != jwt.ErrTokenIsExpired: %v", err)
+		if _, _, err := jwt.Verify(jws.UseKey(testHS256Key), signingInput+"."+signatureEncoded); err == nil || !errors.Is(err, jwt.ErrTokenIsNotBefore) {
+			t.Errorf("❌: jwt.Verify: err != jwt.ErrTokenIsNotBefore: %v", err)
 		}
 	})
 
@@ -111,3 +118,83 @@ func TestVerify(t *testing.T) {
 		}
 	})
 }
+
+func TestVerify_policy(t *testing.T) {
+	t.Parallel()
+
+	key := []byte(`your-256-bit-secret`)
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := timez.WithContext(context.Background(), now)
+	newToken := func(header *jose.Header, claims ...jwt.ClaimsSetOption) string {
+		return must.One(jwt.New(jws.WithHMACKey(key), header, jwt.NewClaimsSet(claims...)))
+	}
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		token := newToken(
+			jose.NewHeader(jwa.HS256, jose.WithType("application/AT+JWT")),
+			jwt.WithSubject("sub"), jwt.WithJWTID("jti"), jwt.WithPrivateClaim("client_id", "client"),
+			jwt.WithIssuedAt(now.Add(-5*time.Minute)), jwt.WithExpirationTime(now.Add(-10*time.Second)), jwt.WithNotBefore(now.Add(10*time.Second)),
+		)
+		if _, _, err := jwt.Verify(jws.UseKey(key), token,
+			jwt.VerifyContext(ctx),
+			jwt.VerifyLeeway(30*time.Second),
+			jwt.VerifyRequiredClaims("sub", "jti", "client_id"),
+			jwt.VerifyMaxAge(5*time.Minute),
+			jwt.VerifyAlgorithm(jwa.HS256, jwa.RS256),
+			jwt.VerifyType("at+jwt"),
+		); err != nil {
+			t.Errorf("❌: jwt.Verify: err != nil: %v", err)
+		}
+	})
+
+	t.Run("failure()", func(t *testing.T) {
+		t.Parallel()
+		for _, tt := range []struct {
+			name   string
+			token  string
+			opts   []jwt.VerifyOption
+			rule   string
+			expect error
+		}{
+			{"exp", newToken(jose.NewHeader(jwa.HS256), jwt.WithExpirationTime(now.Add(-time.Minute))), []jwt.VerifyOption{jwt.VerifyLeeway(30 * time.Second)}, "exp", jwt.ErrTokenIsExpired},
+			{"nbf", newToken(jose.NewHeader(jwa.HS256), jwt.WithNotBefore(now.Add(time.Minute))), []jwt.VerifyOption{jwt.VerifyLeeway(30 * time.Second)}, "nbf", jwt.ErrTokenIsNotBefore},
+			{"required", newToken(jose.NewHeader(jwa.HS256), jwt.WithSubject("sub")), []jwt.VerifyOption{jwt.VerifyRequiredClaims("sub", "jti")}, "jti", jwt.ErrRequiredClaimIsMissing},
+			{"iat,missing", newToken(jose.NewHeader(jwa.HS256), func(c *jwt.ClaimsSet) { c.IssuedAt = 0 }), []jwt.VerifyOption{jwt.VerifyMaxAge(time.Minute)}, "iat", jwt.ErrRequiredClaimIsMissing},
+			{"iat,old", newToken(jose.NewHeader(jwa.HS256), jwt.WithIssuedAt(now.Add(-time.Hour))), []jwt.VerifyOption{jwt.VerifyMaxAge(time.Minute)}, "iat", jwt.ErrTokenIsTooOld},
+			{"iat,future", newToken(jose.NewHeader(jwa.HS256), jwt.WithIssuedAt(now.Add(time.Hour))), []jwt.VerifyOption{jwt.VerifyMaxAge(time.Minute)}, "iat", jwt.ErrTokenIsIssuedInTheFuture},
+			{"alg", newToken(jose.NewHeader(jwa.HS256)), []jwt.VerifyOption{jwt.VerifyAlgorithm(jwa.RS256)}, "alg", jwt.ErrAlgorithmIsNotAllowed},
+			{"typ", newToken(jose.NewHeader(jwa.HS256, jose.WithType("JWT"))), []jwt.VerifyOption{jwt.VerifyType("at+jwt")}, "typ", jwt.ErrTypeIsNotMatch},
+			{"aud", newToken(jose.NewHeader(jwa.HS256), jwt.WithAudience("aud")), []jwt.VerifyOption{jwt.VerifyAudience("notMatch")}, "aud", jwt.ErrAudienceIsNotMatch},
+			{"iss", newToken(jose.NewHeader(jwa.HS256), jwt.WithIssuer("iss")), []jwt.VerifyOption{jwt.VerifyIssuer("notMatch")}, "iss", jwt.ErrIssuerIsNotMatch},
+		} {
+			_, _, err := jwt.Verify(jws.UseKey(key), tt.token, append(tt.opts, jwt.VerifyContext(ctx))...)
+			if !errors.Is(err, tt.expect) {
+				t.Errorf("❌: %s: jwt.Verify: err != %v: %v", tt.name, tt.expect, err)
+			}
+			var e *jwt.ValidationError
+			if !errors.As(err, &e) || e.Rule != tt.rule {
+				t.Errorf("❌: %s: jwt.Verify: rule != %s: %v", tt.name, tt.rule, err)
+			}
+		}
+	})
+
+	t.Run("failure(alg,none)", func(t *testing.T) {
+		t.Parallel()
+		token := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + must.One(jwt.NewClaimsSet().Encode()) + "."
+		if _, _, err := jwt.Verify(jws.UseKey(key), token, jwt.VerifyAlgorithm(jwa.HS256)); !errors.Is(err, jwt.ErrAlgorithmIsNotAllowed) {
+			t.Errorf("❌: jwt.Verify: err != jwt.ErrAlgorithmIsNotAllowed: %v", err)
+		}
+	})
+
+	t.Run("failure(jws.ErrCriticalHeaderParameterIsNotUnderstood)", func(t *testing.T) {
+		t.Parallel()
+		token := newToken(jose.NewHeader(jwa.HS256, jose.WithCritical([]string{"exp"}), jose.WithPrivateHeaderParameter("exp", 1)))
+		if _, _, err := jwt.Verify(jws.UseKey(key), token); !errors.Is(err, jws.ErrCriticalHeaderParameterIsNotUnderstood) {
+			t.Errorf("❌: jwt.Verify: err != jws.ErrCriticalHeaderParameterIsNotUnderstood: %v", err)
+		}
+		if _, _, err := jwt.Verify(jws.UseKey(key), token, jwt.VerifyJWSOptions(jws.VerifyCriticalHeaderParameters("exp"))); err != nil {
+			t.Errorf("❌: jwt.Verify: err != nil: %v", err)
+		}
+	})
+}
@@ -1,9 +1,18 @@
 package ilog
 
-import "context"
+import (
+	"context"
+	"strings"
+)
 
-type contextKeyLogger struct{}
+type (
+	contextKeyLogger      struct{}
+	contextKeyFields      struct{}
+	contextKeyTraceparent struct{}
+)
 
+// FromContext returns a copy of the logger in ctx, with the fields in ctx added by ContextWithFields,
+// and "trace_id" and "span_id" parsed from the traceparent in ctx added by ContextWithTraceparent.
 func FromContext(ctx context.Context) (logger Logger) { //nolint:ireturn
 	if ctx == nil {
 		Global().Copy().AddCallerSkip(1).Errorf("ilog: nil context")
@@ -15,12 +24,110 @@ func FromContext(ctx context.Context) (logger Logger) { //nolint:ireturn
 
 	if !ok {
 		Global().Copy().AddCallerSkip(1).Errorf("ilog: type assertion failed: expected=ilog.Logger, actual=%T, value=%#v", v, v)
-		return Global().Copy()
+		return withContextFields(ctx, Global().Copy())
 	}
 
-	return l.Copy()
+	return withContextFields(ctx, l.Copy())
 }
 
 func WithContext(ctx context.Context, logger Logger) context.Context {
 	return context.WithValue(ctx, contextKeyLogger{}, logger)
 }
+
+func withContextFields(ctx context.Context, logger Logger) Logger { //nolint:ireturn
+	fields := FieldsFromContext(ctx)
+	if traceID, spanID, ok := parseTraceparent(TraceparentFromContext(ctx)); ok {
+		fields = append(fields, Field{Key: "trace_id", Value: traceID}, Field{Key: "span_id", Value: spanID})
+	}
+
+	if len(fields) == 0 {
+		return logger
+	}
+
+	entry := appendField(logger, fields[0])
+	for _, f := range fields[1:] {
+		entry = appendField(entry, f)
+	}
+
+	return entry.Logger()
+}
+
+// ContextWithFields returns a copy of ctx with fields appended to the fields already in ctx,
+// so that the fields, e.g. request ID, tenant or user, can be added incrementally as ctx is passed down.
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	parent := FieldsFromContext(ctx)
+	return context.WithValue(ctx, contextKeyFields{}, append(parent, fields...))
+}
+
+// FieldsFromContext returns the fields in ctx added by ContextWithFields.
+func FieldsFromContext(ctx context.Context) []Field {
+	fields, _ := ctx.Value(contextKeyFields{}).([]Field)
+	// NOTE: clip the capacity so that appending to the returned fields does not modify the fields in ctx.
+	return fields[:len(fields):len(fields)]
+}
+
+// ContextWithTraceparent returns a copy of ctx with the value of W3C Trace Context "traceparent", e.g. the HTTP header.
+//
+//   - ref. https://www.w3.org/TR/trace-context/#traceparent-header
+//
+// Example:
+//
+//	ctx := ilog.ContextWithTraceparent(r.Context(), r.Header.Get("traceparent"))
+func ContextWithTraceparent(ctx context.Context, traceparent string) context.Context {
+	return context.WithValue(ctx, contextKeyTraceparent{}, traceparent)
+}
+
+// TraceparentFromContext returns the value of "traceparent" in ctx added by ContextWithTraceparent.
+func TraceparentFromContext(ctx context.Context) string {
+	traceparent, _ := ctx.Value(contextKeyTraceparent{}).(string)
+	return traceparent
+}
+
+// parseTraceparent parses traceparent, and returns trace-id and parent-id as traceID and spanID.
+//
+//   - ref. https://www.w3.org/TR/trace-context/#traceparent-header-field-values
+//
+// e.g.
+//
+//	traceparent = version "-" trace-id "-" parent-id "-" trace-flags
+//	00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func parseTraceparent(traceparent string) (traceID, spanID string, ok bool) {
+	const (
+		length        = 55
+		versionLen    = 2
+		traceIDLen    = 32
+		parentIDLen   = 16
+		traceFlagsLen = 2
+	)
+
+	// NOTE: The future versions may have the additional fields after trace-flags, delimited by "-".
+	if len(traceparent) < length || (len(traceparent) > length && traceparent[length] != '-') {
+		return "", "", false
+	}
+
+	parts := strings.Split(traceparent[:length], "-")
+	if len(parts) != 4 { //nolint:gomnd
+		return "", "", false
+	}
+	version, traceID, spanID, traceFlags := parts[0], parts[1], parts[2], parts[3]
+
+	switch {
+	case len(version) != versionLen || !isLowerHex(version) || version == "ff",
+		version == "00" && len(traceparent) != length,
+		len(traceID) != traceIDLen || !isLowerHex(traceID) || traceID == strings.Repeat("0", traceIDLen),
+		len(spanID) != parentIDLen || !isLowerHex(spanID) || spanID == strings.Repeat("0", parentIDLen),
+		len(traceFlags) != traceFlagsLen || !isLowerHex(traceFlags):
+		return "", "", false
+	}
+
+	return traceID, spanID, true
+}
+
+func isLowerHex(s string) bool {
+	for i := range len(s) {
+		if !('0' <= s[i] && s[i] <= '9') && !('a' <= s[i] && s[i] <= 'f') {
+			return false
+		}
+	}
+	return true
+}
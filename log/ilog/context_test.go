@@ -41,4 +41,55 @@ func TestContext(t *testing.T) {
 		}
 		t.Logf("ℹ️: buf:\n%s", buf)
 	})
+
+	t.Run("success,ContextWithFields", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		ctx := WithContext(context.Background(), NewBuilder(DebugLevel, NewSyncWriter(buf)).SetTimestampKey("").Build())
+		ctx = ContextWithFields(ctx, Field{Key: "requestID", Value: "req-1"})
+		child1 := ContextWithFields(ctx, Field{Key: "tenant", Value: "t-1"}, Field{Key: "userID", Value: 1})
+		child2 := ContextWithFields(ctx, Field{Key: "tenant", Value: "t-2"})
+		FromContext(child1).String("key", "value").Infof("child1")
+		FromContext(child2).Infof("child2")
+		FromContext(ctx).Infof("parent")
+		if expected := regexp.MustCompilePOSIX(`^{"severity":"INFO","caller":"ilog/context_test\.go:[0-9]+","message":"child1","requestID":"req-1","tenant":"t-1","userID":1,"key":"value"}` + "\n" +
+			`{"severity":"INFO","caller":"ilog/context_test\.go:[0-9]+","message":"child2","requestID":"req-1","tenant":"t-2"}` + "\n" +
+			`{"severity":"INFO","caller":"ilog/context_test\.go:[0-9]+","message":"parent","requestID":"req-1"}` + "\n$"); !expected.Match(buf.Bytes()) {
+			t.Errorf("❌: !expected.Match(buf.Bytes()):\n%s", buf)
+		}
+		t.Logf("ℹ️: buf:\n%s", buf)
+	})
+
+	t.Run("success,ContextWithTraceparent", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		ctx := WithContext(context.Background(), NewBuilder(DebugLevel, NewSyncWriter(buf)).SetTimestampKey("").Build())
+		ctx = ContextWithFields(ctx, Field{Key: "requestID", Value: "req-1"})
+		FromContext(ContextWithTraceparent(ctx, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")).Infof("version 00")
+		FromContext(ContextWithTraceparent(ctx, "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future")).Infof("future version")
+		if expected := regexp.MustCompilePOSIX(`^({"severity":"INFO","caller":"ilog/context_test\.go:[0-9]+","message":"(version 00|future version)","requestID":"req-1","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7"}` + "\n){2}$"); !expected.Match(buf.Bytes()) {
+			t.Errorf("❌: !expected.Match(buf.Bytes()):\n%s", buf)
+		}
+		t.Logf("ℹ️: buf:\n%s", buf)
+	})
+
+	t.Run("failure,invalidTraceparent", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		ctx := WithContext(context.Background(), NewBuilder(DebugLevel, NewSyncWriter(buf)).SetTimestampKey("").Build())
+		for _, traceparent := range []string{
+			"",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",           // too short
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", // version 00 has no additional fields
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",        // invalid version
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",        // invalid trace-id
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",        // invalid parent-id
+			"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",        // uppercase
+			"00_4bf92f3577b34da6a3ce929d0e0e4736_00f067aa0ba902b7_01",        // invalid delimiter
+			"00-4bf92f3577b34da6a3ce929d0e0e473-600f067aa0ba902b7-01",        // invalid length
+		} {
+			FromContext(ContextWithTraceparent(ctx, traceparent)).Infof("invalid")
+		}
+		if expected := regexp.MustCompilePOSIX(`^({"severity":"INFO","caller":"ilog/context_test\.go:[0-9]+","message":"invalid"}` + "\n){9}$"); !expected.Match(buf.Bytes()) {
+			t.Errorf("❌: !expected.Match(buf.Bytes()):\n%s", buf)
+		}
+		t.Logf("ℹ️: buf:\n%s", buf)
+	})
 }
@@ -0,0 +1,376 @@
+package ilog
+
+import (
+	"strconv"
+	"time"
+)
+
+// Header is the fixed fields at the beginning of a log entry.
+// If the key of a field is empty, the field is not output.
+type Header struct {
+	LevelKey  string
+	Level     Level
+	LevelText string
+
+	TimestampKey    string
+	Timestamp       time.Time
+	TimestampFormat string
+
+	CallerKey  string
+	CallerFile string
+	CallerLine int
+
+	MessageKey string
+	Message    string
+}
+
+// Encoder is the interface that encodes a log entry of the default implementation.
+// Each method appends the encoded bytes to dst and returns the extended buffer,
+// so that an Encoder can write into the pooled buffers without allocation.
+//
+// A log entry is encoded in the following order:
+//
+//	AppendHeader                 (each header field followed by the field delimiter)
+//	AppendKey, Append<Value>     (each field followed by AppendFieldDelimiter)
+//	AppendEnd                    (removes the trailing field delimiter)
+type Encoder interface {
+	// AppendHeader appends the beginning of a log entry and the header fields, each followed by the field delimiter.
+	AppendHeader(dst []byte, header Header) []byte
+	// AppendEnd appends the end of a log entry. It should remove the trailing field delimiter.
+	AppendEnd(dst []byte) []byte
+	// AppendFieldDelimiter appends the delimiter between the fields.
+	AppendFieldDelimiter(dst []byte) []byte
+
+	// AppendKey appends the key of a field, including the separator between the key and the value.
+	AppendKey(dst []byte, key string) []byte
+	AppendString(dst []byte, value string) []byte
+	AppendBool(dst []byte, value bool) []byte
+	AppendInt64(dst []byte, value int64) []byte
+	AppendUint64(dst []byte, value uint64) []byte
+	AppendFloat64(dst []byte, value float64, bitSize int) []byte
+	// AppendJSON appends the value which is already encoded as JSON, e.g. by json.Marshaler.
+	AppendJSON(dst []byte, value []byte) []byte
+	AppendNull(dst []byte) []byte
+}
+
+type jsonEncoder struct{}
+
+// NewJSONEncoder returns an Encoder which encodes a log entry as a single-line JSON object. This is the default.
+//
+//	{"severity":"INFO","timestamp":"2023-08-13T04:38:39.123456789+09:00","caller":"ilog/ilog.go:1","message":"message","key":"value"}
+func NewJSONEncoder() Encoder { //nolint:ireturn
+	return jsonEncoder{}
+}
+
+//nolint:gocritic
+func (e jsonEncoder) AppendHeader(dst []byte, h Header) []byte {
+	dst = append(dst, '{')
+	if len(h.LevelKey) > 0 {
+		dst = appendKey(dst, h.LevelKey)
+		dst = e.AppendString(dst, h.LevelText)
+		dst = append(dst, ',')
+	}
+	if len(h.TimestampKey) > 0 {
+		dst = appendKey(dst, h.TimestampKey)
+		dst = append(dst, '"')
+		dst = appendJSONEscapedString(dst, h.Timestamp.Format(h.TimestampFormat))
+		dst = append(dst, '"', ',')
+	}
+	if len(h.CallerKey) > 0 {
+		dst = appendKey(dst, h.CallerKey)
+		dst = append(dst, '"')
+		dst = appendJSONEscapedString(dst, h.CallerFile)
+		dst = append(dst, ':')
+		const base = 10
+		dst = strconv.AppendInt(dst, int64(h.CallerLine), base)
+		dst = append(dst, '"', ',')
+	}
+	if len(h.MessageKey) > 0 {
+		dst = appendKey(dst, h.MessageKey)
+		dst = e.AppendString(dst, h.Message)
+		dst = append(dst, ',')
+	}
+	return dst
+}
+
+func (jsonEncoder) AppendEnd(dst []byte) []byte {
+	if len(dst) > 0 && dst[len(dst)-1] == ',' {
+		dst[len(dst)-1] = '}'
+		return dst
+	}
+	return append(dst, '}')
+}
+
+func (jsonEncoder) AppendFieldDelimiter(dst []byte) []byte {
+	return append(dst, ',')
+}
+
+func (jsonEncoder) AppendKey(dst []byte, key string) []byte {
+	return appendKey(dst, key)
+}
+
+func (jsonEncoder) AppendString(dst []byte, value string) []byte {
+	dst = append(dst, '"')
+	dst = appendJSONEscapedString(dst, value)
+	return append(dst, '"')
+}
+
+func (jsonEncoder) AppendBool(dst []byte, value bool) []byte {
+	return strconv.AppendBool(dst, value)
+}
+
+func (jsonEncoder) AppendInt64(dst []byte, value int64) []byte {
+	const base = 10
+	return strconv.AppendInt(dst, value, base)
+}
+
+func (jsonEncoder) AppendUint64(dst []byte, value uint64) []byte {
+	const base = 10
+	return strconv.AppendUint(dst, value, base)
+}
+
+func (jsonEncoder) AppendFloat64(dst []byte, value float64, bitSize int) []byte {
+	return appendFloatFieldValue(dst, value, bitSize)
+}
+
+func (jsonEncoder) AppendJSON(dst []byte, value []byte) []byte {
+	return append(dst, value...)
+}
+
+func (jsonEncoder) AppendNull(dst []byte) []byte {
+	return append(dst, null...)
+}
+
+type logfmtEncoder struct{}
+
+// NewLogfmtEncoder returns an Encoder which encodes a log entry as logfmt.
+// The values which contain spaces, '=', '"' or control characters are quoted, and the invalid characters in the keys are replaced with '_'.
+//
+//	severity=INFO timestamp=2023-08-13T04:38:39.123456789+09:00 caller=ilog/ilog.go:1 message="log message" key=value
+func NewLogfmtEncoder() Encoder { //nolint:ireturn
+	return logfmtEncoder{}
+}
+
+//nolint:gocritic
+func (e logfmtEncoder) AppendHeader(dst []byte, h Header) []byte {
+	if len(h.LevelKey) > 0 {
+		dst = e.AppendKey(dst, h.LevelKey)
+		dst = e.AppendString(dst, h.LevelText)
+		dst = append(dst, ' ')
+	}
+	if len(h.TimestampKey) > 0 {
+		dst = e.AppendKey(dst, h.TimestampKey)
+		dst = e.AppendString(dst, h.Timestamp.Format(h.TimestampFormat))
+		dst = append(dst, ' ')
+	}
+	if len(h.CallerKey) > 0 {
+		dst = e.AppendKey(dst, h.CallerKey)
+		dst = appendLogfmtCaller(dst, h.CallerFile, h.CallerLine)
+		dst = append(dst, ' ')
+	}
+	if len(h.MessageKey) > 0 {
+		dst = e.AppendKey(dst, h.MessageKey)
+		dst = e.AppendString(dst, h.Message)
+		dst = append(dst, ' ')
+	}
+	return dst
+}
+
+func (logfmtEncoder) AppendEnd(dst []byte) []byte {
+	if len(dst) > 0 && dst[len(dst)-1] == ' ' {
+		return dst[:len(dst)-1]
+	}
+	return dst
+}
+
+func (logfmtEncoder) AppendFieldDelimiter(dst []byte) []byte {
+	return append(dst, ' ')
+}
+
+func (logfmtEncoder) AppendKey(dst []byte, key string) []byte {
+	dst = appendLogfmtKey(dst, key)
+	return append(dst, '=')
+}
+
+func (logfmtEncoder) AppendString(dst []byte, value string) []byte {
+	return appendLogfmtString(dst, value)
+}
+
+func (logfmtEncoder) AppendBool(dst []byte, value bool) []byte {
+	return strconv.AppendBool(dst, value)
+}
+
+func (logfmtEncoder) AppendInt64(dst []byte, value int64) []byte {
+	const base = 10
+	return strconv.AppendInt(dst, value, base)
+}
+
+func (logfmtEncoder) AppendUint64(dst []byte, value uint64) []byte {
+	const base = 10
+	return strconv.AppendUint(dst, value, base)
+}
+
+func (logfmtEncoder) AppendFloat64(dst []byte, value float64, bitSize int) []byte {
+	// NOTE: strconv.AppendFloat appends NaN, +Inf and -Inf as they are, which do not need to be quoted in logfmt.
+	return strconv.AppendFloat(dst, value, 'f', -1, bitSize)
+}
+
+func (logfmtEncoder) AppendJSON(dst []byte, value []byte) []byte {
+	return appendLogfmtString(dst, string(value))
+}
+
+func (logfmtEncoder) AppendNull(dst []byte) []byte {
+	return append(dst, null...)
+}
+
+func appendLogfmtKey(dst []byte, key string) []byte {
+	if len(key) == 0 {
+		return append(dst, '_')
+	}
+
+	for i := range len(key) {
+		if needsLogfmtQuote(key[i]) {
+			dst = append(dst, '_')
+			continue
+		}
+		dst = append(dst, key[i])
+	}
+
+	return dst
+}
+
+func appendLogfmtString(dst []byte, s string) []byte {
+	quote := len(s) == 0
+	for i := 0; i < len(s) && !quote; i++ {
+		quote = needsLogfmtQuote(s[i])
+	}
+
+	if !quote {
+		return append(dst, s...)
+	}
+
+	dst = append(dst, '"')
+	dst = appendJSONEscapedString(dst, s)
+	return append(dst, '"')
+}
+
+func appendLogfmtCaller(dst []byte, file string, line int) []byte {
+	dst = appendLogfmtString(dst, file)
+	dst = append(dst, ':')
+	const base = 10
+	return strconv.AppendInt(dst, int64(line), base)
+}
+
+func needsLogfmtQuote(c byte) bool {
+	const del = 0x7F
+	return c <= ' ' || c == '=' || c == '"' || c == del
+}
+
+// ANSI escape sequences for the console encoder.
+const (
+	colorReset   = "\x1b[0m"
+	colorFaint   = "\x1b[2m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+)
+
+type consoleEncoder struct {
+	logfmtEncoder
+
+	noColor bool
+}
+
+// NewConsoleEncoder returns an Encoder which encodes a log entry as human-readable text for the local development.
+// The level, timestamp, caller and message are written in this order without their keys, followed by the fields as key=value.
+// If noColor is false, the level badge and the keys are colored by ANSI escape sequences.
+//
+//	2023-08-13T04:38:39.123456789+09:00 INFO  ilog/ilog.go:1 > log message key=value
+func NewConsoleEncoder(noColor bool) Encoder { //nolint:ireturn
+	return consoleEncoder{noColor: noColor}
+}
+
+func (e consoleEncoder) color(dst []byte, color string) []byte {
+	if e.noColor {
+		return dst
+	}
+	return append(dst, color...)
+}
+
+//nolint:gocritic
+func (e consoleEncoder) AppendHeader(dst []byte, h Header) []byte {
+	if len(h.TimestampKey) > 0 {
+		dst = e.color(dst, colorFaint)
+		dst = h.Timestamp.AppendFormat(dst, h.TimestampFormat)
+		dst = e.color(dst, colorReset)
+		dst = append(dst, ' ')
+	}
+	if len(h.LevelKey) > 0 {
+		dst = e.appendLevelBadge(dst, h.Level, h.LevelText)
+		dst = append(dst, ' ')
+	}
+	if len(h.CallerKey) > 0 {
+		dst = e.color(dst, colorFaint)
+		dst = appendLogfmtCaller(dst, h.CallerFile, h.CallerLine)
+		dst = append(dst, " >"...)
+		dst = e.color(dst, colorReset)
+		dst = append(dst, ' ')
+	}
+	if len(h.MessageKey) > 0 {
+		dst = appendConsoleMessage(dst, h.Message)
+		dst = append(dst, ' ')
+	}
+	return dst
+}
+
+// appendConsoleMessage appends s as it is, except that the control characters are escaped,
+// so that s can neither break the line of the log entry nor inject ANSI escape sequences into the terminal.
+func appendConsoleMessage(dst []byte, s string) []byte {
+	const del = 0x7F
+	for i := range len(s) {
+		switch {
+		case s[i] == del:
+			dst = append(dst, `\u007f`...)
+		case s[i] <= 0x1F:
+			dst = appendJSONEscapedString(dst, s[i:i+1])
+		default:
+			dst = append(dst, s[i])
+		}
+	}
+	return dst
+}
+
+func (e consoleEncoder) appendLevelBadge(dst []byte, level Level, levelText string) []byte {
+	var color string
+	switch {
+	case level >= ErrorLevel:
+		color = colorRed
+	case level >= WarnLevel:
+		color = colorYellow
+	case level >= InfoLevel:
+		color = colorGreen
+	default:
+		color = colorMagenta
+	}
+
+	dst = e.color(dst, color)
+	dst = append(dst, levelText...)
+	dst = e.color(dst, colorReset)
+
+	// NOTE: pad the level badge to align the following columns.
+	const badgeWidth = 5
+	for i := len(levelText); i < badgeWidth; i++ {
+		dst = append(dst, ' ')
+	}
+
+	return dst
+}
+
+func (e consoleEncoder) AppendKey(dst []byte, key string) []byte {
+	dst = e.color(dst, colorCyan)
+	dst = appendLogfmtKey(dst, key)
+	dst = append(dst, '=')
+	return e.color(dst, colorReset)
+}
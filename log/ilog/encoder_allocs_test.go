@@ -0,0 +1,32 @@
+//go:build !race
+
+package ilog //nolint:testpackage
+
+import (
+	"io"
+	"testing"
+)
+
+// NOTE: sync.Pool drops the pooled objects randomly under the race detector, so the allocations are not stable.
+
+// TestEncoder_allocs tests that the encoders do not allocate more than the default JSON encoder, which writes into the pooled buffers.
+//
+//nolint:paralleltest
+func TestEncoder_allocs(t *testing.T) {
+	allocs := func(encoder Encoder) float64 {
+		l := NewBuilder(DebugLevel, io.Discard).SetEncoder(encoder).SetTimestampKey("").SetCallerKey("").Build()
+		return testing.AllocsPerRun(100, func() {
+			l.String("string", "value").Int("int", 1).Bool("bool", true).Infof("log message")
+		})
+	}
+
+	expected := allocs(NewJSONEncoder())
+	for name, encoder := range map[string]Encoder{
+		"logfmt":  NewLogfmtEncoder(),
+		"console": NewConsoleEncoder(false),
+	} {
+		if actual := allocs(encoder); actual > expected {
+			t.Errorf("❌: %s: expected(%v) < actual(%v)", name, expected, actual)
+		}
+	}
+}
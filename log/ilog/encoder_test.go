@@ -0,0 +1,114 @@
+package ilog //nolint:testpackage
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNewLogfmtEncoder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		defer t.Logf("ℹ️: buf:\n%s", buf)
+
+		l := NewBuilder(DebugLevel, buf).
+			SetEncoder(NewLogfmtEncoder()).
+			SetTimestampZone(time.UTC).
+			Build().
+			String("with", "logger").
+			Logger()
+
+		l.Any("bool", true).
+			Any("int", -1).
+			Any("uint", uint(1)).
+			Any("float64", 1.5).
+			Any("NaN", math.NaN()).
+			Any("string", "string").
+			Any("quoted", `a "quoted" string`).
+			Any("empty", "").
+			Any("invalid key=", "value=").
+			Any("json", map[string]int{"a": 1}).
+			Any("null", (*bool)(nil)).
+			Err(io.ErrUnexpectedEOF).
+			Infof("log %s", "message")
+
+		expected := regexp.MustCompilePOSIX(`^severity=INFO timestamp=[0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}\.?[0-9]*Z caller=ilog/encoder_test\.go:[0-9]+ message="log message" with=logger bool=true int=-1 uint=1 float64=1\.5 NaN=NaN string=string quoted="a \\"quoted\\" string" empty="" invalid_key_="value=" json="{\\"a\\":1}" null=null error="unexpected EOF"` + "\n$")
+		if !expected.Match(buf.Bytes()) {
+			t.Errorf("❌: !expected.Match(buf.Bytes()):\n%s", buf)
+		}
+	})
+
+	t.Run("success(empty)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+
+		NewBuilder(DebugLevel, buf).SetEncoder(NewLogfmtEncoder()).SetLevelKey("").SetTimestampKey("").SetCallerKey("").SetMessageKey("").Build().Debugf("Debugf")
+		if expected, actual := "\n", buf.String(); expected != actual {
+			t.Errorf("❌: expected(%q) != actual(%q)", expected, actual)
+		}
+	})
+}
+
+func TestNewConsoleEncoder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(noColor)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		defer t.Logf("ℹ️: buf:\n%s", buf)
+
+		l := NewBuilder(DebugLevel, buf).
+			SetEncoder(NewConsoleEncoder(true)).
+			SetTimestampFormat(time.TimeOnly).
+			Build()
+		l.String("key", "value").Int("int", 1).Warnf("log message")
+		l.Errorf("error message")
+
+		expected := regexp.MustCompilePOSIX(`^[0-9]{2}:[0-9]{2}:[0-9]{2} WARN  ilog/encoder_test\.go:[0-9]+ > log message key=value int=1` + "\n" +
+			`[0-9]{2}:[0-9]{2}:[0-9]{2} ERROR ilog/encoder_test\.go:[0-9]+ > error message` + "\n$")
+		if !expected.Match(buf.Bytes()) {
+			t.Errorf("❌: !expected.Match(buf.Bytes()):\n%s", buf)
+		}
+	})
+
+	t.Run("success(color)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		defer t.Logf("ℹ️: buf:\n%s", buf)
+
+		NewBuilder(DebugLevel, buf).
+			SetEncoder(NewConsoleEncoder(false)).
+			SetTimestampKey("").
+			SetCallerKey("").
+			Build().
+			String("key", "value").
+			Infof("log message")
+
+		if expected, actual := colorGreen+"INFO"+colorReset+"  log message "+colorCyan+"key="+colorReset+"value\n", buf.String(); expected != actual {
+			t.Errorf("❌: expected(%q) != actual(%q)", expected, actual)
+		}
+	})
+
+	t.Run("success(control_characters)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		defer t.Logf("ℹ️: buf:\n%s", buf)
+
+		NewBuilder(DebugLevel, buf).
+			SetEncoder(NewConsoleEncoder(true)).
+			SetTimestampKey("").
+			SetCallerKey("").
+			Build().
+			Infof("log \"message\"\nforged\x1b[31m\x7f")
+
+		if expected, actual := `INFO  log "message"\nforged\u001b[31m\u007f`+"\n", buf.String(); expected != actual {
+			t.Errorf("❌: expected(%q) != actual(%q)", expected, actual)
+		}
+	})
+}
@@ -41,6 +41,7 @@ type implLoggerConfig struct {
 	useLongCaller   bool
 	messageKey      string
 	separator       string
+	encoder         Encoder
 	writer          io.Writer
 }
 
@@ -87,6 +88,7 @@ func NewBuilder(level Level, w io.Writer) implLoggerConfig { //nolint:revive
 		useLongCaller:   false,
 		messageKey:      "message",
 		separator:       "\n",
+		encoder:         NewJSONEncoder(),
 		writer:          w,
 	}
 }
@@ -158,6 +160,13 @@ func (c implLoggerConfig) SetSeparator(separator string) implLoggerConfig { //no
 	return c
 }
 
+// SetEncoder sets the encoder of the log entries, e.g. NewLogfmtEncoder or NewConsoleEncoder.
+// Default is NewJSONEncoder.
+func (c implLoggerConfig) SetEncoder(encoder Encoder) implLoggerConfig { //nolint:revive
+	c.encoder = encoder
+	return c
+}
+
 // UseSyncWriter sets whether to use sync writer of the logger.
 func (c implLoggerConfig) UseSyncWriter() implLoggerConfig { //nolint:revive
 	switch v := c.writer.(type) {
@@ -318,9 +327,50 @@ func (*implLogEntry) Error() string {
 }
 
 func (e *implLogEntry) null(key string) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, null...)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
+	enc := e.logger.config.encoder
+	e.bytesBuffer.bytes = enc.AppendKey(e.bytesBuffer.bytes, key)
+	e.bytesBuffer.bytes = enc.AppendNull(e.bytesBuffer.bytes)
+	e.bytesBuffer.bytes = enc.AppendFieldDelimiter(e.bytesBuffer.bytes)
+	return e
+}
+
+func (e *implLogEntry) json(key string, value []byte) LogEntry { //nolint:ireturn
+	enc := e.logger.config.encoder
+	e.bytesBuffer.bytes = enc.AppendKey(e.bytesBuffer.bytes, key)
+	e.bytesBuffer.bytes = enc.AppendJSON(e.bytesBuffer.bytes, value)
+	e.bytesBuffer.bytes = enc.AppendFieldDelimiter(e.bytesBuffer.bytes)
+	return e
+}
+
+func (e *implLogEntry) string(key string, value string) LogEntry { //nolint:ireturn
+	enc := e.logger.config.encoder
+	e.bytesBuffer.bytes = enc.AppendKey(e.bytesBuffer.bytes, key)
+	e.bytesBuffer.bytes = enc.AppendString(e.bytesBuffer.bytes, value)
+	e.bytesBuffer.bytes = enc.AppendFieldDelimiter(e.bytesBuffer.bytes)
+	return e
+}
+
+func (e *implLogEntry) int64(key string, value int64) LogEntry { //nolint:ireturn
+	enc := e.logger.config.encoder
+	e.bytesBuffer.bytes = enc.AppendKey(e.bytesBuffer.bytes, key)
+	e.bytesBuffer.bytes = enc.AppendInt64(e.bytesBuffer.bytes, value)
+	e.bytesBuffer.bytes = enc.AppendFieldDelimiter(e.bytesBuffer.bytes)
+	return e
+}
+
+func (e *implLogEntry) uint64(key string, value uint64) LogEntry { //nolint:ireturn
+	enc := e.logger.config.encoder
+	e.bytesBuffer.bytes = enc.AppendKey(e.bytesBuffer.bytes, key)
+	e.bytesBuffer.bytes = enc.AppendUint64(e.bytesBuffer.bytes, value)
+	e.bytesBuffer.bytes = enc.AppendFieldDelimiter(e.bytesBuffer.bytes)
+	return e
+}
+
+func (e *implLogEntry) float(key string, value float64, bitSize int) LogEntry { //nolint:ireturn
+	enc := e.logger.config.encoder
+	e.bytesBuffer.bytes = enc.AppendKey(e.bytesBuffer.bytes, key)
+	e.bytesBuffer.bytes = enc.AppendFloat64(e.bytesBuffer.bytes, value, bitSize)
+	e.bytesBuffer.bytes = enc.AppendFieldDelimiter(e.bytesBuffer.bytes)
 	return e
 }
 
@@ -382,10 +432,7 @@ func (e *implLogEntry) Any(key string, value interface{}) (le LogEntry) { //noli
 		if err != nil {
 			return e.ErrWithKey(key, fmt.Errorf("json.Marshaler: v.MarshalJSON: %w", err))
 		}
-		e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-		e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, b...)
-		e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-		return e
+		return e.json(key, b)
 	case fmt.Formatter:
 		return e.String(key, fmt.Sprintf("%+v", v))
 	case fmt.Stringer:
@@ -401,34 +448,24 @@ func (e *implLogEntry) Any(key string, value interface{}) (le LogEntry) { //noli
 		if err != nil {
 			return e.String(key, fmt.Sprintf("%v", v))
 		}
-		e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-		e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, b...)
-		e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-		return e
+		return e.json(key, b)
 	}
 }
 
 func (e *implLogEntry) Bool(key string, value bool) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	e.bytesBuffer.bytes = strconv.AppendBool(e.bytesBuffer.bytes, value)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
+	enc := e.logger.config.encoder
+	e.bytesBuffer.bytes = enc.AppendKey(e.bytesBuffer.bytes, key)
+	e.bytesBuffer.bytes = enc.AppendBool(e.bytesBuffer.bytes, value)
+	e.bytesBuffer.bytes = enc.AppendFieldDelimiter(e.bytesBuffer.bytes)
 	return e
 }
 
 func (e *implLogEntry) Bytes(key string, value []byte) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"')
-	e.bytesBuffer.bytes = appendJSONEscapedString(e.bytesBuffer.bytes, string(value))
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"', ',')
-	return e
+	return e.string(key, string(value))
 }
 
 func (e *implLogEntry) Duration(key string, value time.Duration) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"')
-	e.bytesBuffer.bytes = appendJSONEscapedString(e.bytesBuffer.bytes, value.String())
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"', ',')
-	return e
+	return e.string(key, value.String())
 }
 
 func (e *implLogEntry) Err(err error) LogEntry { //nolint:ireturn
@@ -450,92 +487,49 @@ func (e *implLogEntry) ErrWithKey(key string, err error) (le LogEntry) { //nolin
 	} else {
 		v = err.Error()
 	}
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"')
-	e.bytesBuffer.bytes = appendJSONEscapedString(e.bytesBuffer.bytes, v)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"')
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.string(key, v)
 }
 
 func (e *implLogEntry) Float32(key string, value float32) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
 	const bitSize = 32
-	e.bytesBuffer.bytes = appendFloatFieldValue(e.bytesBuffer.bytes, float64(value), bitSize)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.float(key, float64(value), bitSize)
 }
 
 func (e *implLogEntry) Float64(key string, value float64) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
 	const bitSize = 64
-	e.bytesBuffer.bytes = appendFloatFieldValue(e.bytesBuffer.bytes, value, bitSize)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.float(key, value, bitSize)
 }
 
 func (e *implLogEntry) Int(key string, value int) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	const base = 10
-	e.bytesBuffer.bytes = strconv.AppendInt(e.bytesBuffer.bytes, int64(value), base)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.int64(key, int64(value))
 }
 
 func (e *implLogEntry) Int32(key string, value int32) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	const base = 10
-	e.bytesBuffer.bytes = strconv.AppendInt(e.bytesBuffer.bytes, int64(value), base)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.int64(key, int64(value))
 }
 
 func (e *implLogEntry) Int64(key string, value int64) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	const base = 10
-	e.bytesBuffer.bytes = strconv.AppendInt(e.bytesBuffer.bytes, value, base)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.int64(key, value)
 }
 
 func (e *implLogEntry) String(key string, value string) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"')
-	e.bytesBuffer.bytes = appendJSONEscapedString(e.bytesBuffer.bytes, value)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"', ',')
-	return e
+	return e.string(key, value)
 }
 
 func (e *implLogEntry) Time(key string, value time.Time) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"')
-	e.bytesBuffer.bytes = appendJSONEscapedString(e.bytesBuffer.bytes, value.Format(e.logger.config.timestampFormat))
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, '"', ',')
-	return e
+	return e.string(key, value.Format(e.logger.config.timestampFormat))
 }
 
 func (e *implLogEntry) Uint(key string, value uint) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	const base = 10
-	e.bytesBuffer.bytes = strconv.AppendUint(e.bytesBuffer.bytes, uint64(value), base)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.uint64(key, uint64(value))
 }
 
 func (e *implLogEntry) Uint32(key string, value uint32) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	const base = 10
-	e.bytesBuffer.bytes = strconv.AppendUint(e.bytesBuffer.bytes, uint64(value), base)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.uint64(key, uint64(value))
 }
 
 func (e *implLogEntry) Uint64(key string, value uint64) LogEntry { //nolint:ireturn
-	e.bytesBuffer.bytes = appendKey(e.bytesBuffer.bytes, key)
-	const base = 10
-	e.bytesBuffer.bytes = strconv.AppendUint(e.bytesBuffer.bytes, value, base)
-	e.bytesBuffer.bytes = append(e.bytesBuffer.bytes, ',')
-	return e
+	return e.uint64(key, value)
 }
 
 func (e *implLogEntry) Logger() Logger { //nolint:ireturn
@@ -581,36 +575,29 @@ func (e *implLogEntry) logf(level Level, format string, args ...interface{}) err
 	b, put := getBytesBuffer()
 	defer put()
 
-	b.bytes = append(b.bytes, '{')
-
-	if len(e.logger.config.levelKey) > 0 {
-		b.bytes = appendKey(b.bytes, e.logger.config.levelKey)
-		b.bytes = appendLevelField(b.bytes, e.logger.config.levels, level)
-		b.bytes = append(b.bytes, ',')
+	header := Header{
+		LevelKey:        e.logger.config.levelKey,
+		Level:           level,
+		LevelText:       levelText(e.logger.config.levels, level),
+		TimestampKey:    e.logger.config.timestampKey,
+		TimestampFormat: e.logger.config.timestampFormat,
+		CallerKey:       e.logger.config.callerKey,
+		MessageKey:      e.logger.config.messageKey,
+		Message:         format,
 	}
-	if len(e.logger.config.timestampKey) > 0 {
-		b.bytes = appendKey(b.bytes, e.logger.config.timestampKey)
-		b.bytes = append(b.bytes, '"')
-		b.bytes = appendJSONEscapedString(b.bytes, time.Now().In(e.logger.config.timestampZone).Format(e.logger.config.timestampFormat))
-		b.bytes = append(b.bytes, '"', ',')
+	if len(header.TimestampKey) > 0 {
+		header.Timestamp = time.Now().In(e.logger.config.timestampZone)
 	}
-	if len(e.logger.config.callerKey) > 0 {
-		b.bytes = appendKey(b.bytes, e.logger.config.callerKey)
-		b.bytes = append(b.bytes, '"')
-		b.bytes = appendCaller(b.bytes, e.logger.config.callerSkip, e.logger.config.useLongCaller)
-		b.bytes = append(b.bytes, '"', ',')
+	if len(header.CallerKey) > 0 {
+		header.CallerFile, header.CallerLine = caller(e.logger.config.callerSkip, e.logger.config.useLongCaller)
 	}
-	if len(e.logger.config.messageKey) > 0 {
-		b.bytes = appendKey(b.bytes, e.logger.config.messageKey)
-		b.bytes = append(b.bytes, '"')
-		if len(args) > 0 {
-			b.bytes = appendJSONEscapedString(b.bytes, fmt.Sprintf(format, args...))
-		} else {
-			b.bytes = appendJSONEscapedString(b.bytes, format)
-		}
-		b.bytes = append(b.bytes, '"', ',')
+	if len(header.MessageKey) > 0 && len(args) > 0 {
+		header.Message = fmt.Sprintf(format, args...)
 	}
 
+	enc := e.logger.config.encoder
+	b.bytes = enc.AppendHeader(b.bytes, header)
+
 	if len(e.logger.fields) > 0 {
 		b.bytes = append(b.bytes, e.logger.fields...)
 	}
@@ -619,11 +606,7 @@ func (e *implLogEntry) logf(level Level, format string, args ...interface{}) err
 		b.bytes = append(b.bytes, e.bytesBuffer.bytes...)
 	}
 
-	if b.bytes[len(b.bytes)-1] == ',' {
-		b.bytes[len(b.bytes)-1] = '}'
-	} else {
-		b.bytes = append(b.bytes, '}')
-	}
+	b.bytes = enc.AppendEnd(b.bytes)
 
 	if _, err := e.logger.config.writer.Write(append(b.bytes, e.logger.config.separator...)); err != nil {
 		err = fmt.Errorf("w.logger.writer.Write: p=%s: %w", b.bytes, err)
@@ -720,7 +703,7 @@ func appendFloatFieldValue(dst []byte, value float64, bitSize int) []byte {
 	return strconv.AppendFloat(dst, value, 'f', -1, bitSize)
 }
 
-func appendCaller(dst []byte, callerSkip int, useLongCaller bool) []byte {
+func caller(callerSkip int, useLongCaller bool) (file string, line int) {
 	pc, put := getPCBuffer()
 	defer put()
 
@@ -729,22 +712,10 @@ func appendCaller(dst []byte, callerSkip int, useLongCaller bool) []byte {
 		frame, _ = runtime.CallersFrames(pc.pc).Next()
 	}
 
-	return appendCallerFromFrame(dst, frame, useLongCaller)
-}
-
-// appendCallerFromFrame was split off from appendCaller in order to test different behaviors depending on the contents of the `runtime.Frame`.
-func appendCallerFromFrame(dst []byte, frame runtime.Frame, useLongCaller bool) []byte {
 	if useLongCaller {
-		dst = appendJSONEscapedString(dst, frame.File)
-	} else {
-		dst = appendJSONEscapedString(dst, extractShortPath(frame.File))
+		return frame.File, frame.Line
 	}
-
-	dst = append(dst, ':')
-	const base = 10
-	dst = strconv.AppendInt(dst, int64(frame.Line), base)
-
-	return dst
+	return extractShortPath(frame.File), frame.Line
 }
 
 func extractShortPath(path string) string {
@@ -775,14 +746,10 @@ func appendKey(dst []byte, key string) []byte {
 	return dst
 }
 
-func appendLevelField(dst []byte, levels map[Level]string, level Level) []byte {
+func levelText(levels map[Level]string, level Level) string {
 	v, ok := levels[level]
 	if !ok {
 		v = "DEBUG"
 	}
-
-	dst = append(dst, '"')
-	dst = appendJSONEscapedString(dst, v)
-	dst = append(dst, '"')
-	return dst
+	return v
 }
@@ -0,0 +1,118 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/kunitsucom/util.go/log/ilog"
+)
+
+// logf is the common method of ilog.Logger and ilog.LogEntry.
+type logf interface {
+	Any(key string, value interface{}) ilog.LogEntry
+	Logf(level ilog.Level, format string, args ...interface{})
+}
+
+type implHandler struct {
+	logger ilog.Logger
+	groups []string
+}
+
+var _ slog.Handler = (*implHandler)(nil)
+
+// NewHandler returns slog.Handler which writes log records into logger.
+// slog.Level is converted by IlogLevel, and the attributes of With and WithGroup are added to logger as fields,
+// with the keys qualified by the group names, e.g. "group.key".
+// The caller of ilog.Logger is the caller of *slog.Logger, e.g. slog.Info or (*slog.Logger).Info.
+//
+// Example:
+//
+//	slog.SetDefault(slog.New(ilogslog.NewHandler(ilog.L())))
+func NewHandler(logger ilog.Logger) slog.Handler { //nolint:ireturn
+	// NOTE: (*slog.Logger).log, (*slog.Logger).Info, (*implHandler).Handle instead of ilog.Logger.Infof
+	const callerSkip = 3
+	return &implHandler{
+		logger: logger.Copy().AddCallerSkip(callerSkip),
+	}
+}
+
+func (h *implHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return IlogLevel(level) >= h.logger.Level()
+}
+
+//nolint:gocritic
+func (h *implHandler) Handle(_ context.Context, r slog.Record) error {
+	var entry logf = h.logger
+	r.Attrs(func(attr slog.Attr) bool {
+		entry = appendAttr(entry, h.groups, attr)
+		return true
+	})
+
+	// NOTE: Call Logf directly here, because the caller skip depends on the depth of the call stack.
+	entry.Logf(IlogLevel(r.Level), r.Message)
+	return nil
+}
+
+func (h *implHandler) WithAttrs(attrs []slog.Attr) slog.Handler { //nolint:ireturn
+	if len(attrs) == 0 {
+		return h
+	}
+
+	var entry logf = h.logger
+	for _, attr := range attrs {
+		entry = appendAttr(entry, h.groups, attr)
+	}
+
+	copied := h.copy()
+	if e, ok := entry.(ilog.LogEntry); ok {
+		copied.logger = e.Logger()
+	}
+	return copied
+}
+
+func (h *implHandler) WithGroup(name string) slog.Handler { //nolint:ireturn
+	if name == "" {
+		return h
+	}
+
+	copied := h.copy()
+	copied.groups = append(copied.groups, name)
+	return copied
+}
+
+func (h *implHandler) copy() *implHandler {
+	return &implHandler{
+		logger: h.logger.Copy(),
+		groups: append(make([]string, 0, len(h.groups)+1), h.groups...),
+	}
+}
+
+// appendAttr appends attr to entry as a field, and flattens the group attribute into the fields qualified by the group names.
+//
+//nolint:ireturn
+func appendAttr(entry logf, groups []string, attr slog.Attr) logf {
+	attr.Value = attr.Value.Resolve()
+
+	// NOTE: If an Attr's key and value are both the zero value, ignore the Attr.
+	if attr.Equal(slog.Attr{}) {
+		return entry
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			entry = appendAttr(entry, groups, a)
+		}
+		return entry
+	}
+
+	key := attr.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + key
+	}
+
+	return entry.Any(key, attr.Value.Any())
+}
@@ -0,0 +1,330 @@
+// Package slog provides the bridges between ilog and the standard library's log/slog in both directions.
+//
+// New returns ilog.Logger whose log entries are handled by slog.Handler,
+// and NewHandler returns slog.Handler which writes log records into ilog.Logger.
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/kunitsucom/util.go/log/ilog"
+)
+
+// ilog.Level is twice as fine-grained as slog.Level, i.e. ilog.DebugLevel(-8), ilog.InfoLevel(0), ilog.WarnLevel(8), ilog.ErrorLevel(16)
+// correspond to slog.LevelDebug(-4), slog.LevelInfo(0), slog.LevelWarn(4), slog.LevelError(8).
+const levelRatio = 2
+
+// SlogLevel converts ilog.Level to slog.Level.
+func SlogLevel(level ilog.Level) slog.Level {
+	return slog.Level(int(level) / levelRatio)
+}
+
+// IlogLevel converts slog.Level to ilog.Level.
+// The level which overflows ilog.Level is rounded to the minimum or maximum of ilog.Level.
+func IlogLevel(level slog.Level) ilog.Level {
+	const minLevel, maxLevel = -128, 127
+	switch l := int(level) * levelRatio; {
+	case l < minLevel:
+		return minLevel
+	case l > maxLevel:
+		return maxLevel
+	default:
+		return ilog.Level(l)
+	}
+}
+
+type implLogger struct {
+	level      ilog.Level
+	handler    slog.Handler
+	callerSkip int
+}
+
+// New returns ilog.Logger whose log entries are handled by handler.
+//
+// Example:
+//
+//	l := ilogslog.New(ilog.InfoLevel, slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+func New(level ilog.Level, handler slog.Handler) ilog.Logger { //nolint:ireturn
+	// NOTE: runtime.Callers, (*implLogEntry).logf, (*implLogger).Infof or (*implLogEntry).Infof
+	const defaultCallerSkip = 3
+	return &implLogger{
+		level:      level,
+		handler:    handler,
+		callerSkip: defaultCallerSkip,
+	}
+}
+
+func (l *implLogger) Level() ilog.Level {
+	return l.level
+}
+
+func (l *implLogger) SetLevel(level ilog.Level) ilog.Logger { //nolint:ireturn
+	copied := l.copy()
+	copied.level = level
+	return copied
+}
+
+func (l *implLogger) AddCallerSkip(skip int) ilog.Logger { //nolint:ireturn
+	copied := l.copy()
+	copied.callerSkip += skip
+	return copied
+}
+
+func (l *implLogger) Copy() ilog.Logger { //nolint:ireturn
+	return l.copy()
+}
+
+func (l *implLogger) copy() *implLogger {
+	copied := *l
+	return &copied
+}
+
+func (l *implLogger) Any(key string, value interface{}) ilog.LogEntry { //nolint:ireturn
+	return l.new().Any(key, value)
+}
+
+func (l *implLogger) Bool(key string, value bool) ilog.LogEntry { //nolint:ireturn
+	return l.new().Bool(key, value)
+}
+
+func (l *implLogger) Bytes(key string, value []byte) ilog.LogEntry { //nolint:ireturn
+	return l.new().Bytes(key, value)
+}
+
+func (l *implLogger) Duration(key string, value time.Duration) ilog.LogEntry { //nolint:ireturn
+	return l.new().Duration(key, value)
+}
+
+func (l *implLogger) Err(err error) ilog.LogEntry { //nolint:ireturn
+	return l.new().Err(err)
+}
+
+func (l *implLogger) ErrWithKey(key string, err error) ilog.LogEntry { //nolint:ireturn
+	return l.new().ErrWithKey(key, err)
+}
+
+func (l *implLogger) Float32(key string, value float32) ilog.LogEntry { //nolint:ireturn
+	return l.new().Float32(key, value)
+}
+
+func (l *implLogger) Float64(key string, value float64) ilog.LogEntry { //nolint:ireturn
+	return l.new().Float64(key, value)
+}
+
+func (l *implLogger) Int(key string, value int) ilog.LogEntry { //nolint:ireturn
+	return l.new().Int(key, value)
+}
+
+func (l *implLogger) Int32(key string, value int32) ilog.LogEntry { //nolint:ireturn
+	return l.new().Int32(key, value)
+}
+
+func (l *implLogger) Int64(key string, value int64) ilog.LogEntry { //nolint:ireturn
+	return l.new().Int64(key, value)
+}
+
+func (l *implLogger) String(key, value string) ilog.LogEntry { //nolint:ireturn
+	return l.new().String(key, value)
+}
+
+func (l *implLogger) Time(key string, value time.Time) ilog.LogEntry { //nolint:ireturn
+	return l.new().Time(key, value)
+}
+
+func (l *implLogger) Uint(key string, value uint) ilog.LogEntry { //nolint:ireturn
+	return l.new().Uint(key, value)
+}
+
+func (l *implLogger) Uint32(key string, value uint32) ilog.LogEntry { //nolint:ireturn
+	return l.new().Uint32(key, value)
+}
+
+func (l *implLogger) Uint64(key string, value uint64) ilog.LogEntry { //nolint:ireturn
+	return l.new().Uint64(key, value)
+}
+
+func (l *implLogger) Debugf(format string, args ...interface{}) {
+	l.new().logf(ilog.DebugLevel, format, args...)
+}
+
+func (l *implLogger) Infof(format string, args ...interface{}) {
+	l.new().logf(ilog.InfoLevel, format, args...)
+}
+
+func (l *implLogger) Warnf(format string, args ...interface{}) {
+	l.new().logf(ilog.WarnLevel, format, args...)
+}
+
+func (l *implLogger) Errorf(format string, args ...interface{}) {
+	l.new().logf(ilog.ErrorLevel, format, args...)
+}
+
+func (l *implLogger) Logf(level ilog.Level, format string, args ...interface{}) {
+	l.new().logf(level, format, args...)
+}
+
+func (l *implLogger) Write(p []byte) (int, error) {
+	l.new().logf(l.level, string(p))
+	return len(p), nil
+}
+
+func (l *implLogger) new() *implLogEntry {
+	return &implLogEntry{
+		logger: l,
+		attrs:  make([]slog.Attr, 0),
+	}
+}
+
+//nolint:errname
+type implLogEntry struct {
+	logger *implLogger
+	attrs  []slog.Attr
+}
+
+func (*implLogEntry) Error() string {
+	return ilog.ErrLogEntryIsNotWritten.Error()
+}
+
+func (e *implLogEntry) Any(key string, value interface{}) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Any(key, value))
+	return e
+}
+
+func (e *implLogEntry) Bool(key string, value bool) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Bool(key, value))
+	return e
+}
+
+func (e *implLogEntry) Bytes(key string, value []byte) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.String(key, string(value)))
+	return e
+}
+
+func (e *implLogEntry) Duration(key string, value time.Duration) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Duration(key, value))
+	return e
+}
+
+func (e *implLogEntry) Err(err error) ilog.LogEntry { //nolint:ireturn
+	return e.ErrWithKey("error", err)
+}
+
+func (e *implLogEntry) ErrWithKey(key string, err error) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Any(key, err))
+	return e
+}
+
+func (e *implLogEntry) Float32(key string, value float32) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Float64(key, float64(value)))
+	return e
+}
+
+func (e *implLogEntry) Float64(key string, value float64) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Float64(key, value))
+	return e
+}
+
+func (e *implLogEntry) Int(key string, value int) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Int(key, value))
+	return e
+}
+
+func (e *implLogEntry) Int32(key string, value int32) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Int64(key, int64(value)))
+	return e
+}
+
+func (e *implLogEntry) Int64(key string, value int64) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Int64(key, value))
+	return e
+}
+
+func (e *implLogEntry) String(key, value string) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.String(key, value))
+	return e
+}
+
+func (e *implLogEntry) Time(key string, value time.Time) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Time(key, value))
+	return e
+}
+
+func (e *implLogEntry) Uint(key string, value uint) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Uint64(key, uint64(value)))
+	return e
+}
+
+func (e *implLogEntry) Uint32(key string, value uint32) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Uint64(key, uint64(value)))
+	return e
+}
+
+func (e *implLogEntry) Uint64(key string, value uint64) ilog.LogEntry { //nolint:ireturn
+	e.attrs = append(e.attrs, slog.Uint64(key, value))
+	return e
+}
+
+func (e *implLogEntry) Logger() ilog.Logger { //nolint:ireturn
+	copied := e.logger.copy()
+	copied.handler = copied.handler.WithAttrs(e.attrs)
+	return copied
+}
+
+func (e *implLogEntry) Write(p []byte) (int, error) {
+	e.logf(e.logger.level, string(p))
+	return len(p), nil
+}
+
+func (e *implLogEntry) Debugf(format string, args ...interface{}) {
+	e.logf(ilog.DebugLevel, format, args...)
+}
+
+func (e *implLogEntry) Infof(format string, args ...interface{}) {
+	e.logf(ilog.InfoLevel, format, args...)
+}
+
+func (e *implLogEntry) Warnf(format string, args ...interface{}) {
+	e.logf(ilog.WarnLevel, format, args...)
+}
+
+func (e *implLogEntry) Errorf(format string, args ...interface{}) {
+	e.logf(ilog.ErrorLevel, format, args...)
+}
+
+func (e *implLogEntry) Logf(level ilog.Level, format string, args ...interface{}) {
+	e.logf(level, format, args...)
+}
+
+func (e *implLogEntry) logf(level ilog.Level, format string, args ...interface{}) {
+	defer func() {
+		e.attrs = make([]slog.Attr, 0)
+	}()
+
+	if level < e.logger.level {
+		return
+	}
+
+	ctx := context.Background()
+	slogLevel := SlogLevel(level)
+	if !e.logger.handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(e.logger.callerSkip, pcs[:])
+
+	r := slog.NewRecord(time.Now(), slogLevel, msg, pcs[0])
+	r.AddAttrs(e.attrs...)
+	// NOTE: The error of Handle is ignored as with the other implementations, e.g. the write error.
+	// Logging it by ilog.Global() would recurse infinitely if the global logger is this logger with the failing handler.
+	_ = e.logger.handler.Handle(ctx, r)
+}
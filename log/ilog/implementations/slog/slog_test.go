@@ -0,0 +1,121 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/kunitsucom/util.go/log/ilog"
+	ilogslog "github.com/kunitsucom/util.go/log/ilog/implementations/slog"
+)
+
+func TestLevel(t *testing.T) {
+	t.Parallel()
+
+	for ilogLevel, slogLevel := range map[ilog.Level]slog.Level{
+		ilog.DebugLevel: slog.LevelDebug,
+		ilog.InfoLevel:  slog.LevelInfo,
+		ilog.WarnLevel:  slog.LevelWarn,
+		ilog.ErrorLevel: slog.LevelError,
+	} {
+		if actual := ilogslog.SlogLevel(ilogLevel); actual != slogLevel {
+			t.Errorf("❌: ilogslog.SlogLevel: %d: expect(%s) != actual(%s)", ilogLevel, slogLevel, actual)
+		}
+		if actual := ilogslog.IlogLevel(slogLevel); actual != ilogLevel {
+			t.Errorf("❌: ilogslog.IlogLevel: %s: expect(%d) != actual(%d)", slogLevel, ilogLevel, actual)
+		}
+	}
+
+	if actual := ilogslog.IlogLevel(slog.Level(100)); actual != 127 {
+		t.Errorf("❌: ilogslog.IlogLevel: expect(127) != actual(%d)", actual)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	l := ilogslog.New(ilog.InfoLevel, slog.NewJSONHandler(buf, &slog.HandlerOptions{AddSource: true, Level: slog.LevelDebug})).
+		Any("any", "any").
+		Bool("bool", true).
+		Bytes("bytes", []byte("bytes")).
+		Duration("duration", time.Second).
+		Err(io.ErrUnexpectedEOF).
+		Float32("float32", 1.5).
+		Int("int", 1).
+		Int32("int32", 1).
+		Uint("uint", 1).
+		Logger()
+
+	l.String("string", "entry").Infof("info message: %d", 1)
+	l.Debugf("debug message")
+
+	t.Logf("ℹ️: buf:\n%s", buf)
+
+	var actual map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("❌: json.Unmarshal: %v", err)
+	}
+	if actual["msg"] != "info message: 1" || actual["level"] != "INFO" || actual["string"] != "entry" || actual["bytes"] != "bytes" || actual["error"] != io.ErrUnexpectedEOF.Error() {
+		t.Errorf("❌: unexpected: %v", actual)
+	}
+	if source, _ := actual["source"].(map[string]interface{}); !regexp.MustCompile(`slog_test\.go$`).MatchString(source["file"].(string)) { //nolint:forcetypeassert
+		t.Errorf("❌: source: %v", source)
+	}
+}
+
+type testWriterFunc func(p []byte) (int, error)
+
+func (f testWriterFunc) Write(p []byte) (int, error) { return f(p) }
+
+//nolint:paralleltest // for ilog.SetGlobal
+func TestNew_HandleError(t *testing.T) {
+	var writes int
+	l := ilogslog.New(ilog.DebugLevel, slog.NewJSONHandler(testWriterFunc(func([]byte) (int, error) {
+		writes++
+		return 0, io.ErrClosedPipe
+	}), nil))
+	defer ilog.SetGlobal(l)()
+
+	// NOTE: the error of the handler is not logged by ilog.Global(), which is l itself.
+	l.Errorf("error message")
+	if writes != 1 {
+		t.Errorf("❌: writes: expect(1) != actual(%d)", writes)
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+
+		buf := bytes.NewBuffer(nil)
+		l := slog.New(ilogslog.NewHandler(ilog.NewBuilder(ilog.InfoLevel, buf).SetTimestampKey("").Build())).
+			With("with", "value").
+			WithGroup("group").
+			With(slog.Int("int", 1))
+
+		l.Info("info message", slog.Group("nested", slog.String("string", "value")), slog.Duration("duration", time.Second))
+		l.Debug("debug message")
+
+		expect := regexp.MustCompile(`^{"severity":"INFO","caller":"slog/slog_test\.go:[0-9]+","message":"info message","with":"value","group\.int":1,"group\.nested\.string":"value","group\.duration":"1s"}\n$`)
+		if !expect.Match(buf.Bytes()) {
+			t.Errorf("❌: expect(%s) != actual(%s)", expect, buf)
+		}
+	})
+
+	t.Run("success(Enabled)", func(t *testing.T) {
+		t.Parallel()
+
+		h := ilogslog.NewHandler(ilog.NewBuilder(ilog.WarnLevel, io.Discard).Build())
+		if h.Enabled(context.Background(), slog.LevelInfo) || !h.Enabled(context.Background(), slog.LevelWarn) {
+			t.Errorf("❌: (slog.Handler).Enabled: unexpected")
+		}
+	})
+}
@@ -0,0 +1,495 @@
+package ilog
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// samplerOverflowMessage is the message of samplerKey which the messages exceeding the max number of keys are counted together as.
+const samplerOverflowMessage = "ilog: other messages"
+
+type samplerKey struct {
+	level   Level
+	message string
+}
+
+type samplerCounter struct {
+	windowStart time.Time
+	count       uint64
+}
+
+type sampler struct {
+	mu sync.Mutex
+
+	// "first N per interval then every Mth" per level+message
+	interval   time.Duration
+	first      uint64
+	thereafter uint64
+	counters   map[samplerKey]*samplerCounter
+	prunedAt   time.Time
+
+	// token bucket
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+
+	summaryInterval time.Duration
+	summaryLevel    Level
+	dropped         map[samplerKey]uint64
+
+	// maxKeys is the max number of the distinct levels and messages which are counted separately,
+	// so that e.g. Write with the distinct payloads cannot grow counters and dropped without limit.
+	maxKeys int
+
+	now func() time.Time
+}
+
+// SampledLoggerOption is the option for NewSampledLogger.
+type SampledLoggerOption func(s *sampler)
+
+// WithSampling sets the sampling which logs the first N entries per interval, and then every Mth entry, for each level and message.
+// The message is the format string, so the entries of the same format string with the different arguments are counted together.
+// If thereafter is 0, all entries after the first N are dropped until the next interval.
+func WithSampling(interval time.Duration, first, thereafter uint64) SampledLoggerOption {
+	return func(s *sampler) {
+		s.interval = interval
+		s.first = first
+		s.thereafter = thereafter
+	}
+}
+
+// WithRateLimit sets the token bucket rate limiting which logs eventsPerSecond entries on average, and burst entries at most at once,
+// for all levels and messages.
+func WithRateLimit(eventsPerSecond float64, burst int) SampledLoggerOption {
+	return func(s *sampler) {
+		s.rate = eventsPerSecond
+		s.burst = float64(burst)
+		s.tokens = float64(burst)
+	}
+}
+
+// WithSummaryInterval sets the interval to log the summary of the dropped entries. Default is 1 minute.
+// If interval is 0 or less, the summary is not logged periodically, but only when the context is done.
+func WithSummaryInterval(interval time.Duration) SampledLoggerOption {
+	return func(s *sampler) {
+		s.summaryInterval = interval
+	}
+}
+
+// WithSummaryLevel sets the level to log the summary of the dropped entries. Default is WarnLevel.
+func WithSummaryLevel(level Level) SampledLoggerOption {
+	return func(s *sampler) {
+		s.summaryLevel = level
+	}
+}
+
+//nolint:cyclop
+func (s *sampler) allow(level Level, message string) bool {
+	if s.interval <= 0 && s.rate <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key := samplerKey{level: level, message: message}
+
+	if s.interval > 0 {
+		// NOTE: prune the expired counters here too, since flush may not be called periodically.
+		if now.Sub(s.prunedAt) >= s.interval {
+			s.prune(now)
+		}
+		if _, ok := s.counters[key]; !ok && len(s.counters) >= s.maxKeys {
+			key.message = samplerOverflowMessage
+		}
+		c, ok := s.counters[key]
+		if !ok {
+			c = &samplerCounter{windowStart: now}
+			s.counters[key] = c
+		}
+		if now.Sub(c.windowStart) >= s.interval {
+			c.windowStart, c.count = now, 0
+		}
+		c.count++
+		if c.count > s.first && (s.thereafter == 0 || (c.count-s.first)%s.thereafter != 0) {
+			s.drop(key)
+			return false
+		}
+	}
+
+	if s.rate > 0 {
+		if !s.lastFill.IsZero() {
+			s.tokens = math.Min(s.burst, s.tokens+now.Sub(s.lastFill).Seconds()*s.rate)
+		}
+		s.lastFill = now
+		if s.tokens < 1 {
+			s.drop(key)
+			return false
+		}
+		s.tokens--
+	}
+
+	return true
+}
+
+// drop counts the dropped entry of key. s.mu must be held.
+func (s *sampler) drop(key samplerKey) {
+	if _, ok := s.dropped[key]; !ok && len(s.dropped) >= s.maxKeys {
+		key.message = samplerOverflowMessage
+	}
+	s.dropped[key]++
+}
+
+// prune removes the counters whose interval has expired. s.mu must be held.
+func (s *sampler) prune(now time.Time) {
+	for key, c := range s.counters {
+		if now.Sub(c.windowStart) >= s.interval {
+			delete(s.counters, key)
+		}
+	}
+	s.prunedAt = now
+}
+
+// flush returns the dropped counts since the last flush, and removes the counters whose interval has expired.
+func (s *sampler) flush() map[samplerKey]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(s.now())
+
+	dropped := s.dropped
+	s.dropped = make(map[samplerKey]uint64)
+	return dropped
+}
+
+func (s *sampler) summarize(logger Logger) {
+	for key, count := range s.flush() {
+		logger.
+			Int("droppedLevel", int(key.level)).
+			String("droppedMessage", key.message).
+			Uint64("droppedCount", count).
+			Logf(s.summaryLevel, "ilog: log entries were dropped by sampling")
+	}
+}
+
+type sampledLogger struct {
+	logger  Logger
+	sampler *sampler
+}
+
+// NewSampledLogger returns a Logger which samples and rate-limits the log entries of logger, which can be any implementation of Logger.
+// The dropped entries are counted for each level and message, and the summary is logged to logger at the interval of WithSummaryInterval,
+// until ctx is done. When ctx is done, the last summary is logged.
+// If there are more than 1000 distinct messages, the others are counted together as "ilog: other messages".
+//
+// Example:
+//
+//	l := ilog.NewSampledLogger(ctx, ilog.L(),
+//		ilog.WithSampling(time.Second, 100, 100),
+//		ilog.WithRateLimit(1000, 100),
+//	)
+func NewSampledLogger(ctx context.Context, logger Logger, opts ...SampledLoggerOption) Logger { //nolint:ireturn
+	const (
+		defaultSummaryInterval = 1 * time.Minute
+		defaultMaxKeys         = 1000
+	)
+
+	s := &sampler{
+		counters:        make(map[samplerKey]*samplerCounter),
+		summaryInterval: defaultSummaryInterval,
+		summaryLevel:    WarnLevel,
+		dropped:         make(map[samplerKey]uint64),
+		maxKeys:         defaultMaxKeys,
+		now:             time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	go func() {
+		summaryLogger := logger.Copy()
+		// NOTE: receiving from nil channel blocks forever, so the summary is not logged periodically if tick is nil.
+		var tick <-chan time.Time
+		if s.summaryInterval > 0 {
+			ticker := time.NewTicker(s.summaryInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				s.summarize(summaryLogger)
+				return
+			case <-tick:
+				s.summarize(summaryLogger)
+			}
+		}
+	}()
+
+	// NOTE: sampledLogger and sampledLogEntry call the methods of logger, so skip their frame.
+	return &sampledLogger{
+		logger:  logger.Copy().AddCallerSkip(1),
+		sampler: s,
+	}
+}
+
+func (l *sampledLogger) Level() Level {
+	return l.logger.Level()
+}
+
+func (l *sampledLogger) SetLevel(level Level) Logger { //nolint:ireturn
+	return &sampledLogger{logger: l.logger.SetLevel(level), sampler: l.sampler}
+}
+
+func (l *sampledLogger) AddCallerSkip(skip int) Logger { //nolint:ireturn
+	return &sampledLogger{logger: l.logger.AddCallerSkip(skip), sampler: l.sampler}
+}
+
+func (l *sampledLogger) Copy() Logger { //nolint:ireturn
+	return &sampledLogger{logger: l.logger.Copy(), sampler: l.sampler}
+}
+
+func (l *sampledLogger) new(entry LogEntry) *sampledLogEntry {
+	return &sampledLogEntry{logger: l, entry: entry}
+}
+
+func (l *sampledLogger) Any(key string, value interface{}) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Any(key, value))
+}
+
+func (l *sampledLogger) Bool(key string, value bool) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Bool(key, value))
+}
+
+func (l *sampledLogger) Bytes(key string, value []byte) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Bytes(key, value))
+}
+
+func (l *sampledLogger) Duration(key string, value time.Duration) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Duration(key, value))
+}
+
+func (l *sampledLogger) Err(err error) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Err(err))
+}
+
+func (l *sampledLogger) ErrWithKey(key string, err error) LogEntry { //nolint:ireturn
+	return l.new(l.logger.ErrWithKey(key, err))
+}
+
+func (l *sampledLogger) Float32(key string, value float32) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Float32(key, value))
+}
+
+func (l *sampledLogger) Float64(key string, value float64) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Float64(key, value))
+}
+
+func (l *sampledLogger) Int(key string, value int) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Int(key, value))
+}
+
+func (l *sampledLogger) Int32(key string, value int32) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Int32(key, value))
+}
+
+func (l *sampledLogger) Int64(key string, value int64) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Int64(key, value))
+}
+
+func (l *sampledLogger) String(key, value string) LogEntry { //nolint:ireturn
+	return l.new(l.logger.String(key, value))
+}
+
+func (l *sampledLogger) Time(key string, value time.Time) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Time(key, value))
+}
+
+func (l *sampledLogger) Uint(key string, value uint) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Uint(key, value))
+}
+
+func (l *sampledLogger) Uint32(key string, value uint32) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Uint32(key, value))
+}
+
+func (l *sampledLogger) Uint64(key string, value uint64) LogEntry { //nolint:ireturn
+	return l.new(l.logger.Uint64(key, value))
+}
+
+func (l *sampledLogger) allow(level Level, message string) bool {
+	return level >= l.logger.Level() && l.sampler.allow(level, message)
+}
+
+func (l *sampledLogger) Debugf(format string, args ...interface{}) {
+	if l.allow(DebugLevel, format) {
+		l.logger.Debugf(format, args...)
+	}
+}
+
+func (l *sampledLogger) Infof(format string, args ...interface{}) {
+	if l.allow(InfoLevel, format) {
+		l.logger.Infof(format, args...)
+	}
+}
+
+func (l *sampledLogger) Warnf(format string, args ...interface{}) {
+	if l.allow(WarnLevel, format) {
+		l.logger.Warnf(format, args...)
+	}
+}
+
+func (l *sampledLogger) Errorf(format string, args ...interface{}) {
+	if l.allow(ErrorLevel, format) {
+		l.logger.Errorf(format, args...)
+	}
+}
+
+func (l *sampledLogger) Logf(level Level, format string, args ...interface{}) {
+	if l.allow(level, format) {
+		l.logger.Logf(level, format, args...)
+	}
+}
+
+func (l *sampledLogger) Write(p []byte) (int, error) {
+	if !l.allow(l.logger.Level(), string(p)) {
+		return len(p), nil
+	}
+	return l.logger.Write(p) //nolint:wrapcheck
+}
+
+//nolint:errname
+type sampledLogEntry struct {
+	logger *sampledLogger
+	entry  LogEntry
+}
+
+func (e *sampledLogEntry) Error() string {
+	return e.entry.Error()
+}
+
+func (e *sampledLogEntry) Any(key string, value interface{}) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Any(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Bool(key string, value bool) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Bool(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Bytes(key string, value []byte) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Bytes(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Duration(key string, value time.Duration) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Duration(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Err(err error) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Err(err)
+	return e
+}
+
+func (e *sampledLogEntry) ErrWithKey(key string, err error) LogEntry { //nolint:ireturn
+	e.entry = e.entry.ErrWithKey(key, err)
+	return e
+}
+
+func (e *sampledLogEntry) Float32(key string, value float32) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Float32(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Float64(key string, value float64) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Float64(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Int(key string, value int) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Int(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Int32(key string, value int32) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Int32(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Int64(key string, value int64) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Int64(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) String(key, value string) LogEntry { //nolint:ireturn
+	e.entry = e.entry.String(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Time(key string, value time.Time) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Time(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Uint(key string, value uint) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Uint(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Uint32(key string, value uint32) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Uint32(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Uint64(key string, value uint64) LogEntry { //nolint:ireturn
+	e.entry = e.entry.Uint64(key, value)
+	return e
+}
+
+func (e *sampledLogEntry) Logger() Logger { //nolint:ireturn
+	return &sampledLogger{logger: e.entry.Logger(), sampler: e.logger.sampler}
+}
+
+func (e *sampledLogEntry) Debugf(format string, args ...interface{}) {
+	if e.logger.allow(DebugLevel, format) {
+		e.entry.Debugf(format, args...)
+	}
+}
+
+func (e *sampledLogEntry) Infof(format string, args ...interface{}) {
+	if e.logger.allow(InfoLevel, format) {
+		e.entry.Infof(format, args...)
+	}
+}
+
+func (e *sampledLogEntry) Warnf(format string, args ...interface{}) {
+	if e.logger.allow(WarnLevel, format) {
+		e.entry.Warnf(format, args...)
+	}
+}
+
+func (e *sampledLogEntry) Errorf(format string, args ...interface{}) {
+	if e.logger.allow(ErrorLevel, format) {
+		e.entry.Errorf(format, args...)
+	}
+}
+
+func (e *sampledLogEntry) Logf(level Level, format string, args ...interface{}) {
+	if e.logger.allow(level, format) {
+		e.entry.Logf(level, format, args...)
+	}
+}
+
+func (e *sampledLogEntry) Write(p []byte) (int, error) {
+	if !e.logger.allow(e.logger.logger.Level(), string(p)) {
+		return len(p), nil
+	}
+	return e.entry.Write(p) //nolint:wrapcheck
+}
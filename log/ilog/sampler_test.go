@@ -0,0 +1,205 @@
+package ilog //nolint:testpackage
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestSampledLogger(t *testing.T, buf *bytes.Buffer, opts ...SampledLoggerOption) (*sampledLogger, *testClock) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	clock := &testClock{now: time.Date(2023, 8, 13, 4, 38, 39, 0, time.UTC)}
+	l := NewSampledLogger(ctx, NewBuilder(DebugLevel, NewSyncWriter(buf)).SetTimestampKey("").Build(), opts...).(*sampledLogger) //nolint:forcetypeassert
+	l.sampler.mu.Lock()
+	l.sampler.now = clock.Now
+	l.sampler.mu.Unlock()
+	return l, clock
+}
+
+func TestNewSampledLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(WithSampling)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		defer t.Logf("ℹ️: buf:\n%s", buf)
+
+		l, clock := newTestSampledLogger(t, buf, WithSampling(time.Second, 2, 3))
+		for i := range 10 {
+			l.Int("i", i).Errorf("error: %d", i)
+		}
+		l.Warnf("error: %d", 0) // NOTE: counted separately for each level.
+		clock.Add(time.Second)
+		l.Errorf("error: %d", 10)
+
+		for i, expected := range []string{`"i":0`, `"i":1`, `"i":4`, `"i":7`, `"WARN"`, `"error: 10"`} {
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 6 {
+				t.Fatalf("❌: len(lines) != 6: %d", len(lines))
+			}
+			if !strings.Contains(lines[i], expected) {
+				t.Errorf("❌: lines[%d]: expected(%s) != actual(%s)", i, expected, lines[i])
+			}
+		}
+
+		if dropped := l.sampler.flush(); dropped[samplerKey{ErrorLevel, "error: %d"}] != 6 {
+			t.Errorf("❌: dropped: %v", dropped)
+		}
+	})
+
+	t.Run("success(WithSampling,prune,maxKeys)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+
+		l, clock := newTestSampledLogger(t, buf, WithSampling(time.Second, 0, 0), WithSummaryInterval(0))
+		l.sampler.mu.Lock()
+		l.sampler.maxKeys = 2
+		l.sampler.mu.Unlock()
+
+		for _, p := range []string{"a", "b", "c", "d"} {
+			_, _ = l.Write([]byte(p))
+		}
+		l.sampler.mu.Lock()
+		if len(l.sampler.counters) != 3 || len(l.sampler.dropped) != 3 || l.sampler.dropped[samplerKey{DebugLevel, samplerOverflowMessage}] != 2 {
+			t.Errorf("❌: counters=%v dropped=%v", l.sampler.counters, l.sampler.dropped)
+		}
+		l.sampler.mu.Unlock()
+
+		// NOTE: the expired counters are pruned without flush.
+		clock.Add(time.Second)
+		_, _ = l.Write([]byte("e"))
+		l.sampler.mu.Lock()
+		if _, ok := l.sampler.counters[samplerKey{DebugLevel, "e"}]; len(l.sampler.counters) != 1 || !ok {
+			t.Errorf("❌: counters=%v", l.sampler.counters)
+		}
+		l.sampler.mu.Unlock()
+	})
+
+	t.Run("success(WithRateLimit)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		defer t.Logf("ℹ️: buf:\n%s", buf)
+
+		l, clock := newTestSampledLogger(t, buf, WithRateLimit(1, 2))
+		for range 5 {
+			l.Infof("info")
+		}
+		clock.Add(time.Second)
+		for range 5 {
+			l.Warnf("warn")
+		}
+
+		if expected, actual := 3, strings.Count(buf.String(), "\n"); expected != actual {
+			t.Errorf("❌: expected(%d) != actual(%d)", expected, actual)
+		}
+	})
+
+	t.Run("success(summary)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		defer t.Logf("ℹ️: buf:\n%s", buf)
+
+		l, _ := newTestSampledLogger(t, buf, WithSampling(time.Second, 1, 0), WithSummaryLevel(ErrorLevel))
+		for range 3 {
+			l.Debugf("debug")
+		}
+		buf.Reset()
+		l.sampler.summarize(l.logger)
+
+		expected := regexp.MustCompilePOSIX(`^{"severity":"ERROR","caller":"ilog/[a-z_]+\.go:[0-9]+","message":"ilog: log entries were dropped by sampling","droppedLevel":-8,"droppedMessage":"debug","droppedCount":2}` + "\n$")
+		if !expected.Match(buf.Bytes()) {
+			t.Errorf("❌: !expected.Match(buf.Bytes()):\n%s", buf)
+		}
+	})
+
+	t.Run("success(context.Canceled)", func(t *testing.T) {
+		t.Parallel()
+		written := make(chan string, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		l := NewSampledLogger(ctx, NewBuilder(DebugLevel, testWriterFunc(func(p []byte) (int, error) {
+			written <- string(p)
+			return len(p), nil
+		})).Build(), WithSampling(time.Minute, 0, 0))
+		l.Infof("dropped")
+		cancel()
+
+		select {
+		case actual := <-written:
+			if !strings.Contains(actual, `"droppedCount":1`) {
+				t.Errorf("❌: summary: %s", actual)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("❌: summary is not written")
+		}
+	})
+
+	t.Run("success(WithSummaryInterval,0)", func(t *testing.T) {
+		t.Parallel()
+		written := make(chan string, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		l := NewSampledLogger(ctx, NewBuilder(DebugLevel, testWriterFunc(func(p []byte) (int, error) {
+			written <- string(p)
+			return len(p), nil
+		})).Build(), WithSampling(time.Minute, 0, 0), WithSummaryInterval(0))
+		l.Infof("dropped")
+		cancel()
+
+		select {
+		case actual := <-written:
+			if !strings.Contains(actual, `"droppedCount":1`) {
+				t.Errorf("❌: summary: %s", actual)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("❌: summary is not written")
+		}
+	})
+
+	t.Run("success(caller,Logger)", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		defer t.Logf("ℹ️: buf:\n%s", buf)
+
+		l, _ := newTestSampledLogger(t, buf)
+		l.String("key", "value").Logger().SetLevel(InfoLevel).Copy().Infof("info")
+		l.Infof("info")
+		_, _ = l.Write([]byte("write"))
+
+		expected := regexp.MustCompilePOSIX(`^({"severity":"(INFO|DEBUG)","caller":"ilog/sampler_test\.go:[0-9]+","message":"(info|write)"(,"key":"value")?}` + "\n){3}$")
+		if !expected.Match(buf.Bytes()) {
+			t.Errorf("❌: !expected.Match(buf.Bytes()):\n%s", buf)
+		}
+	})
+}
+
+type testWriterFunc func(p []byte) (int, error)
+
+func (f testWriterFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -6,4 +6,6 @@ const (
 	_ contextKey = iota
 	keyXRealIP
 	keyRequestBodyBuffer
+	keyDPoPProof
+	keyJWTClaimsSet
 )
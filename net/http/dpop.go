@@ -0,0 +1,154 @@
+package httpz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kunitsucom/util.go/openid/dpop"
+)
+
+const HeaderDPoP = dpop.HeaderDPoP
+
+func ContextDPoPProof(ctx context.Context) (proof *dpop.Proof, ok bool) {
+	v, ok := ctx.Value(keyDPoPProof).(*dpop.Proof)
+	return v, ok
+}
+
+func ContextWithDPoPProof(parent context.Context, proof *dpop.Proof) context.Context {
+	return context.WithValue(parent, keyDPoPProof, proof)
+}
+
+type (
+	dpopHandlerConfig struct {
+		requestURI   func(r *http.Request) string
+		errorHandler func(r *http.Request, err error)
+	}
+
+	NewDPoPHandlerOption interface {
+		apply(cfg *dpopHandlerConfig)
+	}
+
+	newDPoPHandlerOptionRequestURIFunc func(r *http.Request) string
+	newDPoPHandlerOptionErrorHandler   func(r *http.Request, err error)
+)
+
+func (f newDPoPHandlerOptionRequestURIFunc) apply(cfg *dpopHandlerConfig) {
+	cfg.requestURI = f
+}
+
+// WithNewDPoPHandlerOptionRequestURIFunc sets the function which returns "htu" of the request,
+// e.g. to use the external URL behind the reverse proxy. Default is DPoPRequestURI.
+func WithNewDPoPHandlerOptionRequestURIFunc(f func(r *http.Request) string) NewDPoPHandlerOption { //nolint:ireturn
+	return newDPoPHandlerOptionRequestURIFunc(f)
+}
+
+func (f newDPoPHandlerOptionErrorHandler) apply(cfg *dpopHandlerConfig) {
+	cfg.errorHandler = f
+}
+
+// WithNewDPoPHandlerOptionErrorHandler sets the handler which receives the error of confirmation or (*dpop.Verifier).Verify, e.g. for logging.
+// The error is not written to the response, since it may contain the details of the token or the verification.
+func WithNewDPoPHandlerOptionErrorHandler(errorHandler func(r *http.Request, err error)) NewDPoPHandlerOption { //nolint:ireturn
+	return newDPoPHandlerOptionErrorHandler(errorHandler)
+}
+
+// DPoPRequestURI returns the URI of the request without query and fragment, which is compared with "htu" of DPoP proof.
+func DPoPRequestURI(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.Path
+}
+
+// NewDPoPHandler returns the middleware of the resource server which accepts the DPoP-bound access tokens.
+// It verifies DPoP proof of DPoP header against the request and "Authorization: DPoP <access token>",
+// and binds it to "jkt" of "cnf" claim of the access token which is returned by confirmation.
+// The verified proof is stored in the context, and can be taken by ContextDPoPProof.
+//
+// The access token itself is not verified by this middleware, so confirmation should verify it and return its "cnf.jkt",
+// e.g. by jwt.Verify and dpop.JWKThumbprintFromClaimsSet, or by the token introspection.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-7
+//
+// Example:
+//
+//	verifier := dpop.NewVerifier(ctx)
+//
+//	httpz.NewDPoPHandler(verifier, func(r *http.Request, accessToken string) (string, error) {
+//		_, claimsSet, err := jwt.Verify(jws.UseECDSAKey(publicKey), accessToken)
+//		if err != nil {
+//			return "", err
+//		}
+//		return dpop.JWKThumbprintFromClaimsSet(claimsSet)
+//	})
+func NewDPoPHandler(verifier *dpop.Verifier, confirmation func(r *http.Request, accessToken string) (jkt string, err error), opts ...NewDPoPHandlerOption) func(next http.Handler) http.Handler {
+	c := &dpopHandlerConfig{
+		requestURI: DPoPRequestURI,
+	}
+
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+
+	algs := strings.Join(verifier.Algorithms(), " ")
+
+	// - ref. https://www.rfc-editor.org/rfc/rfc9449#section-7.1
+	unauthorized := func(rw http.ResponseWriter, errorCode, errorDescription string) {
+		challenge := dpop.AuthorizationScheme + " algs=" + quoteString(algs)
+		if errorCode != "" {
+			challenge += ", error=" + quoteString(errorCode) + ", error_description=" + quoteString(errorDescription)
+		}
+		rw.Header().Set(HeaderWWWAuthenticate, challenge)
+		rw.WriteHeader(http.StatusUnauthorized)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			scheme, accessToken, _ := strings.Cut(r.Header.Get(HeaderAuthorization), " ")
+			if !strings.EqualFold(scheme, dpop.AuthorizationScheme) || accessToken == "" {
+				unauthorized(rw, "", "")
+				return
+			}
+
+			// NOTE: There is not more than one DPoP HTTP request header field.
+			proofs := r.Header.Values(HeaderDPoP)
+			if len(proofs) != 1 {
+				unauthorized(rw, "invalid_dpop_proof", "DPoP proof is missing or duplicated")
+				return
+			}
+
+			jkt, err := confirmation(r, accessToken)
+			if err != nil {
+				if c.errorHandler != nil {
+					c.errorHandler(r, err)
+				}
+				unauthorized(rw, "invalid_token", "access token is invalid")
+				return
+			}
+
+			proof, err := verifier.Verify(r.Context(), proofs[0], r.Method, c.requestURI(r),
+				dpop.VerifyAccessToken(accessToken),
+				dpop.VerifyJWKThumbprint(jkt),
+			)
+			if err != nil {
+				if c.errorHandler != nil {
+					c.errorHandler(r, err)
+				}
+				if errors.Is(err, dpop.ErrJWKThumbprintIsNotMatch) {
+					unauthorized(rw, "invalid_token", "access token is not bound to the DPoP key")
+					return
+				}
+				// NOTE: error_description is a fixed message, since the detail of err should not be exposed to the client,
+				// and the characters of error_description are limited.
+				//   - ref. https://www.rfc-editor.org/rfc/rfc6750#section-3
+				unauthorized(rw, "invalid_dpop_proof", "DPoP proof is invalid")
+				return
+			}
+
+			next.ServeHTTP(rw, r.WithContext(ContextWithDPoPProof(r.Context(), proof)))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package httpz_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/must"
+	httpz "github.com/kunitsucom/util.go/net/http"
+	"github.com/kunitsucom/util.go/openid/dpop"
+	testingz "github.com/kunitsucom/util.go/testing"
+)
+
+func TestContextDPoPProof(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := httpz.ContextDPoPProof(context.Background()); ok {
+		t.Errorf("❌: httpz.ContextDPoPProof: ok == true")
+	}
+}
+
+//nolint:cyclop
+func TestNewDPoPHandler(t *testing.T) {
+	t.Parallel()
+
+	const testAccessToken = "test_access_token"
+	key := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	prover := must.One(dpop.NewProver(key, jwa.ES256))
+
+	newHandler := func() http.Handler {
+		confirmation := func(_ *http.Request, accessToken string) (string, error) {
+			if accessToken != testAccessToken {
+				return "", errors.New("invalid access token") //nolint:goerr113
+			}
+			return prover.JWKThumbprint(), nil
+		}
+		return httpz.NewDPoPHandler(dpop.NewVerifier(context.Background()), confirmation)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			proof, ok := httpz.ContextDPoPProof(r.Context())
+			if !ok || proof.JWKThumbprint != prover.JWKThumbprint() {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+		}))
+	}
+
+	newRequest := func(accessToken string, proofs ...string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://resource.example.com/protected?q=1", nil)
+		if accessToken != "" {
+			r.Header.Set(httpz.HeaderAuthorization, "DPoP "+accessToken)
+		}
+		for _, proof := range proofs {
+			r.Header.Add(httpz.HeaderDPoP, proof)
+		}
+		return r
+	}
+
+	newProof := func(accessToken string) string {
+		return must.One(prover.NewProof(context.Background(), http.MethodGet, "http://resource.example.com/protected", dpop.WithAccessToken(accessToken)))
+	}
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		newHandler().ServeHTTP(rec, newRequest(testAccessToken, newProof(testAccessToken)))
+		if rec.Code != http.StatusOK {
+			t.Errorf("❌: code != http.StatusOK: %d: %s", rec.Code, rec.Header().Get(httpz.HeaderWWWAuthenticate))
+		}
+	})
+
+	t.Run("failure()", func(t *testing.T) {
+		t.Parallel()
+
+		replayed := newProof(testAccessToken)
+		handler := newHandler()
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest(testAccessToken, replayed))
+
+		for name, tt := range map[string]struct {
+			r      *http.Request
+			expect string
+		}{
+			"authorization": {newRequest(""), `DPoP algs="RS256 RS384 RS512 PS256 PS384 PS512 ES256 ES384 ES512 EdDSA"`},
+			"dpop":          {newRequest(testAccessToken), `error="invalid_dpop_proof"`},
+			"dpop,multiple": {newRequest(testAccessToken, newProof(testAccessToken), newProof(testAccessToken)), `error="invalid_dpop_proof"`},
+			"access_token":  {newRequest("invalid_access_token", newProof("invalid_access_token")), `error="invalid_token"`},
+			"ath":           {newRequest(testAccessToken, newProof("other_access_token")), `error="invalid_dpop_proof", error_description="DPoP proof is invalid"`},
+			"jti":           {newRequest(testAccessToken, replayed), `error="invalid_dpop_proof"`},
+		} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.r)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("❌: %s: code != http.StatusUnauthorized: %d", name, rec.Code)
+			}
+			if actual := rec.Header().Get(httpz.HeaderWWWAuthenticate); !strings.Contains(actual, tt.expect) {
+				t.Errorf("❌: %s: WWW-Authenticate: expect(%s) != actual(%s)", name, tt.expect, actual)
+			}
+		}
+	})
+
+	t.Run("failure(WithNewDPoPHandlerOptionErrorHandler)", func(t *testing.T) {
+		t.Parallel()
+
+		var actual error
+		handler := httpz.NewDPoPHandler(dpop.NewVerifier(context.Background()),
+			func(*http.Request, string) (string, error) { return prover.JWKThumbprint(), nil },
+			httpz.WithNewDPoPHandlerOptionErrorHandler(func(_ *http.Request, err error) { actual = err }),
+		)(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) { rw.WriteHeader(http.StatusOK) }))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newRequest(testAccessToken, newProof("other_access_token")))
+		if !errors.Is(actual, dpop.ErrAccessTokenHashIsNotMatch) {
+			t.Errorf("❌: err != dpop.ErrAccessTokenHashIsNotMatch: %v", actual)
+		}
+		if expect, actual := `DPoP algs="RS256 RS384 RS512 PS256 PS384 PS512 ES256 ES384 ES512 EdDSA", error="invalid_dpop_proof", error_description="DPoP proof is invalid"`, rec.Header().Get(httpz.HeaderWWWAuthenticate); actual != expect {
+			t.Errorf("❌: WWW-Authenticate: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+
+	t.Run("success(WithNewDPoPHandlerOptionRequestURIFunc)", func(t *testing.T) {
+		t.Parallel()
+
+		proof := must.One(prover.NewProof(context.Background(), http.MethodGet, "https://api.example.com/protected", dpop.WithAccessToken(testAccessToken)))
+		handler := httpz.NewDPoPHandler(dpop.NewVerifier(context.Background()),
+			func(*http.Request, string) (string, error) { return prover.JWKThumbprint(), nil },
+			httpz.WithNewDPoPHandlerOptionRequestURIFunc(func(r *http.Request) string { return "https://api.example.com" + r.URL.Path }),
+		)(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) { rw.WriteHeader(http.StatusOK) }))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newRequest(testAccessToken, proof))
+		if rec.Code != http.StatusOK {
+			t.Errorf("❌: code != http.StatusOK: %d: %s", rec.Code, rec.Header().Get(httpz.HeaderWWWAuthenticate))
+		}
+	})
+}
@@ -1,6 +1,14 @@
 package httpz
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderAuthorization   = "Authorization"
+	HeaderWWWAuthenticate = "WWW-Authenticate"
+)
 
 type HeaderBuilder interface {
 	Add(key, value string) HeaderBuilder
@@ -41,3 +49,10 @@ func (h *headerBuilder) Merge(header http.Header) HeaderBuilder { //nolint:iretu
 func (h *headerBuilder) Build() http.Header {
 	return h.header
 }
+
+// quoteString returns quoted-string of RFC 9110, e.g. for the auth-param of WWW-Authenticate header.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9110#section-5.6.4
+func quoteString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
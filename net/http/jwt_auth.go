@@ -0,0 +1,245 @@
+package httpz
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	slicez "github.com/kunitsucom/util.go/slices"
+)
+
+// - ref. The OAuth 2.0 Authorization Framework: Bearer Token Usage https://www.rfc-editor.org/rfc/rfc6750
+
+const AuthorizationSchemeBearer = "Bearer"
+
+// Error codes of WWW-Authenticate header.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6750#section-3.1
+const (
+	BearerErrorInvalidRequest    = "invalid_request"
+	BearerErrorInvalidToken      = "invalid_token"
+	BearerErrorInsufficientScope = "insufficient_scope"
+)
+
+func ContextJWTClaimsSet(ctx context.Context) (claimsSet *jwt.ClaimsSet, ok bool) {
+	v, ok := ctx.Value(keyJWTClaimsSet).(*jwt.ClaimsSet)
+	return v, ok
+}
+
+func ContextWithJWTClaimsSet(parent context.Context, claimsSet *jwt.ClaimsSet) context.Context {
+	return context.WithValue(parent, keyJWTClaimsSet, claimsSet)
+}
+
+// BearerToken returns the access token of "Authorization: Bearer <access token>" header.
+// ok is false if the header is missing or its scheme is not Bearer.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6750#section-2.1
+func BearerToken(r *http.Request) (token string, ok bool) {
+	scheme, token, _ := strings.Cut(r.Header.Get(HeaderAuthorization), " ")
+	if !strings.EqualFold(scheme, AuthorizationSchemeBearer) {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// WriteBearerError writes the error response with WWW-Authenticate header of RFC 6750.
+// If errorCode is empty, only realm is written, as the request lacks any authentication information.
+// The status code is 400 for invalid_request, 401 for invalid_token or empty, and 403 for insufficient_scope.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6750#section-3
+func WriteBearerError(rw http.ResponseWriter, realm, errorCode, errorDescription string, scope ...string) {
+	params := make([]string, 0, 4) //nolint:mnd
+	if realm != "" {
+		params = append(params, "realm="+quoteString(realm))
+	}
+	if errorCode != "" {
+		params = append(params, "error="+quoteString(errorCode))
+	}
+	if errorDescription != "" {
+		params = append(params, "error_description="+quoteString(errorDescription))
+	}
+	if len(scope) > 0 {
+		params = append(params, "scope="+quoteString(strings.Join(scope, " ")))
+	}
+
+	challenge := AuthorizationSchemeBearer
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+	rw.Header().Set(HeaderWWWAuthenticate, challenge)
+
+	switch errorCode {
+	case BearerErrorInvalidRequest:
+		rw.WriteHeader(http.StatusBadRequest)
+	case BearerErrorInsufficientScope:
+		rw.WriteHeader(http.StatusForbidden)
+	default:
+		rw.WriteHeader(http.StatusUnauthorized)
+	}
+}
+
+type (
+	jwtAuthHandlerConfig struct {
+		verifyOptions []jwt.VerifyOption
+		realm         string
+		errorHandler  func(r *http.Request, err error)
+	}
+
+	NewJWTAuthHandlerOption interface {
+		apply(cfg *jwtAuthHandlerConfig)
+	}
+
+	newJWTAuthHandlerOptionVerifyOptions []jwt.VerifyOption
+	newJWTAuthHandlerOptionRealm         string
+	newJWTAuthHandlerOptionErrorHandler  func(r *http.Request, err error)
+)
+
+func (f newJWTAuthHandlerOptionVerifyOptions) apply(cfg *jwtAuthHandlerConfig) {
+	cfg.verifyOptions = append(cfg.verifyOptions, f...)
+}
+
+// WithNewJWTAuthHandlerOptionVerifyOptions sets jwt.VerifyOption, e.g. jwt.VerifyIssuer, jwt.VerifyAudience and jwt.VerifyAlgorithm.
+// jwt.VerifyContext is set to the request context by default.
+func WithNewJWTAuthHandlerOptionVerifyOptions(opts ...jwt.VerifyOption) NewJWTAuthHandlerOption { //nolint:ireturn
+	return newJWTAuthHandlerOptionVerifyOptions(opts)
+}
+
+func (f newJWTAuthHandlerOptionRealm) apply(cfg *jwtAuthHandlerConfig) {
+	cfg.realm = string(f)
+}
+
+// WithNewJWTAuthHandlerOptionRealm sets realm of WWW-Authenticate header.
+func WithNewJWTAuthHandlerOptionRealm(realm string) NewJWTAuthHandlerOption { //nolint:ireturn
+	return newJWTAuthHandlerOptionRealm(realm)
+}
+
+func (f newJWTAuthHandlerOptionErrorHandler) apply(cfg *jwtAuthHandlerConfig) {
+	cfg.errorHandler = f
+}
+
+// WithNewJWTAuthHandlerOptionErrorHandler sets the handler which receives the error of jwt.Verify, e.g. for logging.
+// The error is not written to the response, since it may contain the details of the token or the verification.
+func WithNewJWTAuthHandlerOptionErrorHandler(errorHandler func(r *http.Request, err error)) NewJWTAuthHandlerOption { //nolint:ireturn
+	return newJWTAuthHandlerOptionErrorHandler(errorHandler)
+}
+
+// NewJWTAuthHandler returns the middleware which verifies the JWT of "Authorization: Bearer <token>" header by keyOption.
+// The verified claims set is stored in the context, and can be taken by ContextJWTClaimsSet.
+// If the token is missing or invalid, it responds with WWW-Authenticate header of RFC 6750.
+//
+// Example:
+//
+//	// NOTE: httpz.Middlewares wraps in order, so the last one runs first.
+//	middleware := httpz.Middlewares(
+//		httpz.NewJWTScopeHandler("read:users"),
+//		httpz.NewJWTAuthHandler(
+//			jws.UseJWKSetURL(ctx, "https://accounts.example.com/.well-known/jwks.json"),
+//			httpz.WithNewJWTAuthHandlerOptionVerifyOptions(
+//				jwt.VerifyIssuer("https://accounts.example.com"),
+//				jwt.VerifyAudience("https://api.example.com"),
+//				jwt.VerifyAlgorithm(jwa.RS256),
+//			),
+//		),
+//	)
+func NewJWTAuthHandler(keyOption jws.VerificationKeyOption, opts ...NewJWTAuthHandlerOption) func(next http.Handler) http.Handler {
+	c := new(jwtAuthHandlerConfig)
+
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			token, ok := BearerToken(r)
+			if !ok {
+				WriteBearerError(rw, c.realm, "", "")
+				return
+			}
+			if token == "" {
+				WriteBearerError(rw, c.realm, BearerErrorInvalidRequest, "access token is empty")
+				return
+			}
+
+			_, claimsSet, err := jwt.Verify(keyOption, token, append([]jwt.VerifyOption{jwt.VerifyContext(r.Context())}, c.verifyOptions...)...)
+			if err != nil {
+				if c.errorHandler != nil {
+					c.errorHandler(r, err)
+				}
+				// NOTE: error_description is a fixed message, since the detail of err should not be exposed to the client,
+				// and the characters of error_description are limited.
+				//   - ref. https://www.rfc-editor.org/rfc/rfc6750#section-3
+				WriteBearerError(rw, c.realm, BearerErrorInvalidToken, "the access token is invalid")
+				return
+			}
+
+			next.ServeHTTP(rw, r.WithContext(ContextWithJWTClaimsSet(r.Context(), claimsSet)))
+		})
+	}
+}
+
+// JWTScopes returns the scopes of the claims set, from space-delimited "scope" claim of RFC 9068 or "scp" array claim.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9068#section-2.2.3
+func JWTScopes(claimsSet *jwt.ClaimsSet) []string {
+	if scope, ok := claimsSet.PrivateClaims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	return jwtStringsClaim(claimsSet, "scp")
+}
+
+// jwtStringsClaim returns the claim of string array, or string as an array of one element.
+func jwtStringsClaim(claimsSet *jwt.ClaimsSet, claimName string) []string {
+	switch v := claimsSet.PrivateClaims[claimName].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		s := make([]string, 0, len(v))
+		for _, e := range v {
+			if str, ok := e.(string); ok {
+				s = append(s, str)
+			}
+		}
+		return s
+	default:
+		return nil
+	}
+}
+
+// NewJWTScopeHandler returns the middleware which requires all of scopes in the claims set stored by NewJWTAuthHandler.
+// If the scopes are insufficient, it responds with 403 Forbidden and "insufficient_scope".
+func NewJWTScopeHandler(scopes ...string) func(next http.Handler) http.Handler {
+	return newJWTClaimHandler(JWTScopes, scopes, "scope", scopes)
+}
+
+// NewJWTRoleHandler returns the middleware which requires all of roles in claimName claim, e.g. "roles" of RFC 9068,
+// in the claims set stored by NewJWTAuthHandler.
+// If the roles are insufficient, it responds with 403 Forbidden and "insufficient_scope".
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9068#section-2.2.3.1
+func NewJWTRoleHandler(claimName string, roles ...string) func(next http.Handler) http.Handler {
+	return newJWTClaimHandler(func(claimsSet *jwt.ClaimsSet) []string { return jwtStringsClaim(claimsSet, claimName) }, roles, claimName, nil)
+}
+
+func newJWTClaimHandler(values func(claimsSet *jwt.ClaimsSet) []string, required []string, claimName string, scope []string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			claimsSet, ok := ContextJWTClaimsSet(r.Context())
+			if !ok {
+				WriteBearerError(rw, "", "", "")
+				return
+			}
+
+			actual := values(claimsSet)
+			for _, v := range required {
+				if !slicez.Contains(actual, v) {
+					WriteBearerError(rw, "", BearerErrorInsufficientScope, claimName+" is insufficient", scope...)
+					return
+				}
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
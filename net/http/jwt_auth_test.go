@@ -0,0 +1,171 @@
+package httpz_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	httpz "github.com/kunitsucom/util.go/net/http"
+)
+
+func TestContextJWTClaimsSet(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := httpz.ContextJWTClaimsSet(context.Background()); ok {
+		t.Errorf("❌: httpz.ContextJWTClaimsSet: ok == true")
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	t.Parallel()
+
+	for authorization, expect := range map[string]struct {
+		token string
+		ok    bool
+	}{
+		"":                  {"", false},
+		"Bearer test_token": {"test_token", true},
+		"bearer test_token": {"test_token", true},
+		"Bearer":            {"", true},
+		"Basic dGVzdA==":    {"", false},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(httpz.HeaderAuthorization, authorization)
+		if token, ok := httpz.BearerToken(r); token != expect.token || ok != expect.ok {
+			t.Errorf("❌: httpz.BearerToken: %q: expect(%q, %t) != actual(%q, %t)", authorization, expect.token, expect.ok, token, ok)
+		}
+	}
+}
+
+//nolint:cyclop
+func TestNewJWTAuthHandler(t *testing.T) {
+	t.Parallel()
+
+	const (
+		testIssuer   = "https://accounts.example.com"
+		testAudience = "https://api.example.com"
+	)
+	key := []byte("test_hmac_key")
+
+	newToken := func(opts ...jwt.ClaimsSetOption) string {
+		opts = append([]jwt.ClaimsSetOption{
+			jwt.WithIssuer(testIssuer),
+			jwt.WithSubject("test_subject"),
+			jwt.WithAudience(testAudience),
+			jwt.WithExpirationTime(time.Now().Add(time.Hour)),
+		}, opts...)
+		return must.One(jwt.New(jws.WithHMACKey(key), jose.NewHeader(jwa.HS256, jose.WithType("at+jwt")), jwt.NewClaimsSet(opts...)))
+	}
+
+	handler := httpz.Middlewares(
+		httpz.NewJWTRoleHandler("roles", "admin"),
+		httpz.NewJWTScopeHandler("read:users"),
+		httpz.NewJWTAuthHandler(jws.UseHMACKey(key),
+			httpz.WithNewJWTAuthHandlerOptionRealm("example"),
+			httpz.WithNewJWTAuthHandlerOptionVerifyOptions(
+				jwt.VerifyIssuer(testIssuer),
+				jwt.VerifyAudience(testAudience),
+				jwt.VerifyAlgorithm(jwa.HS256),
+			),
+		),
+	)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		claimsSet, ok := httpz.ContextJWTClaimsSet(r.Context())
+		if !ok || claimsSet.Subject != "test_subject" {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	newRequest := func(authorization string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/users", nil)
+		if authorization != "" {
+			r.Header.Set(httpz.HeaderAuthorization, authorization)
+		}
+		return r
+	}
+
+	t.Run("success(scope)", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newRequest("Bearer "+newToken(jwt.WithPrivateClaim("scope", "openid read:users"), jwt.WithPrivateClaim("roles", []string{"admin"}))))
+		if rec.Code != http.StatusOK {
+			t.Errorf("❌: code != http.StatusOK: %d: %s", rec.Code, rec.Header().Get(httpz.HeaderWWWAuthenticate))
+		}
+	})
+
+	t.Run("success(scp)", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newRequest("Bearer "+newToken(jwt.WithPrivateClaim("scp", []string{"read:users"}), jwt.WithPrivateClaim("roles", "admin"))))
+		if rec.Code != http.StatusOK {
+			t.Errorf("❌: code != http.StatusOK: %d: %s", rec.Code, rec.Header().Get(httpz.HeaderWWWAuthenticate))
+		}
+	})
+
+	t.Run("failure()", func(t *testing.T) {
+		t.Parallel()
+
+		for name, tt := range map[string]struct {
+			authorization string
+			code          int
+			expect        string
+		}{
+			"authorization": {"", http.StatusUnauthorized, `Bearer realm="example"`},
+			"basic":         {"Basic dGVzdA==", http.StatusUnauthorized, `Bearer realm="example"`},
+			"empty":         {"Bearer ", http.StatusBadRequest, `Bearer realm="example", error="invalid_request", error_description="access token is empty"`},
+			"malformed":     {"Bearer malformed", http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="the access token is invalid"`},
+			"exp":           {"Bearer " + newToken(jwt.WithExpirationTime(time.Now().Add(-time.Hour))), http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="the access token is invalid"`},
+			"iss":           {"Bearer " + newToken(jwt.WithIssuer("https://other.example.com")), http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="the access token is invalid"`},
+			"scope":         {"Bearer " + newToken(jwt.WithPrivateClaim("scope", "openid"), jwt.WithPrivateClaim("roles", []string{"admin"})), http.StatusForbidden, `Bearer error="insufficient_scope", error_description="scope is insufficient", scope="read:users"`},
+			"roles":         {"Bearer " + newToken(jwt.WithPrivateClaim("scope", "read:users"), jwt.WithPrivateClaim("roles", []string{"user"})), http.StatusForbidden, `Bearer error="insufficient_scope", error_description="roles is insufficient"`},
+		} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newRequest(tt.authorization))
+			if rec.Code != tt.code {
+				t.Errorf("❌: %s: code != %d: %d", name, tt.code, rec.Code)
+			}
+			if actual := rec.Header().Get(httpz.HeaderWWWAuthenticate); !strings.Contains(actual, tt.expect) {
+				t.Errorf("❌: %s: WWW-Authenticate: expect(%s) != actual(%s)", name, tt.expect, actual)
+			}
+		}
+	})
+
+	t.Run("failure(WithNewJWTAuthHandlerOptionErrorHandler)", func(t *testing.T) {
+		t.Parallel()
+
+		var actual error
+		rec := httptest.NewRecorder()
+		httpz.NewJWTAuthHandler(jws.UseHMACKey(key),
+			httpz.WithNewJWTAuthHandlerOptionVerifyOptions(jwt.VerifyIssuer(testIssuer)),
+			httpz.WithNewJWTAuthHandlerOptionErrorHandler(func(_ *http.Request, err error) { actual = err }),
+		)(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) { rw.WriteHeader(http.StatusOK) })).ServeHTTP(rec, newRequest("Bearer "+newToken(jwt.WithIssuer("https://other.example.com"))))
+		if !errors.Is(actual, jwt.ErrIssuerIsNotMatch) {
+			t.Errorf("❌: err != jwt.ErrIssuerIsNotMatch: %v", actual)
+		}
+		if expect, actual := `Bearer error="invalid_token", error_description="the access token is invalid"`, rec.Header().Get(httpz.HeaderWWWAuthenticate); actual != expect {
+			t.Errorf("❌: WWW-Authenticate: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+
+	t.Run("failure(NewJWTScopeHandler,without_NewJWTAuthHandler)", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		httpz.NewJWTScopeHandler("read:users")(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) { rw.WriteHeader(http.StatusOK) })).ServeHTTP(rec, newRequest(""))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("❌: code != http.StatusUnauthorized: %d", rec.Code)
+		}
+	})
+}
@@ -1,11 +1,13 @@
 package authentication_request //nolint:revive,stylecheck
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kunitsucom/util.go/openid/pkce"
 )
@@ -26,6 +28,12 @@ type option struct {
 	codeChallengeMethod string // ref. https://www.rfc-editor.org/rfc/rfc7636#:~:text=REQUIRED.%20%20Code%20challenge.-,code_challenge_method,-OPTIONAL
 
 	accessType string // ref. https://developers.google.com/identity/protocols/oauth2/web-server#request-parameter-access_type
+
+	requestObject           *requestObject           // ref. https://www.rfc-editor.org/rfc/rfc9101#section-2.1
+	requestObjectEncryption *requestObjectEncryption // ref. https://www.rfc-editor.org/rfc/rfc9101#section-4
+	requestObjectTTL        time.Duration
+
+	ctx context.Context //nolint:containedctx
 }
 
 type Option func(*option)
@@ -126,16 +134,34 @@ func WithAccessType(accessType string) Option { //nolint:revive,stylecheck
 //   - redirect_uri:           ref. https://openid.net/specs/openid-connect-core-1_0.html#:~:text=the%20Authorization%20Server.-,redirect_uri,-REQUIRED.%20Redirection%20URI
 //   - state:                  ref. https://openid.net/specs/openid-connect-core-1_0.html#:~:text=a%20native%20application.-,state,-RECOMMENDED.%20Opaque%20value
 func New(authorizationEndpoint string, scope []string, responseType, clientID, redirectURI, state string, opts ...Option) (*url.URL, error) { //nolint:revive,stylecheck
-	o := new(option)
-	for _, opt := range opts {
-		opt(o)
-	}
+	o := newOption(context.Background(), opts...)
 
 	u, err := url.Parse(authorizationEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("url.Parse: %w", err)
 	}
 
+	query, err := parameters(o, scope, responseType, clientID, redirectURI, state)
+	if err != nil {
+		return nil, err
+	}
+
+	u.RawQuery = encodeQuery(query)
+
+	return u, nil
+}
+
+func newOption(ctx context.Context, opts ...Option) *option {
+	o := &option{ctx: ctx}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// parameters returns the authorization request parameters.
+// If the request object is set, the parameters are passed by "request" parameter except the required ones.
+func parameters(o *option, scope []string, responseType, clientID, redirectURI, state string) (url.Values, error) {
 	if len(scope) == 0 {
 		return nil, fmt.Errorf("scope: %w", ErrParameterIsEmpty)
 	}
@@ -160,9 +186,30 @@ func New(authorizationEndpoint string, scope []string, responseType, clientID, r
 
 	query = optionalParameters(query, o)
 
-	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	if o.requestObject == nil {
+		if o.requestObjectEncryption != nil {
+			return nil, ErrRequestObjectSigningKeyIsNotSet
+		}
+		return query, nil
+	}
+
+	request, err := newRequestObject(o, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: response_type, client_id and scope MUST be also passed using the OAuth 2.0 request syntax.
+	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#RequestObject
+	return url.Values{
+		"scope":         {query.Get("scope")},
+		"response_type": {responseType},
+		"client_id":     {clientID},
+		"request":       {request},
+	}, nil
+}
 
-	return u, nil
+func encodeQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
 }
 
 func optionalParameters(query url.Values, optionals *option) url.Values { //nolint:cyclop
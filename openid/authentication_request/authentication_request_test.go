@@ -1,10 +1,21 @@
 package authentication_request //nolint:revive,stylecheck,testpackage
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwe"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
 	"github.com/kunitsucom/util.go/openid/pkce"
+	testingz "github.com/kunitsucom/util.go/testing"
+	timez "github.com/kunitsucom/util.go/time"
 )
 
 func TestNewAuthenticationRequest(t *testing.T) {
@@ -162,3 +173,90 @@ func TestNewAuthenticationRequest(t *testing.T) {
 		}
 	})
 }
+
+func TestNewAuthenticationRequest_RequestObject(t *testing.T) {
+	t.Parallel()
+
+	privateKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+
+		u, err := New(
+			"https://server.example.com/connect/authorize",
+			[]string{"openid", "email"},
+			"code",
+			"client_id@server.example.com",
+			"http://localhost:8022/auth/oidc",
+			"state_very_very_secure_random_string",
+			WithNonce("nonce_very_very_secure_random_string"),
+			WithMaxAge(3600),
+			WithRequestObject(jws.WithECDSAKey(privateKey), jwa.ES256, "https://server.example.com"),
+			WithRequestObjectTTL(time.Minute),
+		)
+		if err != nil {
+			t.Fatalf("❌: NewAuthenticationRequest: %v", err)
+		}
+		query := u.Query()
+		if len(query) != 4 || query.Get("client_id") != "client_id@server.example.com" || query.Get("response_type") != "code" || query.Get("scope") != "openid email" {
+			t.Errorf("❌: NewAuthenticationRequest: query=%v", query)
+		}
+
+		header, claimsSet, err := jwt.Verify(jws.UseECDSAKey(&privateKey.PublicKey), query.Get("request"),
+			jwt.VerifyType(RequestObjectType),
+			jwt.VerifyIssuer("client_id@server.example.com"),
+			jwt.VerifyAudience("https://server.example.com"),
+		)
+		if err != nil {
+			t.Fatalf("❌: jwt.Verify: err != nil: %v", err)
+		}
+		if header.Algorithm != jwa.ES256 || claimsSet.ExpirationTime-claimsSet.IssuedAt != 60 {
+			t.Errorf("❌: jwt.Verify: header=%+v claimsSet=%+v", header, claimsSet)
+		}
+		for name, expect := range map[string]any{
+			"redirect_uri": "http://localhost:8022/auth/oidc",
+			"state":        "state_very_very_secure_random_string",
+			"nonce":        "nonce_very_very_secure_random_string",
+			"max_age":      float64(3600),
+		} {
+			if actual := claimsSet.PrivateClaims[name]; actual != expect {
+				t.Errorf("❌: jwt.Verify: %s: expect(%v) != actual(%v)", name, expect, actual)
+			}
+		}
+	})
+
+	t.Run("success(clock)", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		o := newOption(timez.WithContext(context.Background(), now), WithRequestObject(jws.WithECDSAKey(privateKey), jwa.ES256, "https://server.example.com"))
+		query, err := parameters(o, []string{"openid"}, "code", "client_id", "http://localhost:8022/auth/oidc", "state")
+		if err != nil {
+			t.Fatalf("❌: parameters: err != nil: %v", err)
+		}
+		_, claimsSet, err := jwt.Verify(jws.UseECDSAKey(&privateKey.PublicKey), query.Get("request"), jwt.VerifyContext(timez.WithContext(context.Background(), now)))
+		if err != nil {
+			t.Fatalf("❌: jwt.Verify: err != nil: %v", err)
+		}
+		if claimsSet.IssuedAt != now.Unix() || claimsSet.NotBefore != now.Unix() || claimsSet.ExpirationTime != now.Add(defaultRequestObjectTTL).Unix() {
+			t.Errorf("❌: jwt.Verify: claimsSet=%+v", claimsSet)
+		}
+	})
+
+	t.Run("failure(encryption_without_signing)", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(
+			"https://server.example.com/connect/authorize",
+			[]string{"openid"},
+			"code",
+			"client_id@server.example.com",
+			"http://localhost:8022/auth/oidc",
+			"state_very_very_secure_random_string",
+			WithRequestObjectEncryption(jwe.EncryptionKeyOption{}, jwa.RSAOAEP256, jwa.A256GCM),
+		)
+		if !errors.Is(err, ErrRequestObjectSigningKeyIsNotSet) {
+			t.Errorf("❌: NewAuthenticationRequest: err != ErrRequestObjectSigningKeyIsNotSet: %v", err)
+		}
+	})
+}
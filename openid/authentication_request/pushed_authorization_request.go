@@ -0,0 +1,117 @@
+package authentication_request //nolint:revive,stylecheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/token"
+	slicez "github.com/kunitsucom/util.go/slices"
+)
+
+// - ref. OAuth 2.0 Pushed Authorization Requests (PAR) https://www.rfc-editor.org/rfc/rfc9126
+
+var (
+	ErrResponseIsNotOK                   = errors.New("openid: Authentication Request: response is not OK")
+	ErrRequestURIIsEmpty                 = errors.New("openid: Authentication Request: request_uri is empty")
+	ErrRequestObjectIsRequired           = errors.New("openid: Authentication Request: request object is required by the provider")
+	ErrPushedAuthorizationIsNotSupported = errors.New("openid: Authentication Request: pushed authorization request is not supported by the provider")
+	ErrResponseIsTooLarge                = errors.New("openid: Authentication Request: response is too large")
+)
+
+// PushedAuthorizationResponse is the successful response of the pushed authorization request endpoint.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9126#section-2.2
+//
+//nolint:tagliatelle
+type PushedAuthorizationResponse struct {
+	RequestURI string `json:"request_uri"`
+	ExpiresIn  int64  `json:"expires_in"`
+}
+
+// Push sends the authorization request parameters to "pushed_authorization_request_endpoint" with the client authentication of client,
+// and returns URL for redirect response to User-Agent, which has only "client_id" and the returned "request_uri".
+// The parameters are the same as New, and can be signed as Request Object by WithRequestObject.
+//
+// Example:
+//
+//	client, err := token.NewClient(metadata, "YOUR_CLIENT_ID", token.WithPrivateKeyJWT(jws.WithECDSAKey(privateKey), jwa.ES256))
+//	if err != nil {
+//		return err
+//	}
+//
+//	u, _, err := authentication_request.Push(ctx, client, metadata, []string{"openid"}, "code", "YOUR_CLIENT_ID", "https://example.com/callback", state,
+//		authentication_request.WithCodeChallengeForPKCE(codeVerifier, pkce.CodeChallengeMethodS256),
+//	)
+func Push(ctx context.Context, client *token.Client, metadata *discovery.ProviderMetadata, scope []string, responseType, clientID, redirectURI, state string, opts ...Option) (*url.URL, *PushedAuthorizationResponse, error) { //nolint:cyclop
+	o := newOption(ctx, opts...)
+
+	if metadata.PushedAuthorizationRequestEndpoint == "" {
+		return nil, nil, fmt.Errorf("pushed_authorization_request_endpoint: %w", ErrPushedAuthorizationIsNotSupported)
+	}
+	if metadata.RequireSignedRequestObject && o.requestObject == nil {
+		return nil, nil, ErrRequestObjectIsRequired
+	}
+
+	u, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("url.Parse: %w", err)
+	}
+
+	params, err := parameters(o, scope, responseType, clientID, redirectURI, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := client.NewRequest(ctx, metadata.PushedAuthorizationRequestEndpoint, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("(*token.Client).NewRequest: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("(*token.Client).Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	const limit = 1024 * 1024
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if len(body) > limit {
+		return nil, nil, fmt.Errorf("limit=%d: %w", limit, ErrResponseIsTooLarge)
+	}
+
+	// NOTE: The authorization server responds with 201 Created, but accepts 200 OK for the compatibility.
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		e := &token.ErrorResponse{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(body, e); err != nil || e.ErrorCode == "" {
+			const cutOffSize = 100
+			return nil, nil, fmt.Errorf("code=%d body=%q: %w", resp.StatusCode, string(slicez.CutOff(body, cutOffSize)), ErrResponseIsNotOK)
+		}
+		return nil, nil, e
+	}
+
+	par := new(PushedAuthorizationResponse)
+	if err := json.Unmarshal(body, par); err != nil {
+		return nil, nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if par.RequestURI == "" {
+		return nil, nil, ErrRequestURIIsEmpty
+	}
+
+	// NOTE: client_id is required, because request_uri is bound to the client.
+	//   - ref. https://www.rfc-editor.org/rfc/rfc9126#section-4
+	u.RawQuery = encodeQuery(url.Values{
+		"client_id":   {clientID},
+		"request_uri": {par.RequestURI},
+	})
+
+	return u, par, nil
+}
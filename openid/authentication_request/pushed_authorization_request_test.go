@@ -0,0 +1,186 @@
+package authentication_request //nolint:revive,stylecheck,testpackage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwe"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/token"
+	testingz "github.com/kunitsucom/util.go/testing"
+)
+
+const (
+	testClientID     = "test_client_id"
+	testClientSecret = "test_client_secret"
+	testRequestURI   = "urn:ietf:params:oauth:request_uri:6esc_11ACC5bwc014ltc14eY22c"
+)
+
+func newTestPushedAuthorizationRequestEndpoint(t *testing.T, handler func(w http.ResponseWriter, params url.Values)) *discovery.ProviderMetadata {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if clientID, clientSecret, ok := r.BasicAuth(); !ok || clientID != testClientID || clientSecret != testClientSecret {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		must.Must(r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r.PostForm)
+	}))
+	t.Cleanup(s.Close)
+
+	return &discovery.ProviderMetadata{
+		Issuer:                             s.URL,
+		AuthorizationEndpoint:              s.URL + "/authorize",
+		TokenEndpoint:                      s.URL + "/token",
+		PushedAuthorizationRequestEndpoint: s.URL + "/par",
+	}
+}
+
+func TestPush(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+
+		var pushed url.Values
+		metadata := newTestPushedAuthorizationRequestEndpoint(t, func(w http.ResponseWriter, params url.Values) {
+			pushed = params
+			w.WriteHeader(http.StatusCreated)
+			must.Must(json.NewEncoder(w).Encode(&PushedAuthorizationResponse{RequestURI: testRequestURI, ExpiresIn: 60}))
+		})
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretBasic(testClientSecret)))
+
+		u, par, err := Push(context.Background(), client, metadata, []string{"openid"}, "code", testClientID, "https://rp.example.com/callback", "test_state", WithNonce("test_nonce"))
+		if err != nil {
+			t.Fatalf("❌: Push: err != nil: %v", err)
+		}
+		expect := metadata.AuthorizationEndpoint + "?client_id=test_client_id&request_uri=urn%3Aietf%3Aparams%3Aoauth%3Arequest_uri%3A6esc_11ACC5bwc014ltc14eY22c"
+		if actual := u.String(); actual != expect {
+			t.Errorf("❌: Push: expect(%s) != actual(%s)", expect, actual)
+		}
+		if par.ExpiresIn != 60 {
+			t.Errorf("❌: Push: expires_in=%d", par.ExpiresIn)
+		}
+		if pushed.Get("nonce") != "test_nonce" || pushed.Get("redirect_uri") != "https://rp.example.com/callback" || pushed.Get("state") != "test_state" {
+			t.Errorf("❌: Push: pushed=%v", pushed)
+		}
+	})
+
+	t.Run("success(request_object,encrypted)", func(t *testing.T) {
+		t.Parallel()
+
+		signingKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+		encryptionKey := must.One(x509z.ParseRSAPrivateKeyPEM([]byte(testingz.TestRSAPrivateKey2048BitPEM)))
+
+		var claimsSet *jwt.ClaimsSet
+		metadata := newTestPushedAuthorizationRequestEndpoint(t, func(w http.ResponseWriter, params url.Values) {
+			var err error
+			_, claimsSet, err = jwt.VerifyNested(jwe.UseRSAKey(encryptionKey), jws.UseECDSAKey(&signingKey.PublicKey), params.Get("request"), jwt.VerifyType(RequestObjectType))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_request_object","error_description":"` + err.Error() + `"}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			must.Must(json.NewEncoder(w).Encode(&PushedAuthorizationResponse{RequestURI: testRequestURI, ExpiresIn: 60}))
+		})
+		metadata.RequireSignedRequestObject = true
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretBasic(testClientSecret)))
+
+		if _, _, err := Push(context.Background(), client, metadata, []string{"openid"}, "code", testClientID, "https://rp.example.com/callback", "test_state"); !errors.Is(err, ErrRequestObjectIsRequired) {
+			t.Fatalf("❌: Push: err != ErrRequestObjectIsRequired: %v", err)
+		}
+
+		_, _, err := Push(context.Background(), client, metadata, []string{"openid"}, "code", testClientID, "https://rp.example.com/callback", "test_state",
+			WithRequestObject(jws.WithECDSAKey(signingKey), jwa.ES256, metadata.Issuer),
+			WithRequestObjectEncryption(jwe.WithRSAKey(&encryptionKey.PublicKey), jwa.RSAOAEP256, jwa.A256GCM),
+		)
+		if err != nil {
+			t.Fatalf("❌: Push: err != nil: %v", err)
+		}
+		if claimsSet.PrivateClaims["state"] != "test_state" || claimsSet.Issuer != testClientID {
+			t.Errorf("❌: Push: claimsSet=%+v", claimsSet)
+		}
+	})
+
+	t.Run("failure(error_response)", func(t *testing.T) {
+		t.Parallel()
+
+		metadata := newTestPushedAuthorizationRequestEndpoint(t, func(w http.ResponseWriter, _ url.Values) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_request","error_description":"redirect_uri is not registered"}`))
+		})
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretBasic(testClientSecret)))
+
+		_, _, err := Push(context.Background(), client, metadata, []string{"openid"}, "code", testClientID, "https://rp.example.com/callback", "test_state")
+		var e *token.ErrorResponse
+		if !errors.As(err, &e) || e.ErrorCode != "invalid_request" || e.StatusCode != http.StatusBadRequest {
+			t.Errorf("❌: Push: err != invalid_request: %v", err)
+		}
+	})
+
+	t.Run("failure(ErrResponseIsTooLarge)", func(t *testing.T) {
+		t.Parallel()
+
+		metadata := newTestPushedAuthorizationRequestEndpoint(t, func(w http.ResponseWriter, _ url.Values) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(make([]byte, 1024*1024+1))
+		})
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretBasic(testClientSecret)))
+
+		if _, _, err := Push(context.Background(), client, metadata, []string{"openid"}, "code", testClientID, "https://rp.example.com/callback", "test_state"); !errors.Is(err, ErrResponseIsTooLarge) {
+			t.Errorf("❌: Push: err != ErrResponseIsTooLarge: %v", err)
+		}
+	})
+
+	t.Run("failure(invalid_client)", func(t *testing.T) {
+		t.Parallel()
+
+		metadata := newTestPushedAuthorizationRequestEndpoint(t, func(http.ResponseWriter, url.Values) {})
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretBasic("invalid_secret")))
+
+		_, _, err := Push(context.Background(), client, metadata, []string{"openid"}, "code", testClientID, "https://rp.example.com/callback", "test_state")
+		var e *token.ErrorResponse
+		if !errors.As(err, &e) || e.ErrorCode != "invalid_client" || e.StatusCode != http.StatusUnauthorized {
+			t.Errorf("❌: Push: err != invalid_client: %v", err)
+		}
+	})
+
+	t.Run("failure(request_uri)", func(t *testing.T) {
+		t.Parallel()
+
+		metadata := newTestPushedAuthorizationRequestEndpoint(t, func(w http.ResponseWriter, _ url.Values) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"expires_in":60}`))
+		})
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretBasic(testClientSecret)))
+
+		if _, _, err := Push(context.Background(), client, metadata, []string{"openid"}, "code", testClientID, "https://rp.example.com/callback", "test_state"); !errors.Is(err, ErrRequestURIIsEmpty) {
+			t.Errorf("❌: Push: err != ErrRequestURIIsEmpty: %v", err)
+		}
+	})
+
+	t.Run("failure(not_supported)", func(t *testing.T) {
+		t.Parallel()
+
+		metadata := &discovery.ProviderMetadata{AuthorizationEndpoint: "https://server.example.com/authorize", TokenEndpoint: "https://server.example.com/token"}
+		client := must.One(token.NewClient(metadata, testClientID))
+
+		if _, _, err := Push(context.Background(), client, metadata, []string{"openid"}, "code", testClientID, "https://rp.example.com/callback", "test_state"); !errors.Is(err, ErrPushedAuthorizationIsNotSupported) {
+			t.Errorf("❌: Push: err != ErrPushedAuthorizationIsNotSupported: %v", err)
+		}
+	})
+}
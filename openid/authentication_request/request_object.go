@@ -0,0 +1,116 @@
+package authentication_request //nolint:revive,stylecheck
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	randz "github.com/kunitsucom/util.go/crypto/rand"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwe"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	timez "github.com/kunitsucom/util.go/time"
+)
+
+// - ref. JWT-Secured Authorization Request (JAR) https://www.rfc-editor.org/rfc/rfc9101
+// - ref. Passing Request Parameters as JWTs https://openid.net/specs/openid-connect-core-1_0.html#JWTRequests
+
+// RequestObjectType is the "typ" of Request Object.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9101#section-10.8
+const RequestObjectType = "oauth-authz-req+jwt"
+
+var ErrRequestObjectSigningKeyIsNotSet = errors.New("openid: Authentication Request: request object signing key is not set")
+
+const defaultRequestObjectTTL = 5 * time.Minute
+
+type requestObject struct {
+	keyOpt   jws.SigningKeyOption
+	alg      string
+	audience string
+}
+
+type requestObjectEncryption struct {
+	keyOpt jwe.EncryptionKeyOption
+	alg    string
+	enc    string
+}
+
+// WithRequestObject signs the authorization request parameters as Request Object, and passes it by "request" parameter.
+// audience should be the Issuer Identifier of the OP.
+//
+//   - request: ref. https://www.rfc-editor.org/rfc/rfc9101#section-5.1
+func WithRequestObject(keyOpt jws.SigningKeyOption, alg, audience string) Option {
+	return func(o *option) {
+		o.requestObject = &requestObject{keyOpt: keyOpt, alg: alg, audience: audience}
+	}
+}
+
+// WithRequestObjectTTL sets the lifetime of Request Object. Default is 5 minutes.
+func WithRequestObjectTTL(ttl time.Duration) Option {
+	return func(o *option) {
+		o.requestObjectTTL = ttl
+	}
+}
+
+// WithRequestObjectEncryption encrypts the signed Request Object to the OP as Nested JWT.
+// It requires WithRequestObject.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9101#section-4
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#EncryptedRequestObject
+func WithRequestObjectEncryption(keyOpt jwe.EncryptionKeyOption, alg, enc string) Option {
+	return func(o *option) {
+		o.requestObjectEncryption = &requestObjectEncryption{keyOpt: keyOpt, alg: alg, enc: enc}
+	}
+}
+
+func newRequestObject(o *option, params url.Values) (string, error) {
+	const jtiLength = 32
+	jti, err := randz.ReadString(randz.NewReader(), jtiLength)
+	if err != nil {
+		return "", fmt.Errorf("randz.ReadString: %w", err)
+	}
+
+	ttl := o.requestObjectTTL
+	if ttl == 0 {
+		ttl = defaultRequestObjectTTL
+	}
+
+	now := timez.Now(o.ctx)
+	claimsSet := jwt.NewClaimsSet(
+		jwt.WithIssuer(params.Get("client_id")),
+		jwt.WithAudience(o.requestObject.audience),
+		jwt.WithIssuedAt(now),
+		jwt.WithNotBefore(now),
+		jwt.WithExpirationTime(now.Add(ttl)),
+		jwt.WithJWTID(jti),
+	)
+	for name := range params {
+		claimsSet.PrivateClaims[name] = params.Get(name)
+	}
+	if o.maxAge != 0 {
+		// NOTE: max_age is JSON number in Request Object.
+		claimsSet.PrivateClaims["max_age"] = o.maxAge
+	}
+
+	header := jose.NewHeader(o.requestObject.alg, jose.WithType(RequestObjectType))
+
+	if o.requestObjectEncryption == nil {
+		request, err := jwt.New(o.requestObject.keyOpt, header, claimsSet)
+		if err != nil {
+			return "", fmt.Errorf("jwt.New: %w", err)
+		}
+		return request, nil
+	}
+
+	request, err := jwt.NewNested(
+		o.requestObject.keyOpt, header, claimsSet,
+		o.requestObjectEncryption.keyOpt, jose.NewHeader(o.requestObjectEncryption.alg, jose.WithEncryptionAlgorithm(o.requestObjectEncryption.enc)),
+	)
+	if err != nil {
+		return "", fmt.Errorf("jwt.NewNested: %w", err)
+	}
+	return request, nil
+}
@@ -0,0 +1,300 @@
+package device_authorization //nolint:revive,stylecheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/token"
+	"github.com/kunitsucom/util.go/retry"
+	slicez "github.com/kunitsucom/util.go/slices"
+)
+
+// - ref. OAuth 2.0 Device Authorization Grant https://www.rfc-editor.org/rfc/rfc8628
+
+const (
+	GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+	ErrorCodeAuthorizationPending = "authorization_pending"
+	ErrorCodeSlowDown             = "slow_down"
+	ErrorCodeAccessDenied         = "access_denied"
+	ErrorCodeExpiredToken         = "expired_token"
+)
+
+var (
+	ErrDeviceAuthorizationEndpointIsEmpty = errors.New("device_authorization: device authorization endpoint is empty")
+	ErrResponseIsNotOK                    = errors.New("device_authorization: response is not OK")
+	ErrDeviceCodeIsEmpty                  = errors.New("device_authorization: device code is empty")
+	ErrDeviceCodeIsExpired                = errors.New("device_authorization: device code is expired")
+	ErrResponseIsTooLarge                 = errors.New("device_authorization: response is too large")
+)
+
+// Response is the successful response of the device authorization endpoint.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc8628#section-3.2
+//
+//nolint:tagliatelle
+type Response struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval,omitempty"`
+	// Raw is the raw response body, to read the parameters which are not defined above, e.g. "verification_url" of Google.
+	Raw json.RawMessage `json:"-"`
+}
+
+// Client is the client of the Device Authorization Grant, for the devices which lack a browser, e.g. CLI tools.
+//
+// Example:
+//
+//	tokenClient, err := token.NewClient(metadata, "YOUR_CLIENT_ID")
+//	if err != nil {
+//		return err
+//	}
+//
+//	client, err := device_authorization.NewClient(metadata, tokenClient,
+//		device_authorization.WithVerificationHandler(func(_ context.Context, resp *device_authorization.Response) error {
+//			_, err := fmt.Fprintf(os.Stderr, "Open %s and enter the code: %s\n", resp.VerificationURI, resp.UserCode)
+//			return err
+//		}),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//
+//	resp, err := client.Authorize(ctx, device_authorization.WithScope("openid", "offline_access"))
+type Client struct {
+	tokenClient                 *token.Client
+	deviceAuthorizationEndpoint string
+	verificationHandler         func(ctx context.Context, resp *Response) error
+	pollingHandler              func(ctx context.Context, r *retry.Retryer, err error)
+	defaultInterval             time.Duration
+	// intervalUnit is the unit of "interval" and the increment of "slow_down". It is the seconds except for testing.
+	intervalUnit time.Duration
+}
+
+type ClientOption func(*Client)
+
+// WithVerificationHandler sets the function which shows the End-User "verification_uri" and "user_code", before polling.
+// If it returns an error, the authorization is aborted.
+func WithVerificationHandler(f func(ctx context.Context, resp *Response) error) ClientOption {
+	return func(c *Client) {
+		c.verificationHandler = f
+	}
+}
+
+// WithPollingHandler sets the function which is called when the token request is pending, e.g. to show a progress.
+func WithPollingHandler(f func(ctx context.Context, r *retry.Retryer, err error)) ClientOption {
+	return func(c *Client) {
+		c.pollingHandler = f
+	}
+}
+
+// WithDefaultInterval sets the polling interval which is used if the response does not have "interval". Default is 5 seconds.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc8628#section-3.2
+func WithDefaultInterval(interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.defaultInterval = interval
+	}
+}
+
+// NewClient returns the client of "device_authorization_endpoint" of metadata.
+// The device authorization request and the token request are sent with the client authentication of tokenClient.
+func NewClient(metadata *discovery.ProviderMetadata, tokenClient *token.Client, opts ...ClientOption) (*Client, error) {
+	const defaultInterval = 5 * time.Second
+
+	if metadata.DeviceAuthorizationEndpoint == "" {
+		return nil, ErrDeviceAuthorizationEndpointIsEmpty
+	}
+
+	c := &Client{
+		tokenClient:                 tokenClient,
+		deviceAuthorizationEndpoint: metadata.DeviceAuthorizationEndpoint,
+		defaultInterval:             defaultInterval,
+		intervalUnit:                time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+type requestOption struct {
+	params url.Values
+}
+
+type RequestOption func(*requestOption)
+
+// WithScope sets scope.
+func WithScope(scope ...string) RequestOption {
+	return WithParameter("scope", strings.Join(scope, " "))
+}
+
+// WithParameter sets an additional parameter, e.g. "audience".
+func WithParameter(key, value string) RequestOption {
+	return func(o *requestOption) {
+		o.params.Set(key, value)
+	}
+}
+
+// Authorize requests the device code, calls the verification handler, and polls the token endpoint until the End-User completes the authorization.
+func (c *Client) Authorize(ctx context.Context, opts ...RequestOption) (*token.Response, error) {
+	resp, err := c.RequestDeviceAuthorization(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.verificationHandler != nil {
+		if err := c.verificationHandler(ctx, resp); err != nil {
+			return nil, fmt.Errorf("verificationHandler: %w", err)
+		}
+	}
+
+	return c.PollToken(ctx, resp)
+}
+
+// RequestDeviceAuthorization requests the device code and the user code.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc8628#section-3.1
+func (c *Client) RequestDeviceAuthorization(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	o := &requestOption{params: url.Values{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	req, err := c.tokenClient.NewRequest(ctx, c.deviceAuthorizationEndpoint, o.params)
+	if err != nil {
+		return nil, fmt.Errorf("(*token.Client).NewRequest: %w", err)
+	}
+
+	resp, err := c.tokenClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("(*token.Client).Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	const limit = 1024 * 1024
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if len(body) > limit {
+		return nil, fmt.Errorf("limit=%d: %w", limit, ErrResponseIsTooLarge)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		e := &token.ErrorResponse{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(body, e); err != nil || e.ErrorCode == "" {
+			const cutOffSize = 100
+			return nil, fmt.Errorf("code=%d body=%q: %w", resp.StatusCode, string(slicez.CutOff(body, cutOffSize)), ErrResponseIsNotOK)
+		}
+		return nil, e
+	}
+
+	r := &Response{Raw: body}
+	if err := json.Unmarshal(body, r); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if r.DeviceCode == "" {
+		return nil, ErrDeviceCodeIsEmpty
+	}
+
+	return r, nil
+}
+
+// PollToken polls the token endpoint with "device_code" of resp at "interval", until the End-User completes the authorization or the device code expires.
+// "authorization_pending" continues polling, and "slow_down" increases the interval by 5 seconds.
+// The other errors, e.g. "access_denied", are returned as *token.ErrorResponse.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc8628#section-3.4
+//   - ref. https://www.rfc-editor.org/rfc/rfc8628#section-3.5
+//
+//nolint:cyclop
+func (c *Client) PollToken(ctx context.Context, resp *Response) (*token.Response, error) {
+	if resp.DeviceCode == "" {
+		return nil, ErrDeviceCodeIsEmpty
+	}
+
+	interval := c.defaultInterval
+	if resp.Interval > 0 {
+		interval = time.Duration(resp.Interval) * c.intervalUnit
+	}
+
+	pollingCtx := ctx
+	if resp.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		pollingCtx, cancel = context.WithTimeout(ctx, time.Duration(resp.ExpiresIn)*c.intervalUnit)
+		defer cancel()
+	}
+
+	// NOTE: The client MUST wait at least "interval" between the requests, so the backoff is constant without jitter, except "slow_down".
+	r := retry.New(pollingCtx, retry.NewConfig(interval, time.Duration(math.MaxInt64),
+		retry.WithBackoff(func(time.Duration, int) time.Duration { return interval }),
+		retry.WithJitter(func(d time.Duration) time.Duration { return d }),
+	))
+
+	params := url.Values{
+		"grant_type":  {GrantTypeDeviceCode},
+		"device_code": {resp.DeviceCode},
+	}
+
+	for r.Retry() {
+		if r.Retries() == 0 {
+			// NOTE: wait "interval" before the first request.
+			continue
+		}
+
+		tokenResponse, err := c.tokenClient.RequestToken(pollingCtx, params)
+		if err == nil {
+			return tokenResponse, nil
+		}
+
+		var e *token.ErrorResponse
+		if !errors.As(err, &e) {
+			if ctx.Err() == nil && pollingCtx.Err() != nil {
+				return nil, fmt.Errorf("expires_in=%d: %w: %w", resp.ExpiresIn, ErrDeviceCodeIsExpired, err)
+			}
+			return nil, fmt.Errorf("(*token.Client).RequestToken: %w", err)
+		}
+		switch e.ErrorCode {
+		case ErrorCodeAuthorizationPending:
+		case ErrorCodeSlowDown:
+			const slowDownIncrement = 5
+			increment := slowDownIncrement * c.intervalUnit
+			interval += increment
+			// NOTE: r has already computed the next wait with the old interval, so wait for the increment in addition,
+			// in order to apply the new interval to this and all subsequent requests.
+			//   - ref. https://www.rfc-editor.org/rfc/rfc8628#section-3.5
+			select {
+			case <-pollingCtx.Done():
+			case <-time.After(increment):
+			}
+		case ErrorCodeExpiredToken:
+			return nil, fmt.Errorf("%w: %w", ErrDeviceCodeIsExpired, e)
+		default:
+			return nil, fmt.Errorf("(*token.Client).RequestToken: %w", e)
+		}
+
+		if c.pollingHandler != nil {
+			c.pollingHandler(pollingCtx, r, e)
+		}
+	}
+
+	if ctx.Err() == nil && errors.Is(r.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("expires_in=%d: %w", resp.ExpiresIn, ErrDeviceCodeIsExpired)
+	}
+	return nil, fmt.Errorf("(*retry.Retryer).Retry: %w", r.Err())
+}
@@ -0,0 +1,240 @@
+package device_authorization //nolint:revive,stylecheck,testpackage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/token"
+	"github.com/kunitsucom/util.go/retry"
+)
+
+const (
+	testClientID   = "test_client_id"
+	testDeviceCode = "test_device_code"
+)
+
+type testProvider struct {
+	mu             sync.Mutex
+	metadata       *discovery.ProviderMetadata
+	tokenResponses []string // error codes, or "" for success
+	requests       int
+	requestedAt    []time.Time
+}
+
+func newTestProvider(t *testing.T, expiresIn int64, tokenResponses ...string) *testProvider {
+	t.Helper()
+
+	p := &testProvider{tokenResponses: tokenResponses}
+	mux := http.NewServeMux()
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("client_id") != testClientID {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		must.Must(json.NewEncoder(w).Encode(&Response{
+			DeviceCode:      testDeviceCode,
+			UserCode:        "WDJB-MJHT",
+			VerificationURI: s.URL + "/device",
+			ExpiresIn:       expiresIn,
+			Interval:        1,
+		}))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostFormValue("grant_type") != GrantTypeDeviceCode || r.PostFormValue("device_code") != testDeviceCode {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		p.requestedAt = append(p.requestedAt, time.Now())
+		errorCode := ErrorCodeAuthorizationPending
+		if p.requests < len(p.tokenResponses) {
+			errorCode = p.tokenResponses[p.requests]
+		}
+		p.requests++
+		if errorCode != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			must.Must(json.NewEncoder(w).Encode(&token.ErrorResponse{ErrorCode: errorCode}))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"test_access_token","token_type":"Bearer","expires_in":3600}`))
+	})
+
+	p.metadata = &discovery.ProviderMetadata{
+		Issuer:                      s.URL,
+		TokenEndpoint:               s.URL + "/token",
+		DeviceAuthorizationEndpoint: s.URL + "/device",
+	}
+	return p
+}
+
+func (p *testProvider) client(t *testing.T, opts ...ClientOption) *Client {
+	t.Helper()
+
+	c := must.One(NewClient(p.metadata, must.One(token.NewClient(p.metadata, testClientID)), opts...))
+	c.intervalUnit = time.Millisecond
+	return c
+}
+
+func TestClient_Authorize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(authorization_pending,slow_down)", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestProvider(t, 600, ErrorCodeAuthorizationPending, ErrorCodeSlowDown, "")
+		var verificationURI string
+		var pending []string
+		c := p.client(t,
+			WithVerificationHandler(func(_ context.Context, resp *Response) error {
+				verificationURI = resp.VerificationURI
+				return nil
+			}),
+			WithPollingHandler(func(_ context.Context, _ *retry.Retryer, err error) {
+				var e *token.ErrorResponse
+				if errors.As(err, &e) {
+					pending = append(pending, e.ErrorCode)
+				}
+			}),
+		)
+
+		resp, err := c.Authorize(context.Background(), WithScope("openid", "offline_access"))
+		if err != nil {
+			t.Fatalf("❌: (*Client).Authorize: err != nil: %v", err)
+		}
+		if resp.AccessToken != "test_access_token" {
+			t.Errorf("❌: (*Client).Authorize: access_token=%s", resp.AccessToken)
+		}
+		if verificationURI != p.metadata.DeviceAuthorizationEndpoint {
+			t.Errorf("❌: verificationHandler: verification_uri=%s", verificationURI)
+		}
+		if len(pending) != 2 || pending[0] != ErrorCodeAuthorizationPending || pending[1] != ErrorCodeSlowDown {
+			t.Errorf("❌: pollingHandler: pending=%v", pending)
+		}
+	})
+
+	t.Run("success(slow_down,interval)", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestProvider(t, 600, ErrorCodeSlowDown, ErrorCodeAuthorizationPending, "")
+		c := p.client(t)
+		c.intervalUnit = 10 * time.Millisecond
+
+		if _, err := c.Authorize(context.Background()); err != nil {
+			t.Fatalf("❌: (*Client).Authorize: err != nil: %v", err)
+		}
+
+		// NOTE: "interval" is 1 unit, and "slow_down" increases it by 5 units for this and all subsequent requests.
+		const expected = 6 * 10 * time.Millisecond
+		if len(p.requestedAt) != 3 {
+			t.Fatalf("❌: requests: %d", len(p.requestedAt))
+		}
+		for i := 1; i < len(p.requestedAt); i++ {
+			if actual := p.requestedAt[i].Sub(p.requestedAt[i-1]); actual < expected {
+				t.Errorf("❌: interval of request %d: expected(>=%s) != actual(%s)", i, expected, actual)
+			}
+		}
+	})
+
+	t.Run("failure(access_denied)", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestProvider(t, 600, ErrorCodeAuthorizationPending, ErrorCodeAccessDenied)
+		_, err := p.client(t).Authorize(context.Background())
+		var e *token.ErrorResponse
+		if !errors.As(err, &e) || e.ErrorCode != ErrorCodeAccessDenied {
+			t.Errorf("❌: (*Client).Authorize: err != access_denied: %v", err)
+		}
+	})
+
+	t.Run("failure(expired_token)", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestProvider(t, 600, ErrorCodeExpiredToken)
+		if _, err := p.client(t).Authorize(context.Background()); !errors.Is(err, ErrDeviceCodeIsExpired) {
+			t.Errorf("❌: (*Client).Authorize: err != ErrDeviceCodeIsExpired: %v", err)
+		}
+	})
+
+	t.Run("failure(expires_in)", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestProvider(t, 10)
+		if _, err := p.client(t).Authorize(context.Background()); !errors.Is(err, ErrDeviceCodeIsExpired) {
+			t.Errorf("❌: (*Client).Authorize: err != ErrDeviceCodeIsExpired: %v", err)
+		}
+	})
+
+	t.Run("failure(context.Canceled)", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		p := newTestProvider(t, 600)
+		_, err := p.client(t, WithPollingHandler(func(context.Context, *retry.Retryer, error) { cancel() })).Authorize(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("❌: (*Client).Authorize: err != context.Canceled: %v", err)
+		}
+	})
+
+	t.Run("failure(verificationHandler)", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestProvider(t, 600)
+		errAbort := errors.New("abort") //nolint:goerr113
+		_, err := p.client(t, WithVerificationHandler(func(context.Context, *Response) error { return errAbort })).Authorize(context.Background())
+		if !errors.Is(err, errAbort) {
+			t.Errorf("❌: (*Client).Authorize: err != errAbort: %v", err)
+		}
+	})
+
+	t.Run("failure(invalid_client)", func(t *testing.T) {
+		t.Parallel()
+
+		p := newTestProvider(t, 600)
+		c := must.One(NewClient(p.metadata, must.One(token.NewClient(p.metadata, "invalid_client_id"))))
+		_, err := c.Authorize(context.Background())
+		var e *token.ErrorResponse
+		if !errors.As(err, &e) || e.ErrorCode != "invalid_client" {
+			t.Errorf("❌: (*Client).Authorize: err != invalid_client: %v", err)
+		}
+	})
+
+	t.Run("failure(ErrResponseIsTooLarge)", func(t *testing.T) {
+		t.Parallel()
+
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(make([]byte, 1024*1024+1))
+		}))
+		t.Cleanup(s.Close)
+		metadata := &discovery.ProviderMetadata{Issuer: s.URL, TokenEndpoint: s.URL + "/token", DeviceAuthorizationEndpoint: s.URL + "/device"}
+		c := must.One(NewClient(metadata, must.One(token.NewClient(metadata, testClientID))))
+		if _, err := c.Authorize(context.Background()); !errors.Is(err, ErrResponseIsTooLarge) {
+			t.Errorf("❌: (*Client).Authorize: err != ErrResponseIsTooLarge: %v", err)
+		}
+	})
+
+	t.Run("failure(device_authorization_endpoint)", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := NewClient(&discovery.ProviderMetadata{}, nil); !errors.Is(err, ErrDeviceAuthorizationEndpointIsEmpty) {
+			t.Errorf("❌: NewClient: err != ErrDeviceAuthorizationEndpointIsEmpty: %v", err)
+		}
+	})
+}
@@ -68,6 +68,29 @@ type (
 		RequireRequestURIRegistration              bool     `json:"require_request_uri_registration,omitempty"`
 		OPPolicyURI                                string   `json:"op_policy_uri,omitempty"`
 		OPTosURI                                   string   `json:"op_tos_uri,omitempty"`
+
+		// https://www.rfc-editor.org/rfc/rfc8414#section-2
+		CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+
+		// https://www.rfc-editor.org/rfc/rfc8628#section-4
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+
+		// https://www.rfc-editor.org/rfc/rfc9126#section-5
+		PushedAuthorizationRequestEndpoint string `json:"pushed_authorization_request_endpoint,omitempty"`
+		RequirePushedAuthorizationRequests bool   `json:"require_pushed_authorization_requests,omitempty"`
+
+		// https://www.rfc-editor.org/rfc/rfc9101#section-10.5
+		RequireSignedRequestObject bool `json:"require_signed_request_object,omitempty"`
+
+		// https://www.rfc-editor.org/rfc/rfc9449#section-5.1
+		DPoPSigningAlgValuesSupported []string `json:"dpop_signing_alg_values_supported,omitempty"`
+
+		// https://openid.net/specs/openid-connect-rpinitiated-1_0.html#OPMetadata
+		EndSessionEndpoint string `json:"end_session_endpoint,omitempty"`
+
+		// https://openid.net/specs/openid-connect-backchannel-1_0.html#BCSupport
+		BackchannelLogoutSupported        bool `json:"backchannel_logout_supported,omitempty"`
+		BackchannelLogoutSessionSupported bool `json:"backchannel_logout_session_supported,omitempty"`
 	}
 )
 
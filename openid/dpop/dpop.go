@@ -0,0 +1,428 @@
+package dpop
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	randz "github.com/kunitsucom/util.go/crypto/rand"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	slicez "github.com/kunitsucom/util.go/slices"
+	syncz "github.com/kunitsucom/util.go/sync"
+	timez "github.com/kunitsucom/util.go/time"
+)
+
+// - ref. OAuth 2.0 Demonstrating Proof of Possession (DPoP) https://www.rfc-editor.org/rfc/rfc9449
+
+const (
+	// HeaderDPoP is the HTTP header to send DPoP proof.
+	HeaderDPoP = "DPoP"
+	// AuthorizationScheme is the scheme of Authorization header for DPoP-bound access tokens.
+	AuthorizationScheme = "DPoP"
+	// ProofType is the "typ" of DPoP proof.
+	ProofType = "dpop+jwt"
+	// TokenType is the "token_type" of DPoP-bound access tokens in the token response.
+	TokenType = "DPoP"
+)
+
+var (
+	ErrKeyIsNotSupported         = errors.New("dpop: key is not supported")
+	ErrAlgorithmIsNotAsymmetric  = errors.New("dpop: algorithm is not asymmetric")
+	ErrProofIsEmpty              = errors.New("dpop: proof is empty")
+	ErrJSONWebKeyIsPrivate       = errors.New("dpop: jwk contains private key")
+	ErrHTTPMethodIsNotMatch      = errors.New("dpop: htm is not match")
+	ErrHTTPURIIsNotMatch         = errors.New("dpop: htu is not match")
+	ErrAccessTokenHashIsNotMatch = errors.New("dpop: ath is not match")
+	ErrJWKThumbprintIsNotMatch   = errors.New("dpop: jwk thumbprint is not match")
+	ErrNonceIsNotMatch           = errors.New("dpop: nonce is not match")
+	ErrProofIsReplayed           = errors.New("dpop: proof is replayed")
+	ErrConfirmationIsMissing     = errors.New("dpop: cnf.jkt is missing")
+)
+
+// SupportedAlgorithms is the asymmetric algorithms which can be used for DPoP proof.
+// "none" and the symmetric algorithms MUST NOT be used.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-4.2
+func SupportedAlgorithms() []string {
+	return []string{
+		jwa.RS256, jwa.RS384, jwa.RS512,
+		jwa.PS256, jwa.PS384, jwa.PS512,
+		jwa.ES256, jwa.ES384, jwa.ES512,
+		jwa.EdDSA,
+	}
+}
+
+// Proof is the claims of DPoP proof.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-4.2
+//
+//nolint:tagliatelle
+type Proof struct {
+	jwt.ClaimsSet
+
+	// HTTPMethod: "htm"
+	HTTPMethod string `json:"htm"`
+	// HTTPURI: "htu"
+	HTTPURI string `json:"htu"`
+	// AccessTokenHash: "ath"
+	AccessTokenHash string `json:"ath,omitempty"`
+	// Nonce: "nonce"
+	Nonce string `json:"nonce,omitempty"`
+
+	// JSONWebKey is the public key of "jwk" header, which is set by Verifier.
+	JSONWebKey *jwk.JSONWebKey `json:"-"`
+	// JWKThumbprint is the SHA-256 JWK Thumbprint of JSONWebKey, which is set by Verifier.
+	// It should be equal to "jkt" of "cnf" claim of the access token.
+	JWKThumbprint string `json:"-"`
+}
+
+var _ jwt.Claims = (*Proof)(nil)
+
+// MarshalJSON returns the JSON encoding of c, which includes "htm", "htu", "ath" and "nonce" as well as the claims of the embedded jwt.ClaimsSet.
+// It is required because (*jwt.ClaimsSet).MarshalJSON is promoted to Proof, which ignores the other fields.
+func (c *Proof) MarshalJSON() ([]byte, error) {
+	return jwt.MarshalClaims(c) //nolint:wrapcheck
+}
+
+// UnmarshalJSON parses the JSON-encoded data into c, including "htm", "htu", "ath" and "nonce".
+func (c *Proof) UnmarshalJSON(data []byte) error {
+	return jwt.UnmarshalClaims(data, c) //nolint:wrapcheck
+}
+
+// AccessTokenHash returns "ath", the base64url-encoded SHA-256 hash of the access token.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-4.2
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JWKThumbprintFromClaimsSet returns "jkt" of "cnf" claim of the DPoP-bound JWT access token.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-6.1
+func JWKThumbprintFromClaimsSet(claimsSet *jwt.ClaimsSet) (string, error) {
+	var cnf map[string]any
+	if err := claimsSet.GetPrivateClaim("cnf", &cnf); err != nil {
+		return "", fmt.Errorf("(*jwt.ClaimsSet).GetPrivateClaim: %w: %w", ErrConfirmationIsMissing, err)
+	}
+	jkt, ok := cnf["jkt"].(string)
+	if !ok || jkt == "" {
+		return "", ErrConfirmationIsMissing
+	}
+	return jkt, nil
+}
+
+// normalizeHTTPURI returns htu without query and fragment, with lower-case scheme and host.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-4.3
+func normalizeHTTPURI(htu string) (string, error) {
+	u, err := url.Parse(htu)
+	if err != nil {
+		return "", fmt.Errorf("url.Parse: %w", err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.RawQuery, u.ForceQuery, u.Fragment, u.RawFragment = "", false, "", ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String(), nil
+}
+
+// Prover creates DPoP proofs with the private key of the client.
+//
+// Example:
+//
+//	prover, err := dpop.NewProver(privateKey, jwa.ES256)
+//	if err != nil {
+//		return err
+//	}
+//
+//	proof, err := prover.NewProof(ctx, http.MethodGet, "https://resource.example.com/protected", dpop.WithAccessToken(accessToken))
+//	if err != nil {
+//		return err
+//	}
+//
+//	req.Header.Set("Authorization", "DPoP "+accessToken)
+//	req.Header.Set(dpop.HeaderDPoP, proof)
+type Prover struct {
+	keyOpt        jws.SigningKeyOption
+	alg           string
+	jsonWebKey    *jwk.JSONWebKey
+	jwkThumbprint string
+}
+
+// NewProver returns Prover which signs DPoP proofs with privateKey by alg.
+// privateKey is *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey.
+func NewProver(privateKey crypto.Signer, alg string) (*Prover, error) {
+	if !slicez.Contains(SupportedAlgorithms(), alg) {
+		return nil, fmt.Errorf("alg=%s: %w", alg, ErrAlgorithmIsNotAsymmetric)
+	}
+
+	jsonWebKey := new(jwk.JSONWebKey)
+	switch pub := privateKey.Public().(type) {
+	case *rsa.PublicKey:
+		jsonWebKey = jsonWebKey.EncodeRSAPublicKey(pub)
+	case *ecdsa.PublicKey:
+		jsonWebKey = jsonWebKey.EncodeECDSAPublicKey(pub)
+	case ed25519.PublicKey:
+		jsonWebKey = jsonWebKey.EncodeEd25519PublicKey(pub)
+	default:
+		return nil, fmt.Errorf("key=%T: %w", pub, ErrKeyIsNotSupported)
+	}
+
+	jwkThumbprint, err := jsonWebKey.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("(*jwk.JSONWebKey).Thumbprint: %w", err)
+	}
+
+	return &Prover{
+		keyOpt:        jws.WithKey(privateKey),
+		alg:           alg,
+		jsonWebKey:    jsonWebKey,
+		jwkThumbprint: jwkThumbprint,
+	}, nil
+}
+
+// JWKThumbprint returns the SHA-256 JWK Thumbprint of the public key, e.g. for "dpop_jkt" of the authorization request.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-10
+func (p *Prover) JWKThumbprint() string {
+	return p.jwkThumbprint
+}
+
+type proofOption struct {
+	accessToken string
+	nonce       string
+}
+
+type ProofOption func(*proofOption)
+
+// WithAccessToken sets "ath" of the access token, which is required to access the protected resources.
+func WithAccessToken(accessToken string) ProofOption {
+	return func(o *proofOption) {
+		o.accessToken = accessToken
+	}
+}
+
+// WithNonce sets "nonce" provided by the server in "DPoP-Nonce" header.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-8
+func WithNonce(nonce string) ProofOption {
+	return func(o *proofOption) {
+		o.nonce = nonce
+	}
+}
+
+// NewProof returns DPoP proof JWT for the HTTP request of htm and htu.
+// The query and fragment of htu are removed.
+func (p *Prover) NewProof(ctx context.Context, htm, htu string, opts ...ProofOption) (string, error) {
+	o := new(proofOption)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	normalized, err := normalizeHTTPURI(htu)
+	if err != nil {
+		return "", err
+	}
+
+	const jtiLength = 32
+	jti, err := randz.ReadString(randz.NewReader(), jtiLength)
+	if err != nil {
+		return "", fmt.Errorf("randz.ReadString: %w", err)
+	}
+
+	proof := &Proof{
+		ClaimsSet: *jwt.NewClaimsSet(
+			jwt.WithJWTID(jti),
+			jwt.WithIssuedAt(timez.Now(ctx)),
+		),
+		HTTPMethod: htm,
+		HTTPURI:    normalized,
+		Nonce:      o.nonce,
+	}
+	if o.accessToken != "" {
+		proof.AccessTokenHash = AccessTokenHash(o.accessToken)
+	}
+
+	token, err := jwt.NewWithClaims(p.keyOpt, jose.NewHeader(p.alg, jose.WithType(ProofType), jose.WithJSONWebKey(p.jsonWebKey)), proof)
+	if err != nil {
+		return "", fmt.Errorf("jwt.NewWithClaims: %w", err)
+	}
+
+	return token, nil
+}
+
+// Verifier verifies DPoP proofs on the authorization server or the resource server.
+type Verifier struct {
+	algorithms []string
+	maxAge     time.Duration
+	leeway     time.Duration
+	jtiCache   syncz.Map[struct{}]
+}
+
+type VerifierOption func(*Verifier)
+
+// WithAlgorithms sets the allow-list of "alg". Default is SupportedAlgorithms. The symmetric algorithms are ignored.
+func WithAlgorithms(algorithms ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.algorithms = slicez.Filter(algorithms, func(_ int, alg string) bool { return slicez.Contains(SupportedAlgorithms(), alg) })
+	}
+}
+
+// WithMaxAge sets the acceptable age of DPoP proof since "iat". Default is 1 minute.
+func WithMaxAge(maxAge time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.maxAge = maxAge
+	}
+}
+
+// WithLeeway sets the leeway for clock skew in verifying "iat".
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
+
+// WithJTICache sets the cache of "jti" for replay protection, e.g. shared between the server instances.
+// The TTL of the cache should be longer than max age and leeway.
+func WithJTICache(cache syncz.Map[struct{}]) VerifierOption {
+	return func(v *Verifier) {
+		v.jtiCache = cache
+	}
+}
+
+func NewVerifier(ctx context.Context, opts ...VerifierOption) *Verifier {
+	const defaultMaxAge = 1 * time.Minute
+
+	v := &Verifier{
+		algorithms: SupportedAlgorithms(),
+		maxAge:     defaultMaxAge,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if v.jtiCache == nil {
+		// NOTE: The proof is acceptable between iat-leeway and iat+maxAge+leeway.
+		v.jtiCache = syncz.NewMap[struct{}](ctx, syncz.WithNewMapOptionTTL(v.maxAge+2*v.leeway))
+	}
+
+	return v
+}
+
+// Algorithms returns the allow-list of "alg", e.g. for "algs" of WWW-Authenticate header or "dpop_signing_alg_values_supported".
+func (v *Verifier) Algorithms() []string {
+	return append([]string(nil), v.algorithms...)
+}
+
+type verifyOption struct {
+	accessToken   string
+	jwkThumbprint string
+	nonce         string
+}
+
+type VerifyOption func(*verifyOption)
+
+// VerifyAccessToken verifies "ath" of DPoP proof against accessToken.
+func VerifyAccessToken(accessToken string) VerifyOption {
+	return func(o *verifyOption) {
+		o.accessToken = accessToken
+	}
+}
+
+// VerifyJWKThumbprint verifies the public key of DPoP proof against "jkt" of "cnf" claim of the access token.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-7.1
+func VerifyJWKThumbprint(jkt string) VerifyOption {
+	return func(o *verifyOption) {
+		o.jwkThumbprint = jkt
+	}
+}
+
+// VerifyNonce verifies "nonce" of DPoP proof against nonce provided by the server.
+func VerifyNonce(nonce string) VerifyOption {
+	return func(o *verifyOption) {
+		o.nonce = nonce
+	}
+}
+
+// Verify verifies DPoP proof for the HTTP request of htm and htu.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc9449#section-4.3
+//
+//nolint:cyclop
+func (v *Verifier) Verify(ctx context.Context, proof, htm, htu string, opts ...VerifyOption) (*Proof, error) {
+	o := new(verifyOption)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if proof == "" {
+		return nil, ErrProofIsEmpty
+	}
+
+	claims := new(Proof)
+	header, err := jwt.VerifyInto(jws.UseJSONWebKey(), proof, claims,
+		jwt.VerifyContext(ctx),
+		jwt.VerifyType(ProofType),
+		jwt.VerifyAlgorithm(v.algorithms...),
+		jwt.VerifyRequiredClaims("jti"),
+		jwt.VerifyMaxAge(v.maxAge),
+		jwt.VerifyLeeway(v.leeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("jwt.VerifyInto: %w", err)
+	}
+	if header.JSONWebKey.IsPrivate() {
+		return nil, ErrJSONWebKeyIsPrivate
+	}
+
+	if claims.HTTPMethod != htm {
+		return nil, fmt.Errorf("htm=%s method=%s: %w", claims.HTTPMethod, htm, ErrHTTPMethodIsNotMatch)
+	}
+	expectHTU, err := normalizeHTTPURI(htu)
+	if err != nil {
+		return nil, err
+	}
+	if actualHTU, err := normalizeHTTPURI(claims.HTTPURI); err != nil || actualHTU != expectHTU {
+		return nil, fmt.Errorf("htu=%s uri=%s: %w", claims.HTTPURI, expectHTU, ErrHTTPURIIsNotMatch)
+	}
+
+	if o.accessToken != "" && claims.AccessTokenHash != AccessTokenHash(o.accessToken) {
+		return nil, ErrAccessTokenHashIsNotMatch
+	}
+	if o.nonce != "" && claims.Nonce != o.nonce {
+		return nil, ErrNonceIsNotMatch
+	}
+
+	jkt, err := header.JSONWebKey.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("(*jwk.JSONWebKey).Thumbprint: %w", err)
+	}
+	if o.jwkThumbprint != "" && jkt != o.jwkThumbprint {
+		return nil, ErrJWKThumbprintIsNotMatch
+	}
+	claims.JSONWebKey, claims.JWKThumbprint = header.JSONWebKey, jkt
+
+	if _, loaded := v.jtiCache.LoadOrStore(jkt+" "+claims.JWTID, struct{}{}); loaded {
+		return nil, fmt.Errorf("jti=%s: %w", claims.JWTID, ErrProofIsReplayed)
+	}
+
+	return claims, nil
+}
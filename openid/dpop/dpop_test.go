@@ -0,0 +1,229 @@
+package dpop_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/dpop"
+	testingz "github.com/kunitsucom/util.go/testing"
+	timez "github.com/kunitsucom/util.go/time"
+)
+
+const (
+	testHTU         = "https://resource.example.com/protected"
+	testAccessToken = "test_access_token"
+)
+
+func TestAccessTokenHash(t *testing.T) {
+	t.Parallel()
+
+	// - ref. https://www.rfc-editor.org/rfc/rfc9449#section-7.1
+	const expect = "fUHyO2r2Z3DZ53EsNrWBb0xWXoaNy59IiKCAqksmQEo"
+	if actual := dpop.AccessTokenHash("Kz~8mXK1EalYznwH-LC-1fBAo.4Ljp~zsPE_NeO.gxU"); expect != actual {
+		t.Errorf("❌: dpop.AccessTokenHash: expect(%s) != actual(%s)", expect, actual)
+	}
+}
+
+func TestProof_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(round_trip)", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &dpop.Proof{
+			ClaimsSet:       *jwt.NewClaimsSet(jwt.WithJWTID("test_jti")),
+			HTTPMethod:      http.MethodGet,
+			HTTPURI:         testHTU,
+			AccessTokenHash: dpop.AccessTokenHash(testAccessToken),
+			Nonce:           "test_nonce",
+		}
+		b, err := json.Marshal(expected)
+		if err != nil {
+			t.Fatalf("❌: json.Marshal: err != nil: %v", err)
+		}
+		actual := new(dpop.Proof)
+		if err := json.Unmarshal(b, actual); err != nil {
+			t.Fatalf("❌: json.Unmarshal: err != nil: %v", err)
+		}
+		if actual.JWTID != expected.JWTID || actual.HTTPMethod != expected.HTTPMethod || actual.HTTPURI != expected.HTTPURI ||
+			actual.AccessTokenHash != expected.AccessTokenHash || actual.Nonce != expected.Nonce {
+			t.Errorf("❌: expected(%+v) != actual(%+v): %s", expected, actual, b)
+		}
+	})
+}
+
+func TestJWKThumbprintFromClaimsSet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+
+		claimsSet := jwt.NewClaimsSet()
+		claimsSet.SetPrivateClaim("cnf", map[string]any{"jkt": "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I"})
+		actual, err := dpop.JWKThumbprintFromClaimsSet(claimsSet)
+		if err != nil {
+			t.Fatalf("❌: dpop.JWKThumbprintFromClaimsSet: err != nil: %v", err)
+		}
+		if expect := "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I"; expect != actual {
+			t.Errorf("❌: dpop.JWKThumbprintFromClaimsSet: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+
+	t.Run("failure(cnf)", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := dpop.JWKThumbprintFromClaimsSet(jwt.NewClaimsSet()); !errors.Is(err, dpop.ErrConfirmationIsMissing) {
+			t.Errorf("❌: dpop.JWKThumbprintFromClaimsSet: err != dpop.ErrConfirmationIsMissing: %v", err)
+		}
+	})
+}
+
+func TestNewProver(t *testing.T) {
+	t.Parallel()
+
+	t.Run("failure(alg)", func(t *testing.T) {
+		t.Parallel()
+
+		key := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+		if _, err := dpop.NewProver(key, jwa.HS256); !errors.Is(err, dpop.ErrAlgorithmIsNotAsymmetric) {
+			t.Errorf("❌: dpop.NewProver: err != dpop.ErrAlgorithmIsNotAsymmetric: %v", err)
+		}
+	})
+}
+
+//nolint:gocognit,cyclop
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	ecdsaKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	prover := must.One(dpop.NewProver(ecdsaKey, jwa.ES256))
+
+	t.Run("success(ES256,RS256,EdDSA)", func(t *testing.T) {
+		t.Parallel()
+
+		rsaKey := must.One(x509z.ParseRSAPrivateKeyPEM([]byte(testingz.TestRSAPrivateKey2048BitPEM)))
+		ed25519Key := must.One(x509z.ParseEd25519PrivateKeyPEM([]byte(testingz.TestEd25519PrivateKeyPEM)))
+		for _, p := range []*dpop.Prover{
+			prover,
+			must.One(dpop.NewProver(rsaKey, jwa.RS256)),
+			must.One(dpop.NewProver(ed25519Key, jwa.EdDSA)),
+		} {
+			verifier := dpop.NewVerifier(context.Background())
+			proof := must.One(p.NewProof(context.Background(), http.MethodGet, testHTU+"?q=1#fragment", dpop.WithAccessToken(testAccessToken), dpop.WithNonce("test_nonce")))
+			actual, err := verifier.Verify(context.Background(), proof, http.MethodGet, "HTTPS://Resource.Example.com/protected?q=2",
+				dpop.VerifyAccessToken(testAccessToken),
+				dpop.VerifyJWKThumbprint(p.JWKThumbprint()),
+				dpop.VerifyNonce("test_nonce"),
+			)
+			if err != nil {
+				t.Fatalf("❌: (*dpop.Verifier).Verify: err != nil: %v", err)
+			}
+			if actual.HTTPURI != testHTU || actual.JWKThumbprint != p.JWKThumbprint() || actual.JSONWebKey.IsPrivate() {
+				t.Errorf("❌: (*dpop.Verifier).Verify: proof=%+v", actual)
+			}
+		}
+	})
+
+	t.Run("failure(replayed)", func(t *testing.T) {
+		t.Parallel()
+
+		verifier := dpop.NewVerifier(context.Background())
+		proof := must.One(prover.NewProof(context.Background(), http.MethodPost, testHTU))
+		if _, err := verifier.Verify(context.Background(), proof, http.MethodPost, testHTU); err != nil {
+			t.Fatalf("❌: (*dpop.Verifier).Verify: err != nil: %v", err)
+		}
+		if _, err := verifier.Verify(context.Background(), proof, http.MethodPost, testHTU); !errors.Is(err, dpop.ErrProofIsReplayed) {
+			t.Errorf("❌: (*dpop.Verifier).Verify: err != dpop.ErrProofIsReplayed: %v", err)
+		}
+	})
+
+	t.Run("failure(mismatch)", func(t *testing.T) {
+		t.Parallel()
+
+		verifier := dpop.NewVerifier(context.Background())
+		for _, tt := range []struct {
+			htm, htu string
+			opts     []dpop.VerifyOption
+			expect   error
+		}{
+			{http.MethodPost, testHTU, nil, dpop.ErrHTTPMethodIsNotMatch},
+			{http.MethodGet, "https://resource.example.com/other", nil, dpop.ErrHTTPURIIsNotMatch},
+			{http.MethodGet, testHTU, []dpop.VerifyOption{dpop.VerifyAccessToken("other_access_token")}, dpop.ErrAccessTokenHashIsNotMatch},
+			{http.MethodGet, testHTU, []dpop.VerifyOption{dpop.VerifyJWKThumbprint("other_jkt")}, dpop.ErrJWKThumbprintIsNotMatch},
+			{http.MethodGet, testHTU, []dpop.VerifyOption{dpop.VerifyNonce("other_nonce")}, dpop.ErrNonceIsNotMatch},
+		} {
+			proof := must.One(prover.NewProof(context.Background(), http.MethodGet, testHTU, dpop.WithAccessToken(testAccessToken)))
+			if _, err := verifier.Verify(context.Background(), proof, tt.htm, tt.htu, tt.opts...); !errors.Is(err, tt.expect) {
+				t.Errorf("❌: (*dpop.Verifier).Verify: err != %v: %v", tt.expect, err)
+			}
+		}
+	})
+
+	t.Run("failure(iat)", func(t *testing.T) {
+		t.Parallel()
+
+		verifier := dpop.NewVerifier(context.Background(), dpop.WithMaxAge(time.Minute))
+		proof := must.One(prover.NewProof(timez.WithContext(context.Background(), time.Now().Add(-time.Hour)), http.MethodGet, testHTU))
+		if _, err := verifier.Verify(context.Background(), proof, http.MethodGet, testHTU); !errors.Is(err, jwt.ErrTokenIsTooOld) {
+			t.Errorf("❌: (*dpop.Verifier).Verify: err != jwt.ErrTokenIsTooOld: %v", err)
+		}
+	})
+
+	t.Run("failure(alg)", func(t *testing.T) {
+		t.Parallel()
+
+		verifier := dpop.NewVerifier(context.Background(), dpop.WithAlgorithms(jwa.RS256, jwa.HS256))
+		if algs := verifier.Algorithms(); len(algs) != 1 || algs[0] != jwa.RS256 {
+			t.Errorf("❌: (*dpop.Verifier).Algorithms: %v", algs)
+		}
+		proof := must.One(prover.NewProof(context.Background(), http.MethodGet, testHTU))
+		if _, err := verifier.Verify(context.Background(), proof, http.MethodGet, testHTU); err == nil {
+			t.Errorf("❌: (*dpop.Verifier).Verify: err == nil")
+		}
+	})
+
+	t.Run("failure(typ)", func(t *testing.T) {
+		t.Parallel()
+
+		pub := new(jwk.JSONWebKey).EncodeECDSAPublicKey(&ecdsaKey.PublicKey)
+		token := must.One(jwt.NewWithClaims(jws.WithECDSAKey(ecdsaKey), jose.NewHeader(jwa.ES256, jose.WithType("JWT"), jose.WithJSONWebKey(pub)),
+			&dpop.Proof{ClaimsSet: *jwt.NewClaimsSet(jwt.WithJWTID("test_jti"), jwt.WithIssuedAt(time.Now())), HTTPMethod: http.MethodGet, HTTPURI: testHTU}))
+		if _, err := dpop.NewVerifier(context.Background()).Verify(context.Background(), token, http.MethodGet, testHTU); !errors.Is(err, jwt.ErrTypeIsNotMatch) {
+			t.Errorf("❌: (*dpop.Verifier).Verify: err != jwt.ErrTypeIsNotMatch: %v", err)
+		}
+	})
+
+	t.Run("failure(jwk,private)", func(t *testing.T) {
+		t.Parallel()
+
+		key := must.One(ecdsa.GenerateKey(elliptic.P256(), rand.Reader))
+		priv := new(jwk.JSONWebKey).EncodeECDSAPrivateKey(key)
+		token := must.One(jwt.NewWithClaims(jws.WithECDSAKey(key), jose.NewHeader(jwa.ES256, jose.WithType(dpop.ProofType), jose.WithJSONWebKey(priv)),
+			&dpop.Proof{ClaimsSet: *jwt.NewClaimsSet(jwt.WithJWTID("test_jti"), jwt.WithIssuedAt(time.Now())), HTTPMethod: http.MethodGet, HTTPURI: testHTU}))
+		if _, err := dpop.NewVerifier(context.Background()).Verify(context.Background(), token, http.MethodGet, testHTU); !errors.Is(err, dpop.ErrJSONWebKeyIsPrivate) {
+			t.Errorf("❌: (*dpop.Verifier).Verify: err != dpop.ErrJSONWebKeyIsPrivate: %v", err)
+		}
+	})
+
+	t.Run("failure(empty)", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := dpop.NewVerifier(context.Background()).Verify(context.Background(), "", http.MethodGet, testHTU); !errors.Is(err, dpop.ErrProofIsEmpty) {
+			t.Errorf("❌: (*dpop.Verifier).Verify: err != dpop.ErrProofIsEmpty: %v", err)
+		}
+	})
+}
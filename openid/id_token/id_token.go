@@ -1,38 +1,45 @@
 package id_token //nolint:revive,stylecheck
 
+import (
+	"github.com/kunitsucom/util.go/jose/jwt"
+)
+
 // NOTE: ref. https://openid.net/specs/openid-connect-core-1_0.html#IDToken
 // NOTE: ref. http://openid-foundation-japan.github.io/openid-connect-core-1_0.ja.html#IDToken
 type Claims struct {
+	// ClaimsSet holds the registered claims "iss", "sub", "aud", "exp" and "iat" defined by OpenID Connect as follows:
+	//
 	// Issuer: "iss"
 	//
 	// REQUIRED. Issuer Identifier for the Issuer of the response. The iss value is a case sensitive URL using the https scheme that contains scheme, host, and optionally, port number and path components and no query or fragment components.
 	//
 	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#IDToken:~:text=by%20OpenID%20Connect%3A-,iss,-REQUIRED.%20Issuer%20Identifier
-	Issuer string `json:"iss"`
-	// SubjectIdentifier: "sub"
+	//
+	// Subject: "sub"
 	//
 	// REQUIRED. Subject Identifier. A locally unique and never reassigned identifier within the Issuer for the End-User, which is intended to be consumed by the Client, e.g., 24400320 or AItOawmwtWwcT0k51BayewNvutrJUqsvl6qs7A4. It MUST NOT exceed 255 ASCII characters in length. The sub value is a case sensitive string.
 	//
 	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#IDToken:~:text=or%20fragment%20components.-,sub,-REQUIRED.%20Subject%20Identifier
-	SubjectIdentifier string `json:"sub"` // set client identifier. len <= 255.
+	//
 	// Audience: "aud"
 	//
 	// REQUIRED. Audience(s) that this ID Token is intended for. It MUST contain the OAuth 2.0 client_id of the Relying Party as an audience value. It MAY also contain identifiers for other audiences. In the general case, the aud value is an array of case sensitive strings. In the common special case when there is one audience, the aud value MAY be a single case sensitive string.
 	//
 	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#IDToken:~:text=case%20sensitive%20string.-,aud,-REQUIRED.%20Audience(s
-	Audience string `json:"aud"`
+	//
 	// ExpirationTime: "exp"
 	//
 	// REQUIRED. Expiration time on or after which the ID Token MUST NOT be accepted for processing. The processing of this parameter requires that the current date/time MUST be before the expiration date/time listed in the value. Implementers MAY provide for some small leeway, usually no more than a few minutes, to account for clock skew. Its value is a JSON number representing the number of seconds from 1970-01-01T0:0:0Z as measured in UTC until the date/time. See RFC 3339Klyne, G., Ed. and C. Newman, “Date and Time on the Internet: Timestamps,” July 2002. [RFC3339] for details regarding date/times in general and UTC in particular.
 	//
 	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#IDToken:~:text=case%20sensitive%20string.-,exp,-REQUIRED.%20Expiration%20time
-	ExpirationTime int64 `json:"exp"`
+	//
 	// IssuedAt: "iat"
 	//
 	// REQUIRED. Time at which the JWT was issued. Its value is a JSON number representing the number of seconds from 1970-01-01T0:0:0Z as measured in UTC until the date/time.
 	//
 	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#IDToken:~:text=UTC%20in%20particular.-,iat,-REQUIRED.%20Time%20at
-	IssuedAt int64 `json:"iat"`
+	jwt.ClaimsSet
+
 	// AuthenticationTime: "auth_time"
 	//
 	// Time when the End-User authentication occurred. Its value is a JSON number representing the number of seconds from 1970-01-01T0:0:0Z as measured in UTC until the date/time. When a max_age request is made or when auth_time is requested as an Essential Claim, then this Claim is REQUIRED; otherwise, its inclusion is OPTIONAL. (The auth_time Claim semantically corresponds to the OpenID 2.0 PAPE [OpenID.PAPE] auth_time response parameter.)
@@ -56,7 +63,7 @@ type Claims struct {
 	// OPTIONAL. Authentication Methods References. JSON array of strings that are identifiers for authentication methods used in the authentication. For instance, values might indicate that both password and OTP authentication methods were used. The definition of particular values to be used in the amr Claim is beyond the scope of this specification. Parties using this claim will need to agree upon the meanings of the values used, which may be context-specific. The amr value is an array of case sensitive strings.
 	//
 	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#IDToken:~:text=case%20sensitive%20strings.-,azp,-OPTIONAL.%20Authorized%20party
-	AuthenticationMethodsReferences string `json:"amr,omitempty"`
+	AuthenticationMethodsReferences []string `json:"amr,omitempty"`
 	// AuthorizedParty: "azp"
 	//
 	// OPTIONAL. Authorized party - the party to which the ID Token was issued. If present, it MUST contain the OAuth 2.0 Client ID of this party. This Claim is only needed when the ID Token has a single audience value and that audience is different than the authorized party. It MAY be included even when the authorized party is the same as the sole audience. The azp value is a case sensitive string containing a StringOrURI value.
@@ -70,6 +77,23 @@ type Claims struct {
 	//
 	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#CodeIDToken
 	AccessTokenHash string `json:"at_hash,omitempty"` //nolint:tagliatelle
+	// CodeHash: "c_hash"
+	//
+	// Code hash value. Its value is the base64url encoding of the left-most half of the hash of the octets of the ASCII representation of the code value, where the hash algorithm used is the hash algorithm used in the alg Header Parameter of the ID Token's JOSE Header. For instance, if the alg is HS512, hash the code value with SHA-512, then take the left-most 256 bits and base64url encode them. The c_hash value is a case sensitive string. If the ID Token is issued from the Authorization Endpoint with a code, which is the case for the response_type values code id_token and code id_token token, this is REQUIRED; otherwise, its inclusion is OPTIONAL.
+	//
+	//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#HybridIDToken
+	CodeHash string `json:"c_hash,omitempty"` //nolint:tagliatelle
+}
+
+var _ jwt.Claims = (*Claims)(nil)
+
+// MarshalJSON returns the JSON encoding of c, which includes "nonce", "at_hash" and the other ID Token claims as well as the claims of the embedded jwt.ClaimsSet.
+// It is required because (*jwt.ClaimsSet).MarshalJSON is promoted to Claims, which ignores the other fields.
+func (c *Claims) MarshalJSON() ([]byte, error) {
+	return jwt.MarshalClaims(c) //nolint:wrapcheck
 }
 
-// TODO: impl
+// UnmarshalJSON parses the JSON-encoded data into c, including "nonce", "at_hash" and the other ID Token claims.
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	return jwt.UnmarshalClaims(data, c) //nolint:wrapcheck
+}
@@ -0,0 +1,45 @@
+package id_token_test //nolint:revive,stylecheck
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kunitsucom/util.go/jose/jwt"
+	id_token "github.com/kunitsucom/util.go/openid/id_token" //nolint:revive,stylecheck
+)
+
+func TestClaims_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(round_trip)", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &id_token.Claims{
+			ClaimsSet:                           *jwt.NewClaimsSet(jwt.WithIssuer("https://accounts.example.com"), jwt.WithSubject("s"), jwt.WithAudience(testClientID), jwt.WithPrivateClaim("private", "value")),
+			AuthenticationTime:                  1,
+			Nonce:                               "test_nonce",
+			AuthenticationContextClassReference: "0",
+			AuthenticationMethodsReferences:     []string{"pwd", "otp"},
+			AuthorizedParty:                     testClientID,
+			AccessTokenHash:                     "test_at_hash",
+			CodeHash:                            "test_c_hash",
+		}
+		b, err := json.Marshal(expected)
+		if err != nil {
+			t.Fatalf("❌: json.Marshal: err != nil: %v", err)
+		}
+		actual := new(id_token.Claims)
+		if err := json.Unmarshal(b, actual); err != nil {
+			t.Fatalf("❌: json.Unmarshal: err != nil: %v", err)
+		}
+		// NOTE: as with jwt.VerifyInto, PrivateClaims holds all the claims other than the registered claims.
+		if actual.PrivateClaims["private"] != "value" || actual.PrivateClaims["nonce"] != "test_nonce" {
+			t.Errorf("❌: PrivateClaims: %v", actual.PrivateClaims)
+		}
+		actual.PrivateClaims = expected.PrivateClaims
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("❌: expected(%+v) != actual(%+v): %s", expected, actual, b)
+		}
+	})
+}
@@ -0,0 +1,334 @@
+package id_token //nolint:revive,stylecheck
+
+import (
+	"context"
+	"crypto"
+	_ "crypto/sha256" // for crypto.SHA256
+	_ "crypto/sha512" // for crypto.SHA384, crypto.SHA512
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	slicez "github.com/kunitsucom/util.go/slices"
+	timez "github.com/kunitsucom/util.go/time"
+)
+
+// - ref. ID Token Validation https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+
+var (
+	ErrAudienceIsNotTrusted         = errors.New("id_token: audience is not trusted")
+	ErrAuthorizedPartyIsNotMatch    = errors.New("id_token: authorized party is not match")
+	ErrNonceIsNotMatch              = errors.New("id_token: nonce is not match")
+	ErrAuthenticationTimeIsTooOld   = errors.New("id_token: authentication time is too old")
+	ErrACRIsNotMatch                = errors.New("id_token: acr is not match")
+	ErrAccessTokenHashIsNotMatch    = errors.New("id_token: access token hash is not match")
+	ErrCodeHashIsNotMatch           = errors.New("id_token: code hash is not match")
+	ErrHashAlgorithmIsNotSupported  = errors.New("id_token: hash algorithm is not supported")
+	ErrAlgorithmNoneIsNotAllowed    = errors.New("id_token: algorithm none is not allowed")
+	ErrProviderMetadataIsIncomplete = errors.New("id_token: provider metadata is incomplete")
+)
+
+// Verifier validates ID Tokens issued by the OpenID Provider of providerMetadataURL for the client of clientID.
+//
+// The provider metadata is fetched by discovery.Client, and the signing keys are fetched from "jwks_uri" by jwk.Client.
+//
+// Example:
+//
+//	verifier := id_token.NewVerifier(discovery.Google, "YOUR_CLIENT_ID", id_token.WithLeeway(30*time.Second))
+//
+//	claims, err := verifier.Verify(ctx, idToken,
+//		id_token.VerifyNonce(nonce),
+//		id_token.VerifyAccessToken(accessToken),
+//	)
+type Verifier struct {
+	providerMetadataURL discovery.ProviderMetadataURL
+	clientID            string
+	discoveryClient     *discovery.Client
+	jwkClient           *jwk.Client
+	trustedAudiences    []string
+	algorithms          []string
+	leeway              time.Duration
+}
+
+type VerifierOption func(*Verifier)
+
+// WithDiscoveryClient sets the client to fetch the provider metadata, instead of discovery.Default.
+func WithDiscoveryClient(client *discovery.Client) VerifierOption {
+	return func(v *Verifier) {
+		v.discoveryClient = client
+	}
+}
+
+// WithJWKClient sets the client to fetch the JWK Set, instead of jwk.Default.
+func WithJWKClient(client *jwk.Client) VerifierOption {
+	return func(v *Verifier) {
+		v.jwkClient = client
+	}
+}
+
+// WithTrustedAudiences sets the audiences trusted in addition to the client ID.
+// The ID Token which contains any other audience is rejected.
+func WithTrustedAudiences(aud ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.trustedAudiences = append(v.trustedAudiences, aud...)
+	}
+}
+
+// WithAlgorithms sets the allow-list of "alg", instead of "id_token_signing_alg_values_supported" of the provider metadata.
+// "none" is never allowed.
+func WithAlgorithms(alg ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.algorithms = append(v.algorithms, alg...)
+	}
+}
+
+// WithLeeway sets the leeway for clock skew in verifying "exp", "iat" and "auth_time".
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
+
+func NewVerifier(providerMetadataURL discovery.ProviderMetadataURL, clientID string, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		providerMetadataURL: providerMetadataURL,
+		clientID:            clientID,
+		discoveryClient:     discovery.Default,
+		jwkClient:           jwk.Default,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+type verifyOption struct {
+	nonce       string
+	maxAge      time.Duration
+	useMaxAge   bool
+	acrValues   []string
+	accessToken string
+	code        string
+}
+
+type VerifyOption func(*verifyOption)
+
+// VerifyNonce sets the nonce sent in the Authentication Request. If set, "nonce" is required and must be equal to nonce.
+func VerifyNonce(nonce string) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.nonce = nonce
+	}
+}
+
+// VerifyMaxAge sets max_age sent in the Authentication Request. If set, "auth_time" is required and must not be older than maxAge.
+func VerifyMaxAge(maxAge time.Duration) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.maxAge = maxAge
+		vo.useMaxAge = true
+	}
+}
+
+// VerifyACRValues sets the acceptable "acr" values. If set, "acr" is required and must be one of acrValues.
+func VerifyACRValues(acrValues ...string) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.acrValues = append(vo.acrValues, acrValues...)
+	}
+}
+
+// VerifyAccessToken sets the access token issued with the ID Token. If "at_hash" is present, it must match accessToken.
+//
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#CodeIDToken
+func VerifyAccessToken(accessToken string) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.accessToken = accessToken
+	}
+}
+
+// VerifyCode sets the authorization code issued with the ID Token. If set, "c_hash" is required and must match code.
+//
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#HybridIDToken
+func VerifyCode(code string) VerifyOption {
+	return func(vo *verifyOption) {
+		vo.code = code
+	}
+}
+
+// Verify validates idToken according to OpenID Connect Core 1.0 Section 3.1.3.7, and returns the claims.
+// The error of a failed rule is *jwt.ValidationError.
+func (v *Verifier) Verify(ctx context.Context, idToken string, opts ...VerifyOption) (*Claims, error) {
+	claims := new(Claims)
+	if _, err := v.VerifyInto(ctx, idToken, claims, opts...); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// ClaimsHolder is the interface implemented by a pointer to Claims or a user-defined struct which embeds Claims.
+//
+// Example:
+//
+//	type MyClaims struct {
+//		id_token.Claims
+//		Email string `json:"email"`
+//	}
+//
+//	var _ id_token.ClaimsHolder = (*MyClaims)(nil)
+type ClaimsHolder interface {
+	jwt.Claims
+	idTokenClaims() *Claims
+}
+
+func (c *Claims) idTokenClaims() *Claims { return c }
+
+var _ ClaimsHolder = (*Claims)(nil)
+
+// VerifyInto validates idToken in the same way as Verify, and decodes the payload into claims.
+func (v *Verifier) VerifyInto(ctx context.Context, idToken string, claims ClaimsHolder, opts ...VerifyOption) (*jose.Header, error) {
+	vo := new(verifyOption)
+	for _, opt := range opts {
+		opt(vo)
+	}
+
+	metadata, err := v.discoveryClient.GetProviderMetadata(ctx, v.providerMetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("(*discovery.Client).GetProviderMetadata: %w", err)
+	}
+	if metadata.Issuer == "" || metadata.JwksURI == "" {
+		return nil, fmt.Errorf("issuer=%q jwks_uri=%q: %w", metadata.Issuer, metadata.JwksURI, ErrProviderMetadataIsIncomplete)
+	}
+
+	algorithms := v.algorithms
+	if len(algorithms) == 0 {
+		algorithms = metadata.IDTokenSigningAlgValuesSupported
+	}
+	if len(algorithms) == 0 {
+		// NOTE: The RS256 is the default value of id_token_signing_alg_values_supported.
+		algorithms = []string{jwa.RS256}
+	}
+	algorithms = slicez.Exclude(algorithms, []string{jwa.None})
+	if len(algorithms) == 0 {
+		return nil, ErrAlgorithmNoneIsNotAllowed
+	}
+
+	header, err := jwt.VerifyInto(
+		jws.UseJWKSetURLWithClient(ctx, v.jwkClient, metadata.JwksURI),
+		idToken,
+		claims,
+		jwt.VerifyContext(ctx),
+		jwt.VerifyLeeway(v.leeway),
+		jwt.VerifyAlgorithm(algorithms...),
+		jwt.VerifyRequiredClaims("iss", "sub", "aud", "exp", "iat"),
+		jwt.VerifyIssuer(metadata.Issuer),
+		jwt.VerifyAudience(v.clientID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("jwt.VerifyInto: %w", err)
+	}
+
+	if err := v.verifyClaims(header, claims.idTokenClaims(), vo, timez.Now(ctx)); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+//nolint:cyclop
+func (v *Verifier) verifyClaims(header *jose.Header, c *Claims, vo *verifyOption, now time.Time) error {
+	for _, aud := range c.Audience {
+		if aud != v.clientID && !slicez.Contains(v.trustedAudiences, aud) {
+			return &jwt.ValidationError{Rule: "aud", Reason: fmt.Sprintf("aud=%s is not trusted", aud), Err: ErrAudienceIsNotTrusted}
+		}
+	}
+
+	if c.AuthorizedParty != "" || len(c.Audience) > 1 {
+		if c.AuthorizedParty != v.clientID {
+			return &jwt.ValidationError{Rule: "azp", Reason: fmt.Sprintf("want=%s got=%s", v.clientID, c.AuthorizedParty), Err: ErrAuthorizedPartyIsNotMatch}
+		}
+	}
+
+	if vo.nonce != "" && subtle.ConstantTimeCompare([]byte(vo.nonce), []byte(c.Nonce)) != 1 {
+		return &jwt.ValidationError{Rule: "nonce", Reason: "nonce is not match", Err: ErrNonceIsNotMatch}
+	}
+
+	if len(vo.acrValues) > 0 && !slicez.Contains(vo.acrValues, c.AuthenticationContextClassReference) {
+		return &jwt.ValidationError{Rule: "acr", Reason: fmt.Sprintf("want=%v got=%s", vo.acrValues, c.AuthenticationContextClassReference), Err: ErrACRIsNotMatch}
+	}
+
+	if vo.useMaxAge {
+		if c.AuthenticationTime == 0 {
+			return &jwt.ValidationError{Rule: "auth_time", Reason: "claim is missing", Err: jwt.ErrRequiredClaimIsMissing}
+		}
+		if !now.Before(time.Unix(c.AuthenticationTime, 0).Add(vo.maxAge + v.leeway)) {
+			return &jwt.ValidationError{Rule: "auth_time", Reason: fmt.Sprintf("auth_time=%d maxAge=%s now=%d leeway=%s", c.AuthenticationTime, vo.maxAge, now.Unix(), v.leeway), Err: ErrAuthenticationTimeIsTooOld}
+		}
+	}
+
+	if vo.accessToken != "" && c.AccessTokenHash != "" {
+		if err := verifyHash("at_hash", header.Algorithm, vo.accessToken, c.AccessTokenHash, ErrAccessTokenHashIsNotMatch); err != nil {
+			return err
+		}
+	}
+
+	if vo.code != "" {
+		if c.CodeHash == "" {
+			return &jwt.ValidationError{Rule: "c_hash", Reason: "claim is missing", Err: jwt.ErrRequiredClaimIsMissing}
+		}
+		if err := verifyHash("c_hash", header.Algorithm, vo.code, c.CodeHash, ErrCodeHashIsNotMatch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func verifyHash(rule, alg, value, hashValue string, errNotMatch error) error {
+	want, err := LeftHalfHash(alg, value)
+	if err != nil {
+		return &jwt.ValidationError{Rule: rule, Reason: fmt.Sprintf("alg=%s", alg), Err: err}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(want), []byte(hashValue)) != 1 {
+		return &jwt.ValidationError{Rule: rule, Reason: fmt.Sprintf("%s is not match", rule), Err: errNotMatch}
+	}
+
+	return nil
+}
+
+// LeftHalfHash returns the base64url encoding of the left-most half of the hash of value,
+// where the hash algorithm is the one used in alg, i.e. the value of "at_hash" and "c_hash".
+// For "EdDSA", SHA-512 is used because the curve of the ID Token is Ed25519.
+//
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#CodeIDToken
+//   - ref. https://bitbucket.org/openid/connect/issues/1125
+func LeftHalfHash(alg, value string) (string, error) {
+	var hash crypto.Hash
+	switch {
+	case alg == jwa.EdDSA:
+		hash = crypto.SHA512
+	case strings.HasSuffix(alg, "256") || strings.HasSuffix(alg, "256K"):
+		hash = crypto.SHA256
+	case strings.HasSuffix(alg, "384"):
+		hash = crypto.SHA384
+	case strings.HasSuffix(alg, "512"):
+		hash = crypto.SHA512
+	default:
+		return "", fmt.Errorf("alg=%s: %w", alg, ErrHashAlgorithmIsNotSupported)
+	}
+
+	h := hash.New()
+	_, _ = h.Write([]byte(value))
+	sum := h.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
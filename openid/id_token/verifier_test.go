@@ -0,0 +1,220 @@
+package id_token_test //nolint:revive,stylecheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	id_token "github.com/kunitsucom/util.go/openid/id_token" //nolint:revive,stylecheck
+)
+
+const testClientID = "test_client_id"
+
+type testProvider struct {
+	issuer     string
+	keyManager *jwk.KeyManager
+	verifier   *id_token.Verifier
+}
+
+func newTestProvider(t *testing.T, opts ...id_token.VerifierOption) *testProvider {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	keyManager := must.One(jwk.NewKeyManager("EC", jwa.ES256))
+	mux := http.NewServeMux()
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	metadata := &discovery.ProviderMetadata{
+		Issuer:                           s.URL,
+		JwksURI:                          s.URL + "/certs",
+		IDTokenSigningAlgValuesSupported: []string{jwa.ES256},
+	}
+	mux.HandleFunc(discovery.ProviderMetadataURLPath, func(w http.ResponseWriter, _ *http.Request) {
+		must.Must(json.NewEncoder(w).Encode(metadata))
+	})
+	mux.Handle("/certs", keyManager)
+
+	opts = append([]id_token.VerifierOption{
+		id_token.WithDiscoveryClient(discovery.New(ctx)),
+		id_token.WithJWKClient(jwk.NewClient(ctx)),
+	}, opts...)
+
+	return &testProvider{
+		issuer:     s.URL,
+		keyManager: keyManager,
+		verifier:   id_token.NewVerifier(s.URL+discovery.ProviderMetadataURLPath, testClientID, opts...),
+	}
+}
+
+func (p *testProvider) claims(opts ...func(c *id_token.Claims)) *id_token.Claims {
+	c := &id_token.Claims{
+		ClaimsSet: *jwt.NewClaimsSet(
+			jwt.WithIssuer(p.issuer),
+			jwt.WithSubject("userID"),
+			jwt.WithAudience(testClientID),
+			jwt.WithExpirationTime(time.Now().Add(time.Hour)),
+			jwt.WithIssuedAt(time.Now()),
+		),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (p *testProvider) sign(t *testing.T, claims *id_token.Claims) string {
+	t.Helper()
+	return must.One(jwt.NewWithClaims(jws.WithSigningKeyProvider(p.keyManager), jose.NewHeader(jwa.ES256, jose.WithType("JWT")), claims))
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProvider(t)
+		accessToken, code := "test_access_token", "test_code"
+		idToken := p.sign(t, p.claims(func(c *id_token.Claims) {
+			c.Nonce = "test_nonce"
+			c.AuthenticationTime = time.Now().Add(-time.Minute).Unix()
+			c.AuthenticationContextClassReference = "urn:mace:incommon:iap:silver"
+			c.AccessTokenHash = must.One(id_token.LeftHalfHash(jwa.ES256, accessToken))
+			c.CodeHash = must.One(id_token.LeftHalfHash(jwa.ES256, code))
+		}))
+
+		claims, err := p.verifier.Verify(context.Background(), idToken,
+			id_token.VerifyNonce("test_nonce"),
+			id_token.VerifyMaxAge(10*time.Minute),
+			id_token.VerifyACRValues("urn:mace:incommon:iap:silver"),
+			id_token.VerifyAccessToken(accessToken),
+			id_token.VerifyCode(code),
+		)
+		if err != nil {
+			t.Fatalf("❌: (*id_token.Verifier).Verify: err != nil: %v", err)
+		}
+		if claims.Subject != "userID" || claims.Nonce != "test_nonce" {
+			t.Errorf("❌: (*id_token.Verifier).Verify: sub=%s nonce=%s", claims.Subject, claims.Nonce)
+		}
+	})
+
+	t.Run("success(azp,trusted_audience)", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProvider(t, id_token.WithTrustedAudiences("trusted_audience"))
+		idToken := p.sign(t, p.claims(func(c *id_token.Claims) {
+			c.Audience = []string{testClientID, "trusted_audience"}
+			c.AuthorizedParty = testClientID
+		}))
+		if _, err := p.verifier.Verify(context.Background(), idToken); err != nil {
+			t.Fatalf("❌: (*id_token.Verifier).Verify: err != nil: %v", err)
+		}
+	})
+
+	t.Run("success(key_rotation)", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProvider(t)
+		if _, err := p.verifier.Verify(context.Background(), p.sign(t, p.claims())); err != nil {
+			t.Fatalf("❌: (*id_token.Verifier).Verify: err != nil: %v", err)
+		}
+		// NOTE: the next key is published in advance, so the cached JWK Set can verify the ID Token signed after rotation.
+		must.Must(p.keyManager.Rotate())
+		if _, err := p.verifier.Verify(context.Background(), p.sign(t, p.claims())); err != nil {
+			t.Fatalf("❌: (*id_token.Verifier).Verify: err != nil: %v", err)
+		}
+	})
+
+	failures := []struct {
+		name    string
+		vopts   []id_token.VerifierOption
+		modify  func(c *id_token.Claims)
+		opts    []id_token.VerifyOption
+		wantErr error
+	}{
+		{name: "iss", modify: func(c *id_token.Claims) { c.Issuer = "https://evil.example.com" }, wantErr: jwt.ErrIssuerIsNotMatch},
+		{name: "aud", modify: func(c *id_token.Claims) { c.Audience = []string{"other_client_id"} }, wantErr: jwt.ErrAudienceIsNotMatch},
+		{name: "aud,untrusted", modify: func(c *id_token.Claims) {
+			c.Audience = []string{testClientID, "untrusted_audience"}
+			c.AuthorizedParty = testClientID
+		}, wantErr: id_token.ErrAudienceIsNotTrusted},
+		{name: "azp,missing", vopts: []id_token.VerifierOption{id_token.WithTrustedAudiences("trusted_audience")}, modify: func(c *id_token.Claims) {
+			c.Audience = []string{testClientID, "trusted_audience"}
+		}, wantErr: id_token.ErrAuthorizedPartyIsNotMatch},
+		{name: "azp", modify: func(c *id_token.Claims) { c.AuthorizedParty = "other_client_id" }, wantErr: id_token.ErrAuthorizedPartyIsNotMatch},
+		{name: "exp", modify: func(c *id_token.Claims) { c.ExpirationTime = time.Now().Add(-time.Minute).Unix() }, wantErr: jwt.ErrTokenIsExpired},
+		{name: "iat,missing", modify: func(c *id_token.Claims) { c.IssuedAt = 0 }, wantErr: jwt.ErrRequiredClaimIsMissing},
+		{name: "nonce", modify: func(c *id_token.Claims) { c.Nonce = "other_nonce" }, opts: []id_token.VerifyOption{id_token.VerifyNonce("test_nonce")}, wantErr: id_token.ErrNonceIsNotMatch},
+		{name: "nonce,missing", opts: []id_token.VerifyOption{id_token.VerifyNonce("test_nonce")}, wantErr: id_token.ErrNonceIsNotMatch},
+		{name: "auth_time,missing", opts: []id_token.VerifyOption{id_token.VerifyMaxAge(time.Minute)}, wantErr: jwt.ErrRequiredClaimIsMissing},
+		{name: "auth_time", modify: func(c *id_token.Claims) { c.AuthenticationTime = time.Now().Add(-time.Hour).Unix() }, opts: []id_token.VerifyOption{id_token.VerifyMaxAge(time.Minute)}, wantErr: id_token.ErrAuthenticationTimeIsTooOld},
+		{name: "acr", modify: func(c *id_token.Claims) { c.AuthenticationContextClassReference = "0" }, opts: []id_token.VerifyOption{id_token.VerifyACRValues("urn:mace:incommon:iap:silver")}, wantErr: id_token.ErrACRIsNotMatch},
+		{name: "at_hash", modify: func(c *id_token.Claims) { c.AccessTokenHash = "invalid" }, opts: []id_token.VerifyOption{id_token.VerifyAccessToken("test_access_token")}, wantErr: id_token.ErrAccessTokenHashIsNotMatch},
+		{name: "c_hash", modify: func(c *id_token.Claims) { c.CodeHash = "invalid" }, opts: []id_token.VerifyOption{id_token.VerifyCode("test_code")}, wantErr: id_token.ErrCodeHashIsNotMatch},
+		{name: "c_hash,missing", opts: []id_token.VerifyOption{id_token.VerifyCode("test_code")}, wantErr: jwt.ErrRequiredClaimIsMissing},
+		{name: "alg", vopts: []id_token.VerifierOption{id_token.WithAlgorithms(jwa.RS256)}, wantErr: jwt.ErrAlgorithmIsNotAllowed},
+		{name: "alg,none", vopts: []id_token.VerifierOption{id_token.WithAlgorithms(jwa.None)}, wantErr: id_token.ErrAlgorithmNoneIsNotAllowed},
+	}
+	for _, tt := range failures {
+		t.Run("failure("+tt.name+")", func(t *testing.T) {
+			t.Parallel()
+			p := newTestProvider(t, tt.vopts...)
+			claims := p.claims()
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+			_, err := p.verifier.Verify(context.Background(), p.sign(t, claims), tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("❌: (*id_token.Verifier).Verify: err != %v: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("failure(signature)", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProvider(t)
+		other := newTestProvider(t)
+		idToken := other.sign(t, p.claims())
+		if _, err := p.verifier.Verify(context.Background(), idToken); !errors.Is(err, jwk.ErrKidNotFound) {
+			t.Fatalf("❌: (*id_token.Verifier).Verify: err != jwk.ErrKidNotFound: %v", err)
+		}
+	})
+}
+
+func TestLeftHalfHash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(RS256)", func(t *testing.T) {
+		t.Parallel()
+		// NOTE: ref. https://openid.net/specs/openid-connect-core-1_0.html#code-id_tokenExample
+		const (
+			code  = "Qcb0Orv1zh30vL1MPRsbm-diHiMwcLyZvn1arpZv-Jxf_11jnpEX3Tgfvk"
+			cHash = "LDktKdoQak3Pk0cnXxCltA"
+		)
+		got, err := id_token.LeftHalfHash(jwa.RS256, code)
+		if err != nil {
+			t.Fatalf("❌: id_token.LeftHalfHash: err != nil: %v", err)
+		}
+		if got != cHash {
+			t.Errorf("❌: id_token.LeftHalfHash: want(%s) != got(%s)", cHash, got)
+		}
+	})
+
+	t.Run("failure(none)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := id_token.LeftHalfHash(jwa.None, "value"); !errors.Is(err, id_token.ErrHashAlgorithmIsNotSupported) {
+			t.Fatalf("❌: id_token.LeftHalfHash: err != id_token.ErrHashAlgorithmIsNotSupported: %v", err)
+		}
+	})
+}
@@ -0,0 +1,278 @@
+package logout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	slicez "github.com/kunitsucom/util.go/slices"
+	syncz "github.com/kunitsucom/util.go/sync"
+)
+
+// - ref. OpenID Connect Back-Channel Logout 1.0 https://openid.net/specs/openid-connect-backchannel-1_0.html
+
+const (
+	// BackChannelLogoutEvent is the member name of "events" claim of Logout Token.
+	BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+	// LogoutTokenType is the explicit "typ" of Logout Token.
+	LogoutTokenType = "logout+jwt"
+)
+
+var (
+	ErrLogoutTokenIsEmpty               = errors.New("logout: logout_token is empty")
+	ErrEventsClaimIsInvalid             = errors.New("logout: events claim is invalid")
+	ErrNonceIsPresent                   = errors.New("logout: nonce is present in logout token")
+	ErrSubjectAndSessionIDAreMissing    = errors.New("logout: both sub and sid are missing")
+	ErrLogoutTokenIsReplayed            = errors.New("logout: logout token is replayed")
+	ErrProviderMetadataIsIncomplete     = errors.New("logout: provider metadata is incomplete")
+	ErrAlgorithmNoneIsNotAllowed        = errors.New("logout: algorithm none is not allowed")
+	ErrBackChannelLogoutCallbackIsEmpty = errors.New("logout: back-channel logout callback is empty")
+)
+
+// LogoutToken is the claims of Logout Token.
+//
+//   - ref. https://openid.net/specs/openid-connect-backchannel-1_0.html#LogoutToken
+//
+//nolint:tagliatelle
+type LogoutToken struct {
+	jwt.ClaimsSet
+
+	// SessionID: "sid"
+	SessionID string `json:"sid,omitempty"`
+	// Events: "events"
+	Events map[string]json.RawMessage `json:"events"`
+}
+
+var _ jwt.Claims = (*LogoutToken)(nil)
+
+// MarshalJSON returns the JSON encoding of c, which includes "sid" and "events" as well as the claims of the embedded jwt.ClaimsSet.
+// It is required because (*jwt.ClaimsSet).MarshalJSON is promoted to LogoutToken, which ignores the other fields.
+func (c *LogoutToken) MarshalJSON() ([]byte, error) {
+	return jwt.MarshalClaims(c) //nolint:wrapcheck
+}
+
+// UnmarshalJSON parses the JSON-encoded data into c, including "sid" and "events".
+func (c *LogoutToken) UnmarshalJSON(data []byte) error {
+	return jwt.UnmarshalClaims(data, c) //nolint:wrapcheck
+}
+
+// BackChannelLogoutHandler is the http.Handler of the RP's back-channel logout URI.
+// It verifies the Logout Token sent by the OP, and calls onLogout to log out the End-User of "sub" and/or the session of "sid".
+//
+// Example:
+//
+//	handler := logout.NewBackChannelLogoutHandler(ctx, discovery.Google, "YOUR_CLIENT_ID",
+//		func(ctx context.Context, token *logout.LogoutToken) error {
+//			return sessionStore.DeleteBySessionID(ctx, token.SessionID)
+//		},
+//	)
+//	http.Handle("/backchannel_logout", handler)
+type BackChannelLogoutHandler struct {
+	providerMetadataURL discovery.ProviderMetadataURL
+	clientID            string
+	onLogout            func(ctx context.Context, token *LogoutToken) error
+	discoveryClient     *discovery.Client
+	jwkClient           *jwk.Client
+	leeway              time.Duration
+	requireType         bool
+	jtiCacheTTL         time.Duration
+	jtiCache            syncz.Map[struct{}]
+	errorHandler        func(r *http.Request, err error)
+}
+
+type BackChannelLogoutHandlerOption func(*BackChannelLogoutHandler)
+
+// WithDiscoveryClient sets the client to fetch the provider metadata, instead of discovery.Default.
+func WithDiscoveryClient(client *discovery.Client) BackChannelLogoutHandlerOption {
+	return func(h *BackChannelLogoutHandler) {
+		h.discoveryClient = client
+	}
+}
+
+// WithJWKClient sets the client to fetch the JWK Set, instead of jwk.Default.
+func WithJWKClient(client *jwk.Client) BackChannelLogoutHandlerOption {
+	return func(h *BackChannelLogoutHandler) {
+		h.jwkClient = client
+	}
+}
+
+// WithLeeway sets the leeway for clock skew in verifying "exp" and "iat".
+func WithLeeway(leeway time.Duration) BackChannelLogoutHandlerOption {
+	return func(h *BackChannelLogoutHandler) {
+		h.leeway = leeway
+	}
+}
+
+// WithRequireLogoutTokenType requires "typ" header of Logout Token to be "logout+jwt".
+//
+//   - ref. https://openid.net/specs/openid-connect-backchannel-1_0.html#Security
+func WithRequireLogoutTokenType() BackChannelLogoutHandlerOption {
+	return func(h *BackChannelLogoutHandler) {
+		h.requireType = true
+	}
+}
+
+// WithJTICache sets the cache of "jti" for replay protection, and the duration to remember "jti".
+// The duration should be longer than the lifetime of Logout Tokens.
+func WithJTICache(cache syncz.Map[struct{}], ttl time.Duration) BackChannelLogoutHandlerOption {
+	return func(h *BackChannelLogoutHandler) {
+		h.jtiCache, h.jtiCacheTTL = cache, ttl
+	}
+}
+
+// WithErrorHandler sets the handler which receives the error of VerifyLogoutToken or onLogout in ServeHTTP, e.g. for logging.
+// The error is not written to the response, since it may contain the details of the RP, e.g. the URLs or the session store.
+func WithErrorHandler(errorHandler func(r *http.Request, err error)) BackChannelLogoutHandlerOption {
+	return func(h *BackChannelLogoutHandler) {
+		h.errorHandler = errorHandler
+	}
+}
+
+func NewBackChannelLogoutHandler(ctx context.Context, providerMetadataURL discovery.ProviderMetadataURL, clientID string, onLogout func(ctx context.Context, token *LogoutToken) error, opts ...BackChannelLogoutHandlerOption) *BackChannelLogoutHandler {
+	const defaultJTICacheTTL = 10 * time.Minute
+
+	h := &BackChannelLogoutHandler{
+		providerMetadataURL: providerMetadataURL,
+		clientID:            clientID,
+		onLogout:            onLogout,
+		discoveryClient:     discovery.Default,
+		jwkClient:           jwk.Default,
+		jtiCacheTTL:         defaultJTICacheTTL,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.jtiCache == nil {
+		h.jtiCache = syncz.NewMap[struct{}](ctx, syncz.WithNewMapOptionTTL(h.jtiCacheTTL))
+	}
+
+	return h
+}
+
+// VerifyLogoutToken validates logoutToken according to OpenID Connect Back-Channel Logout 1.0 Section 2.6.
+//
+//   - ref. https://openid.net/specs/openid-connect-backchannel-1_0.html#Validation
+//
+//nolint:cyclop
+func (h *BackChannelLogoutHandler) VerifyLogoutToken(ctx context.Context, logoutToken string) (*LogoutToken, error) {
+	if logoutToken == "" {
+		return nil, ErrLogoutTokenIsEmpty
+	}
+
+	metadata, err := h.discoveryClient.GetProviderMetadata(ctx, h.providerMetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("(*discovery.Client).GetProviderMetadata: %w", err)
+	}
+	if metadata.Issuer == "" || metadata.JwksURI == "" {
+		return nil, fmt.Errorf("issuer=%q jwks_uri=%q: %w", metadata.Issuer, metadata.JwksURI, ErrProviderMetadataIsIncomplete)
+	}
+
+	// NOTE: Logout Token is signed with the same algorithm as ID Token.
+	algorithms := metadata.IDTokenSigningAlgValuesSupported
+	if len(algorithms) == 0 {
+		algorithms = []string{jwa.RS256}
+	}
+	algorithms = slicez.Exclude(algorithms, []string{jwa.None})
+	if len(algorithms) == 0 {
+		return nil, ErrAlgorithmNoneIsNotAllowed
+	}
+
+	opts := []jwt.VerifyOption{
+		jwt.VerifyContext(ctx),
+		jwt.VerifyLeeway(h.leeway),
+		jwt.VerifyAlgorithm(algorithms...),
+		jwt.VerifyRequiredClaims("iss", "aud", "iat", "exp", "jti"),
+		jwt.VerifyIssuer(metadata.Issuer),
+		jwt.VerifyAudience(h.clientID),
+	}
+	if h.requireType {
+		opts = append(opts, jwt.VerifyType(LogoutTokenType))
+	} else {
+		// NOTE: "typ" is optional, but rejects e.g. "at+jwt" to prevent cross-JWT confusion.
+		opts = append(opts, jwt.VerifyType("", "JWT", LogoutTokenType))
+	}
+
+	claims := new(LogoutToken)
+	if _, err := jwt.VerifyInto(jws.UseJWKSetURLWithClient(ctx, h.jwkClient, metadata.JwksURI), logoutToken, claims, opts...); err != nil {
+		return nil, fmt.Errorf("jwt.VerifyInto: %w", err)
+	}
+
+	if claims.Subject == "" && claims.SessionID == "" {
+		return nil, ErrSubjectAndSessionIDAreMissing
+	}
+
+	event, ok := claims.Events[BackChannelLogoutEvent]
+	if !ok {
+		return nil, fmt.Errorf("events does not contain %s: %w", BackChannelLogoutEvent, ErrEventsClaimIsInvalid)
+	}
+	var eventValue map[string]any
+	if err := json.Unmarshal(event, &eventValue); err != nil || eventValue == nil {
+		return nil, fmt.Errorf("events[%s] is not JSON object: %w", BackChannelLogoutEvent, ErrEventsClaimIsInvalid)
+	}
+
+	if _, ok := claims.PrivateClaims["nonce"]; ok {
+		return nil, ErrNonceIsPresent
+	}
+
+	if _, loaded := h.jtiCache.LoadOrStore(metadata.Issuer+" "+claims.JWTID, struct{}{}); loaded {
+		return nil, fmt.Errorf("jti=%s: %w", claims.JWTID, ErrLogoutTokenIsReplayed)
+	}
+
+	return claims, nil
+}
+
+// ServeHTTP receives the Logout Token as "logout_token" of application/x-www-form-urlencoded POST body.
+//
+//   - ref. https://openid.net/specs/openid-connect-backchannel-1_0.html#BCRequest
+//   - ref. https://openid.net/specs/openid-connect-backchannel-1_0.html#BCResponse
+func (h *BackChannelLogoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, err := h.VerifyLogoutToken(r.Context(), r.PostFormValue("logout_token"))
+	if err != nil {
+		h.handleError(r, err)
+		writeError(w, http.StatusBadRequest, "invalid_request", "the logout token is invalid")
+		return
+	}
+
+	if h.onLogout == nil {
+		writeError(w, http.StatusNotImplemented, "server_error", ErrBackChannelLogoutCallbackIsEmpty.Error())
+		return
+	}
+	if err := h.onLogout(r.Context(), token); err != nil {
+		h.handleError(r, err)
+		// NOTE: If the logout failed, the RP MUST respond with 501 Not Implemented or 400 Bad Request.
+		writeError(w, http.StatusBadRequest, "logout_failed", "the logout failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *BackChannelLogoutHandler) handleError(r *http.Request, err error) {
+	if h.errorHandler != nil {
+		h.errorHandler(r, err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, errorCode, errorDescription string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": errorCode, "error_description": errorDescription})
+}
@@ -0,0 +1,245 @@
+package logout_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	randz "github.com/kunitsucom/util.go/crypto/rand"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/logout"
+)
+
+const testClientID = "test_client_id"
+
+type testProvider struct {
+	issuer     string
+	keyManager *jwk.KeyManager
+	handler    *logout.BackChannelLogoutHandler
+	loggedOut  []*logout.LogoutToken
+}
+
+func newTestProvider(t *testing.T, opts ...logout.BackChannelLogoutHandlerOption) *testProvider {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	keyManager := must.One(jwk.NewKeyManager("EC", jwa.ES256))
+	mux := http.NewServeMux()
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	metadata := &discovery.ProviderMetadata{
+		Issuer:                            s.URL,
+		JwksURI:                           s.URL + "/certs",
+		IDTokenSigningAlgValuesSupported:  []string{jwa.ES256},
+		BackchannelLogoutSupported:        true,
+		BackchannelLogoutSessionSupported: true,
+	}
+	mux.HandleFunc(discovery.ProviderMetadataURLPath, func(w http.ResponseWriter, _ *http.Request) {
+		must.Must(json.NewEncoder(w).Encode(metadata))
+	})
+	mux.Handle("/certs", keyManager)
+
+	p := &testProvider{issuer: s.URL, keyManager: keyManager}
+	opts = append([]logout.BackChannelLogoutHandlerOption{
+		logout.WithDiscoveryClient(discovery.New(ctx)),
+		logout.WithJWKClient(jwk.NewClient(ctx)),
+	}, opts...)
+	p.handler = logout.NewBackChannelLogoutHandler(ctx, s.URL+discovery.ProviderMetadataURLPath, testClientID, func(_ context.Context, token *logout.LogoutToken) error {
+		if token.SessionID == "failure_sid" {
+			return errors.New("session store is unavailable") //nolint:goerr113
+		}
+		p.loggedOut = append(p.loggedOut, token)
+		return nil
+	}, opts...)
+
+	return p
+}
+
+func (p *testProvider) claims(opts ...func(c *logout.LogoutToken)) *logout.LogoutToken {
+	c := &logout.LogoutToken{
+		ClaimsSet: *jwt.NewClaimsSet(
+			jwt.WithIssuer(p.issuer),
+			jwt.WithSubject("userID"),
+			jwt.WithAudience(testClientID),
+			jwt.WithExpirationTime(time.Now().Add(2*time.Minute)),
+			jwt.WithIssuedAt(time.Now()),
+			jwt.WithJWTID(must.One(randz.ReadString(randz.StringReader, 32))),
+		),
+		SessionID: "test_sid",
+		Events:    map[string]json.RawMessage{logout.BackChannelLogoutEvent: json.RawMessage(`{}`)},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (p *testProvider) sign(t *testing.T, claims *logout.LogoutToken, typ string) string {
+	t.Helper()
+	return must.One(jwt.NewWithClaims(jws.WithSigningKeyProvider(p.keyManager), jose.NewHeader(jwa.ES256, jose.WithType(typ)), claims))
+}
+
+func (p *testProvider) post(t *testing.T, logoutToken string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/backchannel_logout", strings.NewReader(url.Values{"logout_token": {logoutToken}}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	p.handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestLogoutToken_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(round_trip)", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &logout.LogoutToken{
+			ClaimsSet: *jwt.NewClaimsSet(jwt.WithSubject("userID")),
+			SessionID: "test_sid",
+			Events:    map[string]json.RawMessage{logout.BackChannelLogoutEvent: json.RawMessage(`{}`)},
+		}
+		b, err := json.Marshal(expected)
+		if err != nil {
+			t.Fatalf("❌: json.Marshal: err != nil: %v", err)
+		}
+		actual := new(logout.LogoutToken)
+		if err := json.Unmarshal(b, actual); err != nil {
+			t.Fatalf("❌: json.Unmarshal: err != nil: %v", err)
+		}
+		if actual.Subject != expected.Subject || actual.SessionID != expected.SessionID || string(actual.Events[logout.BackChannelLogoutEvent]) != `{}` {
+			t.Errorf("❌: expected(%+v) != actual(%+v): %s", expected, actual, b)
+		}
+	})
+}
+
+func TestBackChannelLogoutHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProvider(t)
+		logoutToken := p.sign(t, p.claims(), logout.LogoutTokenType)
+
+		w := p.post(t, logoutToken)
+		if w.Code != http.StatusOK || w.Header().Get("Cache-Control") != "no-store" {
+			t.Fatalf("❌: (*logout.BackChannelLogoutHandler).ServeHTTP: code=%d header=%v body=%s", w.Code, w.Header(), w.Body)
+		}
+		if len(p.loggedOut) != 1 || p.loggedOut[0].SessionID != "test_sid" || p.loggedOut[0].Subject != "userID" {
+			t.Errorf("❌: onLogout: loggedOut=%+v", p.loggedOut)
+		}
+
+		w = p.post(t, logoutToken)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"error":"invalid_request"`) {
+			t.Errorf("❌: (*logout.BackChannelLogoutHandler).ServeHTTP: replayed: code=%d body=%s", w.Code, w.Body)
+		}
+	})
+
+	t.Run("failure(method)", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProvider(t)
+		w := httptest.NewRecorder()
+		p.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/backchannel_logout", nil))
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("❌: (*logout.BackChannelLogoutHandler).ServeHTTP: code=%d", w.Code)
+		}
+	})
+
+	t.Run("failure(onLogout)", func(t *testing.T) {
+		t.Parallel()
+		var actual error
+		p := newTestProvider(t, logout.WithErrorHandler(func(_ *http.Request, err error) { actual = err }))
+		w := p.post(t, p.sign(t, p.claims(func(c *logout.LogoutToken) { c.SessionID = "failure_sid" }), "JWT"))
+		if w.Code != http.StatusBadRequest || strings.TrimSpace(w.Body.String()) != `{"error":"logout_failed","error_description":"the logout failed"}` {
+			t.Errorf("❌: (*logout.BackChannelLogoutHandler).ServeHTTP: code=%d body=%s", w.Code, w.Body)
+		}
+		if actual == nil || actual.Error() != "session store is unavailable" {
+			t.Errorf("❌: errorHandler: err=%v", actual)
+		}
+	})
+
+	t.Run("failure(VerifyLogoutToken)", func(t *testing.T) {
+		t.Parallel()
+		var actual error
+		p := newTestProvider(t, logout.WithErrorHandler(func(_ *http.Request, err error) { actual = err }))
+		w := p.post(t, "")
+		if w.Code != http.StatusBadRequest || strings.TrimSpace(w.Body.String()) != `{"error":"invalid_request","error_description":"the logout token is invalid"}` {
+			t.Errorf("❌: (*logout.BackChannelLogoutHandler).ServeHTTP: code=%d body=%s", w.Code, w.Body)
+		}
+		if !errors.Is(actual, logout.ErrLogoutTokenIsEmpty) {
+			t.Errorf("❌: errorHandler: err != logout.ErrLogoutTokenIsEmpty: %v", actual)
+		}
+	})
+}
+
+func TestBackChannelLogoutHandler_VerifyLogoutToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(sub_only)", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProvider(t)
+		token, err := p.handler.VerifyLogoutToken(context.Background(), p.sign(t, p.claims(func(c *logout.LogoutToken) { c.SessionID = "" }), ""))
+		if err != nil {
+			t.Fatalf("❌: (*logout.BackChannelLogoutHandler).VerifyLogoutToken: err != nil: %v", err)
+		}
+		if token.Subject != "userID" {
+			t.Errorf("❌: (*logout.BackChannelLogoutHandler).VerifyLogoutToken: sub=%s", token.Subject)
+		}
+	})
+
+	failures := []struct {
+		name   string
+		opts   []logout.BackChannelLogoutHandlerOption
+		typ    string
+		claims func(c *logout.LogoutToken)
+		expect error
+	}{
+		{name: "events(missing)", claims: func(c *logout.LogoutToken) { c.Events = nil }, expect: logout.ErrEventsClaimIsInvalid},
+		{name: "events(not_object)", claims: func(c *logout.LogoutToken) {
+			c.Events = map[string]json.RawMessage{logout.BackChannelLogoutEvent: json.RawMessage(`"logout"`)}
+		}, expect: logout.ErrEventsClaimIsInvalid},
+		{name: "nonce", claims: func(c *logout.LogoutToken) { c.PrivateClaims = jwt.PrivateClaims{"nonce": "test_nonce"} }, expect: logout.ErrNonceIsPresent},
+		{name: "sub_and_sid", claims: func(c *logout.LogoutToken) { c.Subject, c.SessionID = "", "" }, expect: logout.ErrSubjectAndSessionIDAreMissing},
+		{name: "jti", claims: func(c *logout.LogoutToken) { c.JWTID = "" }, expect: jwt.ErrRequiredClaimIsMissing},
+		{name: "aud", claims: func(c *logout.LogoutToken) { c.Audience = []string{"other_client_id"} }, expect: jwt.ErrAudienceIsNotMatch},
+		{name: "iss", claims: func(c *logout.LogoutToken) { c.Issuer = "https://evil.example.com" }, expect: jwt.ErrIssuerIsNotMatch},
+		{name: "typ", typ: "at+jwt", expect: jwt.ErrTypeIsNotMatch},
+		{name: "typ(required)", opts: []logout.BackChannelLogoutHandlerOption{logout.WithRequireLogoutTokenType()}, typ: "JWT", expect: jwt.ErrTypeIsNotMatch},
+	}
+	for _, tt := range failures {
+		t.Run("failure("+tt.name+")", func(t *testing.T) {
+			t.Parallel()
+			p := newTestProvider(t, tt.opts...)
+			var opts []func(c *logout.LogoutToken)
+			if tt.claims != nil {
+				opts = append(opts, tt.claims)
+			}
+			if _, err := p.handler.VerifyLogoutToken(context.Background(), p.sign(t, p.claims(opts...), tt.typ)); !errors.Is(err, tt.expect) {
+				t.Errorf("❌: (*logout.BackChannelLogoutHandler).VerifyLogoutToken: err != %v: %v", tt.expect, err)
+			}
+		})
+	}
+
+	t.Run("failure(empty)", func(t *testing.T) {
+		t.Parallel()
+		p := newTestProvider(t)
+		if _, err := p.handler.VerifyLogoutToken(context.Background(), ""); !errors.Is(err, logout.ErrLogoutTokenIsEmpty) {
+			t.Errorf("❌: (*logout.BackChannelLogoutHandler).VerifyLogoutToken: err != logout.ErrLogoutTokenIsEmpty: %v", err)
+		}
+	})
+}
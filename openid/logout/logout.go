@@ -0,0 +1,117 @@
+package logout
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// - ref. OpenID Connect RP-Initiated Logout 1.0 https://openid.net/specs/openid-connect-rpinitiated-1_0.html
+
+var (
+	ErrParameterIsEmpty               = errors.New("openid: Logout Request: parameter is empty")
+	ErrIDTokenHintOrClientIDIsMissing = errors.New("openid: Logout Request: id_token_hint or client_id is required with post_logout_redirect_uri")
+)
+
+type option struct {
+	idTokenHint           string
+	logoutHint            string
+	clientID              string
+	postLogoutRedirectURI string
+	state                 string
+	uiLocales             []string
+}
+
+type Option func(*option)
+
+// WithIDTokenHint
+//
+//   - id_token_hint: ref. https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+func WithIDTokenHint(idTokenHint string) Option {
+	return func(o *option) { o.idTokenHint = idTokenHint }
+}
+
+// WithLogoutHint
+//
+//   - logout_hint: ref. https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+func WithLogoutHint(logoutHint string) Option {
+	return func(o *option) { o.logoutHint = logoutHint }
+}
+
+// WithClientID
+//
+//   - client_id: ref. https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+func WithClientID(clientID string) Option {
+	return func(o *option) { o.clientID = clientID }
+}
+
+// WithPostLogoutRedirectURI
+//
+//   - post_logout_redirect_uri: ref. https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+func WithPostLogoutRedirectURI(postLogoutRedirectURI string) Option {
+	return func(o *option) { o.postLogoutRedirectURI = postLogoutRedirectURI }
+}
+
+// WithState
+//
+//   - state: ref. https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+func WithState(state string) Option {
+	return func(o *option) { o.state = state }
+}
+
+// WithUILocales
+//
+//   - ui_locales: ref. https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+func WithUILocales(uiLocales []string) Option {
+	return func(o *option) { o.uiLocales = uiLocales }
+}
+
+// NewRequest returns URL for redirect response to User-Agent, to request the OP to log out the End-User.
+//
+//   - end_session_endpoint: ref. https://openid.net/specs/openid-connect-rpinitiated-1_0.html#OPMetadata
+//
+// post_logout_redirect_uri requires id_token_hint or client_id, because the OP needs to know the client which registered it.
+func NewRequest(endSessionEndpoint string, opts ...Option) (*url.URL, error) {
+	o := new(option)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if endSessionEndpoint == "" {
+		return nil, fmt.Errorf("end_session_endpoint: %w", ErrParameterIsEmpty)
+	}
+
+	u, err := url.Parse(endSessionEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("url.Parse: %w", err)
+	}
+
+	if o.postLogoutRedirectURI != "" && o.idTokenHint == "" && o.clientID == "" {
+		return nil, ErrIDTokenHintOrClientIDIsMissing
+	}
+
+	query := u.Query()
+	if o.idTokenHint != "" {
+		query.Set("id_token_hint", o.idTokenHint)
+	}
+	if o.logoutHint != "" {
+		query.Set("logout_hint", o.logoutHint)
+	}
+	if o.clientID != "" {
+		query.Set("client_id", o.clientID)
+	}
+	if o.postLogoutRedirectURI != "" {
+		query.Set("post_logout_redirect_uri", o.postLogoutRedirectURI)
+	}
+	if o.state != "" {
+		query.Set("state", o.state)
+	}
+	if len(o.uiLocales) != 0 {
+		query.Set("ui_locales", strings.Join(o.uiLocales, " "))
+	}
+
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	return u, nil
+}
@@ -0,0 +1,52 @@
+package logout_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kunitsucom/util.go/openid/logout"
+)
+
+func TestNewRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success()", func(t *testing.T) {
+		t.Parallel()
+		u, err := logout.NewRequest("https://op.example.com/logout?foo=bar",
+			logout.WithIDTokenHint("test_id_token"),
+			logout.WithLogoutHint("user@example.com"),
+			logout.WithClientID("test_client_id"),
+			logout.WithPostLogoutRedirectURI("https://rp.example.com/logged_out"),
+			logout.WithState("test_state"),
+			logout.WithUILocales([]string{"ja-JP", "en"}),
+		)
+		if err != nil {
+			t.Fatalf("❌: logout.NewRequest: err != nil: %v", err)
+		}
+		const expect = "https://op.example.com/logout?client_id=test_client_id&foo=bar&id_token_hint=test_id_token&logout_hint=user%40example.com&post_logout_redirect_uri=https%3A%2F%2Frp.example.com%2Flogged_out&state=test_state&ui_locales=ja-JP%20en"
+		if actual := u.String(); actual != expect {
+			t.Errorf("❌: logout.NewRequest: expect(%s) != actual(%s)", expect, actual)
+		}
+	})
+
+	t.Run("failure(end_session_endpoint)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := logout.NewRequest(""); !errors.Is(err, logout.ErrParameterIsEmpty) {
+			t.Errorf("❌: logout.NewRequest: err != logout.ErrParameterIsEmpty: %v", err)
+		}
+	})
+
+	t.Run("failure(url.Parse)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := logout.NewRequest("https://op.example.com/%%%"); err == nil {
+			t.Errorf("❌: logout.NewRequest: err == nil")
+		}
+	})
+
+	t.Run("failure(post_logout_redirect_uri)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := logout.NewRequest("https://op.example.com/logout", logout.WithPostLogoutRedirectURI("https://rp.example.com/logged_out")); !errors.Is(err, logout.ErrIDTokenHintOrClientIDIsMissing) {
+			t.Errorf("❌: logout.NewRequest: err != logout.ErrIDTokenHintOrClientIDIsMissing: %v", err)
+		}
+	})
+}
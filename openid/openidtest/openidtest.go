@@ -29,6 +29,9 @@ const (
 
 var ErrInvalidPublicKey = errors.New("openidtest: invalid public key")
 
+// StartOpenIDProvider starts a server which serves only the provider metadata and the JWK Set.
+//
+// Deprecated: The server is never closed. Use NewProvider, which returns the cleanup func.
 func StartOpenIDProvider() (
 	addr net.Addr,
 	metadata *discovery.ProviderMetadata,
@@ -14,7 +14,7 @@ import (
 func TestStartOpenIDProvider(t *testing.T) {
 	t.Parallel()
 
-	addr, metadata, _ := openidtest.StartOpenIDProvider()
+	addr, metadata, _ := openidtest.StartOpenIDProvider() //nolint:staticcheck
 
 	t.Logf("📝: start open id provider: %s", addr)
 
@@ -0,0 +1,614 @@
+package openidtest
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	randz "github.com/kunitsucom/util.go/crypto/rand"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/id_token"
+	"github.com/kunitsucom/util.go/openid/pkce"
+	slicez "github.com/kunitsucom/util.go/slices"
+)
+
+const (
+	DefaultClientID     = "test_client_id"
+	DefaultClientSecret = "test_client_secret"
+	DefaultSubject      = "test_user"
+
+	AuthorizationEndpointPath = "/auth"
+	TokenEndpointPath         = "/token"
+	UserInfoEndpointPath      = "/userinfo"
+	JWKSetURLPath             = "/certs"
+	EndSessionEndpointPath    = "/logout"
+)
+
+// User is an End-User of Provider.
+type User struct {
+	Subject string
+	// Claims is the claims returned by the UserInfo Endpoint, e.g. "email", "name".
+	Claims map[string]any
+}
+
+// Client is an OAuth 2.0 client registered in Provider.
+// If Secret is empty, the client is a public client, which must use PKCE.
+type Client struct {
+	ID           string
+	Secret       string
+	RedirectURIs []string
+}
+
+type authorizationCode struct {
+	clientID            string
+	redirectURI         string
+	scope               string
+	nonce               string
+	codeChallenge       string
+	codeChallengeMethod string
+	subject             string
+	sessionID           string
+	authTime            time.Time
+	expiresAt           time.Time
+}
+
+type grant struct {
+	clientID  string
+	scope     string
+	subject   string
+	sessionID string
+	authTime  time.Time
+	expiresAt time.Time
+}
+
+type injectedError struct {
+	statusCode int
+	errorCode  string
+}
+
+// Provider is an in-process mock OpenID Provider for end-to-end tests of the login flows.
+//
+// Provider serves:
+//
+//   - /.well-known/openid-configuration: the provider metadata.
+//   - /auth: the authorization endpoint. It authenticates the user of "login_hint" (or the first user) and consents automatically, then redirects with "code" and "state".
+//   - /token: the token endpoint. It supports authorization_code (with PKCE), refresh_token and client_credentials grants.
+//   - /userinfo: the UserInfo endpoint.
+//   - /certs: the JWK Set of the signing keys.
+//   - /logout: the end session endpoint.
+//
+// Example:
+//
+//	provider, cleanup := openidtest.NewProvider(
+//		openidtest.WithUser(openidtest.User{Subject: "user", Claims: map[string]any{"email": "user@example.com"}}),
+//	)
+//	defer cleanup()
+//
+//	metadata := provider.Metadata()
+type Provider struct {
+	server         *httptest.Server
+	keyManager     *jwk.KeyManager
+	signingKeyType string
+	signingAlg     string
+	now            func() time.Time
+	tokenTTL       time.Duration
+	codeTTL        time.Duration
+	metadata       *discovery.ProviderMetadata
+
+	mu             sync.Mutex
+	users          []*User
+	clients        map[string]*Client
+	codes          map[string]*authorizationCode
+	accessTokens   map[string]*grant
+	refreshTokens  map[string]*grant
+	injectedErrors map[string]*injectedError
+}
+
+type ProviderOption func(*Provider)
+
+// WithNowFunc sets the clock of Provider, which is used for "iat", "exp", "auth_time" and the expiration of codes and tokens.
+func WithNowFunc(now func() time.Time) ProviderOption {
+	return func(p *Provider) {
+		p.now = now
+	}
+}
+
+// WithUser registers user. If no user is registered, the user of DefaultSubject is registered.
+func WithUser(user User) ProviderOption {
+	return func(p *Provider) {
+		p.users = append(p.users, &user)
+	}
+}
+
+// WithClient registers client. If no client is registered, the client of DefaultClientID and DefaultClientSecret is registered, which accepts any redirect_uri.
+func WithClient(client Client) ProviderOption {
+	return func(p *Provider) {
+		p.clients[client.ID] = &client
+	}
+}
+
+// WithSigningKey sets the key type and the algorithm of the ID Token signing keys. Default is "RSA" and "RS256".
+func WithSigningKey(kty, alg string) ProviderOption {
+	return func(p *Provider) {
+		p.signingKeyType, p.signingAlg = kty, alg
+	}
+}
+
+// WithTokenTTL sets the lifetime of ID Tokens and access tokens.
+func WithTokenTTL(ttl time.Duration) ProviderOption {
+	return func(p *Provider) {
+		p.tokenTTL = ttl
+	}
+}
+
+// NewProvider starts a new Provider, and returns it with the cleanup func which stops it.
+func NewProvider(opts ...ProviderOption) (provider *Provider, cleanup func()) {
+	const (
+		defaultTokenTTL = 1 * time.Hour
+		defaultCodeTTL  = 1 * time.Minute
+	)
+
+	p := &Provider{
+		signingKeyType: "RSA",
+		signingAlg:     jwa.RS256,
+		now:            time.Now,
+		tokenTTL:       defaultTokenTTL,
+		codeTTL:        defaultCodeTTL,
+		clients:        make(map[string]*Client),
+		codes:          make(map[string]*authorizationCode),
+		accessTokens:   make(map[string]*grant),
+		refreshTokens:  make(map[string]*grant),
+		injectedErrors: make(map[string]*injectedError),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if len(p.users) == 0 {
+		p.users = append(p.users, &User{Subject: DefaultSubject})
+	}
+	if len(p.clients) == 0 {
+		p.clients[DefaultClientID] = &Client{ID: DefaultClientID, Secret: DefaultClientSecret}
+	}
+
+	p.keyManager = must.One(jwk.NewKeyManager(p.signingKeyType, p.signingAlg, jwk.WithRotationInterval(0), jwk.WithNowFunc(p.now)))
+
+	mux := http.NewServeMux()
+	p.server = httptest.NewServer(mux)
+	iss := p.server.URL
+
+	p.metadata = &discovery.ProviderMetadata{
+		Issuer:                            iss,
+		AuthorizationEndpoint:             iss + AuthorizationEndpointPath,
+		TokenEndpoint:                     iss + TokenEndpointPath,
+		UserInfoEndpoint:                  iss + UserInfoEndpointPath,
+		JwksURI:                           iss + JWKSetURLPath,
+		EndSessionEndpoint:                iss + EndSessionEndpointPath,
+		ScopesSupported:                   []string{"openid", "profile", "email", "offline_access"},
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "refresh_token", "client_credentials"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{p.signingAlg},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{pkce.CodeChallengeMethodS256.String(), pkce.CodeChallengeMethodPlainShouldNotBeUsed.String()},
+	}
+
+	mux.HandleFunc(discovery.ProviderMetadataURLPath, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, p.metadata)
+	})
+	mux.Handle(JWKSetURLPath, p.keyManager)
+	mux.HandleFunc(AuthorizationEndpointPath, p.handleAuthorization)
+	mux.HandleFunc(TokenEndpointPath, p.handleToken)
+	mux.HandleFunc(UserInfoEndpointPath, p.handleUserInfo)
+	mux.HandleFunc(EndSessionEndpointPath, p.handleEndSession)
+
+	return p, p.server.Close
+}
+
+// Issuer returns the issuer identifier, which is the base URL of Provider.
+func (p *Provider) Issuer() string { return p.server.URL }
+
+// ProviderMetadataURL returns the URL of the provider metadata.
+func (p *Provider) ProviderMetadataURL() string {
+	return p.server.URL + discovery.ProviderMetadataURLPath
+}
+
+// Metadata returns a copy of the provider metadata.
+func (p *Provider) Metadata() *discovery.ProviderMetadata {
+	metadata := *p.metadata
+	return &metadata
+}
+
+// RotateKeys rotates the ID Token signing keys.
+func (p *Provider) RotateKeys() error {
+	return p.keyManager.Rotate() //nolint:wrapcheck
+}
+
+// InjectError makes the next request to path, e.g. TokenEndpointPath, fail with statusCode and the OAuth 2.0 error code.
+// The authorization endpoint redirects with the error code instead.
+func (p *Provider) InjectError(path string, statusCode int, errorCode string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.injectedErrors[path] = &injectedError{statusCode: statusCode, errorCode: errorCode}
+}
+
+func (p *Provider) popInjectedError(path string) *injectedError {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e := p.injectedErrors[path]
+	delete(p.injectedErrors, path)
+	return e
+}
+
+// NewIDToken returns a new ID Token signed with the active key, for tests which need an ID Token without the login flow.
+func (p *Provider) NewIDToken(clientID, subject string, opts ...jwt.ClaimsSetOption) (string, error) {
+	now := p.now()
+	return p.newIDToken(clientID, subject, now, append([]jwt.ClaimsSetOption{jwt.WithPrivateClaim("auth_time", now.Unix())}, opts...)...)
+}
+
+func (p *Provider) newIDToken(clientID, subject string, now time.Time, opts ...jwt.ClaimsSetOption) (string, error) {
+	opts = append([]jwt.ClaimsSetOption{
+		jwt.WithIssuer(p.Issuer()),
+		jwt.WithSubject(subject),
+		jwt.WithAudience(clientID),
+		jwt.WithIssuedAt(now),
+		jwt.WithExpirationTime(now.Add(p.tokenTTL)),
+		jwt.WithPrivateClaim("azp", clientID),
+	}, opts...)
+
+	return jwt.New( //nolint:wrapcheck
+		jws.WithSigningKeyProvider(p.keyManager),
+		jose.NewHeader(p.signingAlg, jose.WithType("JWT")),
+		jwt.NewClaimsSet(opts...),
+	)
+}
+
+func (p *Provider) user(subject string) *User {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, u := range p.users {
+		if u.Subject == subject {
+			return u
+		}
+	}
+	return nil
+}
+
+func (p *Provider) handleAuthorization(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if r.Method == http.MethodPost {
+		_ = r.ParseForm()
+		q = r.Form
+	}
+
+	p.mu.Lock()
+	client := p.clients[q.Get("client_id")]
+	p.mu.Unlock()
+	redirectURI := q.Get("redirect_uri")
+	if client == nil || redirectURI == "" || (len(client.RedirectURIs) > 0 && !slicez.Contains(client.RedirectURIs, redirectURI)) {
+		// NOTE: MUST NOT redirect to the invalid redirect_uri.
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid client_id or redirect_uri")
+		return
+	}
+
+	redirect := func(params url.Values) {
+		params.Set("state", q.Get("state"))
+		params.Set("iss", p.Issuer())
+		u := must.One(url.Parse(redirectURI))
+		query := u.Query()
+		for k, v := range params {
+			query[k] = v
+		}
+		u.RawQuery = query.Encode()
+		http.Redirect(w, r, u.String(), http.StatusFound)
+	}
+
+	if e := p.popInjectedError(AuthorizationEndpointPath); e != nil {
+		redirect(url.Values{"error": {e.errorCode}})
+		return
+	}
+	if q.Get("response_type") != "code" {
+		redirect(url.Values{"error": {"unsupported_response_type"}})
+		return
+	}
+	if !slicez.Contains(strings.Fields(q.Get("scope")), "openid") {
+		redirect(url.Values{"error": {"invalid_scope"}})
+		return
+	}
+	if client.Secret == "" && q.Get("code_challenge") == "" {
+		redirect(url.Values{"error": {"invalid_request"}, "error_description": {"public client must use PKCE"}})
+		return
+	}
+
+	subject := q.Get("login_hint")
+	if subject == "" {
+		p.mu.Lock()
+		subject = p.users[0].Subject
+		p.mu.Unlock()
+	}
+	if p.user(subject) == nil {
+		redirect(url.Values{"error": {"login_required"}})
+		return
+	}
+
+	now := p.now()
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = &authorizationCode{
+		clientID:            client.ID,
+		redirectURI:         redirectURI,
+		scope:               q.Get("scope"),
+		nonce:               q.Get("nonce"),
+		codeChallenge:       q.Get("code_challenge"),
+		codeChallengeMethod: q.Get("code_challenge_method"),
+		subject:             subject,
+		sessionID:           randomString(),
+		authTime:            now,
+		expiresAt:           now.Add(p.codeTTL),
+	}
+	p.mu.Unlock()
+
+	redirect(url.Values{"code": {code}})
+}
+
+func (p *Provider) authenticateClient(r *http.Request) (*Client, bool) {
+	clientID, clientSecret, basic := r.BasicAuth()
+	if basic {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+
+	p.mu.Lock()
+	client := p.clients[clientID]
+	p.mu.Unlock()
+	if client == nil {
+		return nil, false
+	}
+	if subtle.ConstantTimeCompare([]byte(client.Secret), []byte(clientSecret)) != 1 {
+		return nil, false
+	}
+	return client, true
+}
+
+//nolint:cyclop,funlen
+func (p *Provider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request", "method must be POST")
+		return
+	}
+	if e := p.popInjectedError(TokenEndpointPath); e != nil {
+		writeError(w, e.statusCode, e.errorCode, "injected error")
+		return
+	}
+
+	client, ok := p.authenticateClient(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+		writeError(w, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+		return
+	}
+
+	now := p.now()
+	var g *grant
+	var nonce string
+	switch r.PostFormValue("grant_type") {
+	case "authorization_code":
+		p.mu.Lock()
+		c := p.codes[r.PostFormValue("code")]
+		delete(p.codes, r.PostFormValue("code")) // NOTE: authorization code is one-time use.
+		p.mu.Unlock()
+		switch {
+		case c == nil, !now.Before(c.expiresAt), c.clientID != client.ID:
+			writeError(w, http.StatusBadRequest, "invalid_grant", "code is invalid or expired")
+			return
+		case c.redirectURI != r.PostFormValue("redirect_uri"):
+			writeError(w, http.StatusBadRequest, "invalid_grant", "redirect_uri is not match")
+			return
+		case c.codeChallenge != "" && pkce.CodeVerifier(r.PostFormValue("code_verifier")).Encode(pkce.CodeChallengeMethod(c.codeChallengeMethod)) != c.codeChallenge:
+			writeError(w, http.StatusBadRequest, "invalid_grant", "code_verifier is not match")
+			return
+		}
+		g = &grant{clientID: client.ID, scope: c.scope, subject: c.subject, sessionID: c.sessionID, authTime: c.authTime}
+		nonce = c.nonce
+	case "refresh_token":
+		p.mu.Lock()
+		old := p.refreshTokens[r.PostFormValue("refresh_token")]
+		delete(p.refreshTokens, r.PostFormValue("refresh_token")) // NOTE: refresh token rotation.
+		p.mu.Unlock()
+		if old == nil || old.clientID != client.ID {
+			writeError(w, http.StatusBadRequest, "invalid_grant", "refresh_token is invalid")
+			return
+		}
+		g = &grant{clientID: client.ID, scope: old.scope, subject: old.subject, sessionID: old.sessionID, authTime: old.authTime}
+	case "client_credentials":
+		if client.Secret == "" {
+			writeError(w, http.StatusBadRequest, "unauthorized_client", "public client cannot use client_credentials")
+			return
+		}
+		g = &grant{clientID: client.ID, scope: r.PostFormValue("scope")}
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type", "grant_type is not supported")
+		return
+	}
+	g.expiresAt = now.Add(p.tokenTTL)
+
+	accessToken := randomString()
+	resp := map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int64(p.tokenTTL / time.Second),
+	}
+	if g.scope != "" {
+		resp["scope"] = g.scope
+	}
+	p.mu.Lock()
+	p.accessTokens[accessToken] = g
+	if g.subject != "" {
+		refreshToken := randomString()
+		p.refreshTokens[refreshToken] = g
+		resp["refresh_token"] = refreshToken
+	}
+	p.mu.Unlock()
+
+	if slicez.Contains(strings.Fields(g.scope), "openid") {
+		opts := []jwt.ClaimsSetOption{
+			jwt.WithPrivateClaim("auth_time", g.authTime.Unix()),
+			jwt.WithPrivateClaim("sid", g.sessionID),
+			jwt.WithPrivateClaim("at_hash", must.One(id_token.LeftHalfHash(p.signingAlg, accessToken))),
+		}
+		if nonce != "" {
+			opts = append(opts, jwt.WithPrivateClaim("nonce", nonce))
+		}
+		idToken, err := p.newIDToken(client.ID, g.subject, now, opts...)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+			return
+		}
+		resp["id_token"] = idToken
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (p *Provider) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	if e := p.popInjectedError(UserInfoEndpointPath); e != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="`+e.errorCode+`"`)
+		writeError(w, e.statusCode, e.errorCode, "injected error")
+		return
+	}
+
+	accessToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	p.mu.Lock()
+	g := p.accessTokens[accessToken]
+	p.mu.Unlock()
+	if !ok || g == nil || g.subject == "" || !p.now().Before(g.expiresAt) {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	user := p.user(g.subject)
+	if user == nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	claims := make(map[string]any, len(user.Claims)+1)
+	for k, v := range user.Claims {
+		claims[k] = v
+	}
+	claims["sub"] = user.Subject
+	writeJSON(w, http.StatusOK, claims)
+}
+
+func (p *Provider) handleEndSession(w http.ResponseWriter, r *http.Request) {
+	if e := p.popInjectedError(EndSessionEndpointPath); e != nil {
+		writeError(w, e.statusCode, e.errorCode, "injected error")
+		return
+	}
+
+	_ = r.ParseForm()
+
+	// NOTE: revoke all the tokens of the session of id_token_hint.
+	if sid := p.sessionIDOf(r.Form.Get("id_token_hint")); sid != "" {
+		p.mu.Lock()
+		for token, g := range p.accessTokens {
+			if g.sessionID == sid {
+				delete(p.accessTokens, token)
+			}
+		}
+		for token, g := range p.refreshTokens {
+			if g.sessionID == sid {
+				delete(p.refreshTokens, token)
+			}
+		}
+		p.mu.Unlock()
+	}
+
+	if u := r.Form.Get("post_logout_redirect_uri"); u != "" {
+		redirectURI, err := url.Parse(u)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "post_logout_redirect_uri is invalid")
+			return
+		}
+		if state := r.Form.Get("state"); state != "" {
+			query := redirectURI.Query()
+			query.Set("state", state)
+			redirectURI.RawQuery = query.Encode()
+		}
+		http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("logged out"))
+}
+
+// sessionIDOf returns "sid" of idToken if it is signed by Provider. Expired ID Tokens are accepted as id_token_hint.
+func (p *Provider) sessionIDOf(idToken string) string {
+	if idToken == "" {
+		return ""
+	}
+
+	jwks, err := p.keyManager.JWKSet()
+	if err != nil {
+		return ""
+	}
+	headerEncoded, payloadEncoded, _, err := jws.Parse(idToken)
+	if err != nil {
+		return ""
+	}
+	h := new(jose.Header)
+	if err := h.Decode(headerEncoded); err != nil {
+		return ""
+	}
+	key, err := jwks.GetJSONWebKey(h.KeyID)
+	if err != nil {
+		return ""
+	}
+	pub, err := key.DecodePublicKey()
+	if err != nil {
+		return ""
+	}
+	if _, err := jws.Verify(jws.UseKey(pub), idToken); err != nil {
+		return ""
+	}
+	cs := new(jwt.ClaimsSet)
+	if err := cs.Decode(payloadEncoded); err != nil || cs.Issuer != p.Issuer() {
+		return ""
+	}
+	sid, _ := cs.PrivateClaims["sid"].(string)
+	return sid
+}
+
+func randomString() string {
+	const length = 32
+	return must.One(randz.ReadString(randz.StringReader, length))
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+//nolint:unparam
+func writeError(w http.ResponseWriter, code int, errorCode, errorDescription string) {
+	writeJSON(w, code, map[string]string{"error": errorCode, "error_description": errorDescription})
+}
@@ -0,0 +1,190 @@
+package openidtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/authentication_request"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/id_token"
+	"github.com/kunitsucom/util.go/openid/openidtest"
+	"github.com/kunitsucom/util.go/openid/pkce"
+	"github.com/kunitsucom/util.go/openid/token"
+	"github.com/kunitsucom/util.go/openid/userinfo"
+	timez "github.com/kunitsucom/util.go/time"
+)
+
+const testRedirectURI = "https://rp.example.com/callback"
+
+//nolint:gochecknoglobals
+var noRedirectClient = &http.Client{
+	CheckRedirect: func(_ *http.Request, _ []*http.Request) error { return http.ErrUseLastResponse },
+}
+
+// authorize requests the authorization endpoint, and returns the query of the redirect URI.
+func authorize(t *testing.T, provider *openidtest.Provider, opts ...authentication_request.Option) url.Values {
+	t.Helper()
+
+	u := must.One(authentication_request.New(provider.Metadata().AuthorizationEndpoint, []string{"openid", "email"}, "code", openidtest.DefaultClientID, testRedirectURI, "test_state", opts...))
+	resp := must.One(noRedirectClient.Get(u.String()))
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("❌: GET %s: code=%d", u, resp.StatusCode)
+	}
+	location := must.One(url.Parse(resp.Header.Get("Location")))
+	return location.Query()
+}
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(authorization_code,pkce,userinfo,refresh,logout)", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		provider, cleanup := openidtest.NewProvider(openidtest.WithUser(openidtest.User{Subject: "user", Claims: map[string]any{"email": "user@example.com"}}))
+		defer cleanup()
+
+		codeVerifier := must.One(pkce.CreateCodeVerifier(43))
+		query := authorize(t, provider,
+			authentication_request.WithNonce("test_nonce"),
+			authentication_request.WithCodeChallengeForPKCE(codeVerifier, pkce.CodeChallengeMethodS256),
+		)
+		if query.Get("state") != "test_state" || query.Get("iss") != provider.Issuer() || query.Get("code") == "" {
+			t.Fatalf("❌: redirect: query=%v", query)
+		}
+
+		tokenClient := must.One(token.NewClient(provider.Metadata(), openidtest.DefaultClientID, token.WithClientSecretBasic(openidtest.DefaultClientSecret)))
+		if _, err := tokenClient.ExchangeAuthorizationCode(ctx, query.Get("code"), testRedirectURI, token.WithCodeVerifier("invalid_code_verifier_invalid_code_verifier")); err == nil {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err == nil")
+		}
+
+		query = authorize(t, provider,
+			authentication_request.WithNonce("test_nonce"),
+			authentication_request.WithCodeChallengeForPKCE(codeVerifier, pkce.CodeChallengeMethodS256),
+		)
+		tokenResponse, err := tokenClient.ExchangeAuthorizationCode(ctx, query.Get("code"), testRedirectURI, token.WithCodeVerifier(codeVerifier))
+		if err != nil {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err != nil: %v", err)
+		}
+
+		verifier := id_token.NewVerifier(provider.ProviderMetadataURL(), openidtest.DefaultClientID,
+			id_token.WithDiscoveryClient(discovery.New(ctx)),
+			id_token.WithJWKClient(jwk.NewClient(ctx)),
+		)
+		claims, err := verifier.Verify(ctx, tokenResponse.IDToken,
+			id_token.VerifyNonce("test_nonce"),
+			id_token.VerifyAccessToken(tokenResponse.AccessToken),
+			id_token.VerifyMaxAge(time.Minute),
+		)
+		if err != nil {
+			t.Fatalf("❌: (*id_token.Verifier).Verify: err != nil: %v", err)
+		}
+		if claims.Subject != "user" {
+			t.Errorf("❌: (*id_token.Verifier).Verify: sub=%s", claims.Subject)
+		}
+
+		userinfoClient := must.One(userinfo.NewClient(provider.Metadata(), openidtest.DefaultClientID))
+		userInfo, err := userinfoClient.GetUserInfo(ctx, tokenResponse.AccessToken, claims.Subject)
+		if err != nil {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != nil: %v", err)
+		}
+		if userInfo.Email != "user@example.com" {
+			t.Errorf("❌: (*userinfo.Client).GetUserInfo: email=%s", userInfo.Email)
+		}
+
+		refreshed, err := tokenClient.Refresh(ctx, tokenResponse.RefreshToken)
+		if err != nil {
+			t.Fatalf("❌: (*token.Client).Refresh: err != nil: %v", err)
+		}
+		if _, err := tokenClient.Refresh(ctx, tokenResponse.RefreshToken); err == nil {
+			t.Errorf("❌: (*token.Client).Refresh: reused refresh token: err == nil")
+		}
+
+		logoutURL := provider.Metadata().EndSessionEndpoint + "?" + url.Values{"id_token_hint": {tokenResponse.IDToken}}.Encode()
+		resp := must.One(http.Get(logoutURL))
+		resp.Body.Close()
+		if _, err := userinfoClient.GetUserInfo(ctx, refreshed.AccessToken, claims.Subject); !errors.Is(err, userinfo.ErrResponseIsNotOK) {
+			t.Errorf("❌: (*userinfo.Client).GetUserInfo: after logout: err != userinfo.ErrResponseIsNotOK: %v", err)
+		}
+	})
+
+	t.Run("success(key_rotation,clock)", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		ctx, cancel := context.WithCancel(timez.WithContext(context.Background(), now))
+		defer cancel()
+
+		provider, cleanup := openidtest.NewProvider(openidtest.WithNowFunc(func() time.Time { return now }))
+		defer cleanup()
+
+		verifier := id_token.NewVerifier(provider.ProviderMetadataURL(), openidtest.DefaultClientID,
+			id_token.WithDiscoveryClient(discovery.New(ctx)),
+			id_token.WithJWKClient(jwk.NewClient(ctx, jwk.WithRefetchInterval(0))),
+		)
+		for range 3 {
+			idToken := must.One(provider.NewIDToken(openidtest.DefaultClientID, openidtest.DefaultSubject))
+			claims, err := verifier.Verify(ctx, idToken)
+			if err != nil {
+				t.Fatalf("❌: (*id_token.Verifier).Verify: err != nil: %v", err)
+			}
+			if claims.IssuedAt != now.Unix() {
+				t.Errorf("❌: (*id_token.Verifier).Verify: iat=%d", claims.IssuedAt)
+			}
+			must.Must(provider.RotateKeys())
+		}
+
+		if _, err := verifier.Verify(context.Background(), must.One(provider.NewIDToken(openidtest.DefaultClientID, openidtest.DefaultSubject))); !errors.Is(err, jwt.ErrTokenIsExpired) {
+			t.Errorf("❌: (*id_token.Verifier).Verify: err != jwt.ErrTokenIsExpired: %v", err)
+		}
+	})
+
+	t.Run("failure(injected_error)", func(t *testing.T) {
+		t.Parallel()
+
+		provider, cleanup := openidtest.NewProvider()
+		defer cleanup()
+
+		provider.InjectError(openidtest.AuthorizationEndpointPath, http.StatusFound, "access_denied")
+		if query := authorize(t, provider); query.Get("error") != "access_denied" || query.Get("state") != "test_state" {
+			t.Errorf("❌: redirect: query=%v", query)
+		}
+
+		query := authorize(t, provider)
+		provider.InjectError(openidtest.TokenEndpointPath, http.StatusServiceUnavailable, "temporarily_unavailable")
+		tokenClient := must.One(token.NewClient(provider.Metadata(), openidtest.DefaultClientID, token.WithClientSecretPost(openidtest.DefaultClientSecret)))
+		_, err := tokenClient.ExchangeAuthorizationCode(context.Background(), query.Get("code"), testRedirectURI)
+		var e *token.ErrorResponse
+		if !errors.As(err, &e) || e.ErrorCode != "temporarily_unavailable" {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err != temporarily_unavailable: %v", err)
+		}
+		if _, err := tokenClient.ExchangeAuthorizationCode(context.Background(), query.Get("code"), testRedirectURI); err != nil {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err != nil: %v", err)
+		}
+	})
+
+	t.Run("failure(invalid_client)", func(t *testing.T) {
+		t.Parallel()
+
+		provider, cleanup := openidtest.NewProvider()
+		defer cleanup()
+
+		query := authorize(t, provider)
+		tokenClient := must.One(token.NewClient(provider.Metadata(), openidtest.DefaultClientID, token.WithClientSecretBasic("invalid_secret")))
+		_, err := tokenClient.ExchangeAuthorizationCode(context.Background(), query.Get("code"), testRedirectURI)
+		var e *token.ErrorResponse
+		if !errors.As(err, &e) || e.ErrorCode != "invalid_client" {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err != invalid_client: %v", err)
+		}
+	})
+}
@@ -0,0 +1,421 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	randz "github.com/kunitsucom/util.go/crypto/rand"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/pkce"
+	slicez "github.com/kunitsucom/util.go/slices"
+	timez "github.com/kunitsucom/util.go/time"
+)
+
+// - ref. Token Endpoint https://openid.net/specs/openid-connect-core-1_0.html#TokenEndpoint
+// - ref. Client Authentication https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication
+// - ref. OAuth 2.0 https://www.rfc-editor.org/rfc/rfc6749
+
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodPrivateKeyJWT     = "private_key_jwt"
+	AuthMethodNone              = "none"
+
+	ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+var (
+	ErrTokenEndpointIsEmpty       = errors.New("token: token endpoint is empty")
+	ErrAuthMethodIsNotSupported   = errors.New("token: client authentication method is not supported by the provider")
+	ErrResponseIsNotOK            = errors.New("token: response is not OK")
+	ErrAccessTokenIsEmpty         = errors.New("token: access token is empty")
+	ErrContentTypeIsNotJSON       = errors.New("token: content type is not application/json")
+	ErrClientAssertionKeyIsNotSet = errors.New("token: client assertion key is not set")
+	ErrResponseIsTooLarge         = errors.New("token: response is too large")
+)
+
+// ErrorResponse is the error returned when the token endpoint responds with an OAuth 2.0 error.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6749#section-5.2
+//
+// Example:
+//
+//	var e *token.ErrorResponse
+//	if errors.As(err, &e) && e.ErrorCode == "invalid_grant" {
+//		// re-authenticate the End-User
+//	}
+//
+//nolint:errname,tagliatelle
+type ErrorResponse struct {
+	StatusCode       int    `json:"-"`
+	ErrorCode        string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+	ErrorURI         string `json:"error_uri,omitempty"`
+}
+
+var _ interface{ Error() string } = (*ErrorResponse)(nil)
+
+func (e *ErrorResponse) Error() string {
+	if e.ErrorDescription == "" {
+		return fmt.Sprintf("token: code=%d error=%s", e.StatusCode, e.ErrorCode)
+	}
+	return fmt.Sprintf("token: code=%d error=%s error_description=%q", e.StatusCode, e.ErrorCode, e.ErrorDescription)
+}
+
+// Response is the successful response of the token endpoint.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6749#section-5.1
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#TokenResponse
+//
+//nolint:tagliatelle
+type Response struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	// IDToken is the raw ID Token. It should be verified by id_token.Verifier.
+	IDToken string `json:"id_token,omitempty"`
+	// Expiry is the time when the access token expires, computed from "expires_in" and the time of the response.
+	// It is zero if "expires_in" is not present.
+	Expiry time.Time `json:"-"`
+	// Raw is the raw response body, to read the parameters which are not defined above.
+	Raw json.RawMessage `json:"-"`
+}
+
+// Client is the client of the token endpoint.
+//
+// Example:
+//
+//	metadata, err := discovery.GetProviderMetadata(ctx, discovery.Google)
+//	if err != nil {
+//		return err
+//	}
+//
+//	client, err := token.NewClient(metadata, "YOUR_CLIENT_ID", token.WithClientSecretBasic("YOUR_CLIENT_SECRET"))
+//	if err != nil {
+//		return err
+//	}
+//
+//	resp, err := client.ExchangeAuthorizationCode(ctx, code, "https://example.com/callback", token.WithCodeVerifier(codeVerifier))
+type Client struct {
+	client        *http.Client
+	tokenEndpoint string
+	clientID      string
+	authMethod    string
+	clientSecret  string
+	assertionKey  *jws.SigningKeyOption
+	assertionAlg  string
+	assertionTTL  time.Duration
+}
+
+type ClientOption func(*Client)
+
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithClientSecretBasic sets client_secret_basic client authentication.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6749#section-2.3.1
+func WithClientSecretBasic(clientSecret string) ClientOption {
+	return func(c *Client) {
+		c.authMethod = AuthMethodClientSecretBasic
+		c.clientSecret = clientSecret
+	}
+}
+
+// WithClientSecretPost sets client_secret_post client authentication.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6749#section-2.3.1
+func WithClientSecretPost(clientSecret string) ClientOption {
+	return func(c *Client) {
+		c.authMethod = AuthMethodClientSecretPost
+		c.clientSecret = clientSecret
+	}
+}
+
+// WithPrivateKeyJWT sets private_key_jwt client authentication.
+// The client assertion is signed by keyOpt with alg for each request.
+//
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication
+//   - ref. https://www.rfc-editor.org/rfc/rfc7523#section-2.2
+func WithPrivateKeyJWT(keyOpt jws.SigningKeyOption, alg string) ClientOption {
+	return func(c *Client) {
+		c.authMethod = AuthMethodPrivateKeyJWT
+		c.assertionKey = &keyOpt
+		c.assertionAlg = alg
+	}
+}
+
+// WithClientAssertionTTL sets the lifetime of the client assertion of private_key_jwt.
+func WithClientAssertionTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.assertionTTL = ttl
+	}
+}
+
+// NewClient returns a new Client for the token endpoint of metadata.
+// If no client authentication option is specified, the client is a public client ("none"), which sends only client_id.
+// If metadata has "token_endpoint_auth_methods_supported", the client authentication method must be in it.
+func NewClient(metadata *discovery.ProviderMetadata, clientID string, opts ...ClientOption) (*Client, error) {
+	const defaultAssertionTTL = 1 * time.Minute
+
+	c := &Client{
+		client:       http.DefaultClient,
+		authMethod:   AuthMethodNone,
+		assertionTTL: defaultAssertionTTL,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if metadata.TokenEndpoint == "" {
+		return nil, ErrTokenEndpointIsEmpty
+	}
+	c.tokenEndpoint, c.clientID = metadata.TokenEndpoint, clientID
+
+	if c.authMethod != AuthMethodNone && len(metadata.TokenEndpointAuthMethodsSupported) > 0 &&
+		!slicez.Contains(metadata.TokenEndpointAuthMethodsSupported, c.authMethod) {
+		return nil, fmt.Errorf("method=%s supported=%v: %w", c.authMethod, metadata.TokenEndpointAuthMethodsSupported, ErrAuthMethodIsNotSupported)
+	}
+
+	return c, nil
+}
+
+type requestOption struct {
+	params url.Values
+}
+
+type RequestOption func(*requestOption)
+
+// WithCodeVerifier sets code_verifier of PKCE.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc7636#section-4.5
+func WithCodeVerifier(codeVerifier pkce.CodeVerifier) RequestOption {
+	return WithParameter("code_verifier", string(codeVerifier))
+}
+
+// WithScope sets scope.
+func WithScope(scope ...string) RequestOption {
+	return WithParameter("scope", strings.Join(scope, " "))
+}
+
+// WithParameter sets an additional parameter, e.g. "resource", "audience".
+func WithParameter(key, value string) RequestOption {
+	return func(o *requestOption) {
+		o.params.Set(key, value)
+	}
+}
+
+// ExchangeAuthorizationCode exchanges the authorization code for tokens.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6749#section-4.1.3
+func (c *Client) ExchangeAuthorizationCode(ctx context.Context, code, redirectURI string, opts ...RequestOption) (*Response, error) {
+	params := url.Values{
+		"grant_type":   {GrantTypeAuthorizationCode},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	}
+	return c.RequestToken(ctx, params, opts...)
+}
+
+// Refresh exchanges the refresh token for new tokens.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6749#section-6
+func (c *Client) Refresh(ctx context.Context, refreshToken string, opts ...RequestOption) (*Response, error) {
+	params := url.Values{
+		"grant_type":    {GrantTypeRefreshToken},
+		"refresh_token": {refreshToken},
+	}
+	return c.RequestToken(ctx, params, opts...)
+}
+
+// ClientCredentials requests tokens for the client itself.
+//
+//   - ref. https://www.rfc-editor.org/rfc/rfc6749#section-4.4
+func (c *Client) ClientCredentials(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	params := url.Values{
+		"grant_type": {GrantTypeClientCredentials},
+	}
+	return c.RequestToken(ctx, params, opts...)
+}
+
+// RequestToken sends the token request with params and the client authentication, and returns the token response.
+// It can be used for the grant types other than the above, e.g. "urn:ietf:params:oauth:grant-type:device_code".
+// If the token endpoint responds with an OAuth 2.0 error, the error is *ErrorResponse.
+// params is not modified.
+func (c *Client) RequestToken(ctx context.Context, params url.Values, opts ...RequestOption) (*Response, error) {
+	o := &requestOption{params: url.Values{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	params = cloneValues(params)
+	for k, v := range o.params {
+		params[k] = v
+	}
+
+	req, err := c.NewRequest(ctx, c.tokenEndpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return parseResponse(ctx, resp)
+}
+
+// NewRequest returns a new POST request to endpoint with params and the client authentication.
+// It is also used for the endpoints which require the same client authentication as the token endpoint,
+// e.g. pushed authorization request endpoint and device authorization endpoint.
+// params is not modified.
+func (c *Client) NewRequest(ctx context.Context, endpoint string, params url.Values) (*http.Request, error) {
+	params = cloneValues(params)
+	if err := c.authenticate(ctx, params); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if c.authMethod == AuthMethodClientSecretBasic {
+		// NOTE: client_id and client_secret are encoded using application/x-www-form-urlencoded before Basic authentication.
+		//   - ref. https://www.rfc-editor.org/rfc/rfc6749#section-2.3.1
+		req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+	}
+
+	return req, nil
+}
+
+// Do sends req by the HTTP client of c, e.g. the request returned by NewRequest.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("(*http.Client).Do: %w", err)
+	}
+	return resp, nil
+}
+
+func cloneValues(values url.Values) url.Values {
+	cloned := make(url.Values, len(values))
+	for k, v := range values {
+		cloned[k] = append([]string(nil), v...)
+	}
+	return cloned
+}
+
+func (c *Client) authenticate(ctx context.Context, params url.Values) error {
+	switch c.authMethod {
+	case AuthMethodClientSecretBasic:
+		// NOTE: set by Authorization header.
+	case AuthMethodClientSecretPost:
+		params.Set("client_id", c.clientID)
+		params.Set("client_secret", c.clientSecret)
+	case AuthMethodPrivateKeyJWT:
+		assertion, err := c.clientAssertion(ctx)
+		if err != nil {
+			return err
+		}
+		params.Set("client_id", c.clientID)
+		params.Set("client_assertion_type", ClientAssertionTypeJWTBearer)
+		params.Set("client_assertion", assertion)
+	default:
+		params.Set("client_id", c.clientID)
+	}
+
+	return nil
+}
+
+func (c *Client) clientAssertion(ctx context.Context) (string, error) {
+	if c.assertionKey == nil {
+		return "", ErrClientAssertionKeyIsNotSet
+	}
+
+	const jtiLength = 32
+	jti, err := randz.ReadString(randz.NewReader(), jtiLength)
+	if err != nil {
+		return "", fmt.Errorf("randz.ReadString: %w", err)
+	}
+
+	now := timez.Now(ctx)
+	assertion, err := jwt.New(
+		*c.assertionKey,
+		jose.NewHeader(c.assertionAlg, jose.WithType("JWT")),
+		jwt.NewClaimsSet(
+			jwt.WithIssuer(c.clientID),
+			jwt.WithSubject(c.clientID),
+			jwt.WithAudience(c.tokenEndpoint),
+			jwt.WithJWTID(jti),
+			jwt.WithIssuedAt(now),
+			jwt.WithExpirationTime(now.Add(c.assertionTTL)),
+		),
+	)
+	if err != nil {
+		return "", fmt.Errorf("jwt.New: %w", err)
+	}
+
+	return assertion, nil
+}
+
+func parseResponse(ctx context.Context, resp *http.Response) (*Response, error) {
+	const limit = 1024 * 1024
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if len(body) > limit {
+		return nil, fmt.Errorf("limit=%d: %w", limit, ErrResponseIsTooLarge)
+	}
+
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		e := &ErrorResponse{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(body, e); err != nil || e.ErrorCode == "" {
+			const cutOffSize = 100
+			return nil, fmt.Errorf("code=%d body=%q: %w", resp.StatusCode, string(slicez.CutOff(body, cutOffSize)), ErrResponseIsNotOK)
+		}
+		return nil, e
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return nil, fmt.Errorf("content-type=%s: %w", resp.Header.Get("Content-Type"), ErrContentTypeIsNotJSON)
+	}
+
+	r := &Response{Raw: body}
+	if err := json.Unmarshal(body, r); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if r.AccessToken == "" {
+		return nil, ErrAccessTokenIsEmpty
+	}
+	if r.ExpiresIn > 0 {
+		r.Expiry = timez.Now(ctx).Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+
+	return r, nil
+}
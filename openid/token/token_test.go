@@ -0,0 +1,225 @@
+package token_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/pkce"
+	"github.com/kunitsucom/util.go/openid/token"
+	testingz "github.com/kunitsucom/util.go/testing"
+)
+
+const (
+	testClientID     = "client_id@example.com"
+	testClientSecret = "client secret"
+)
+
+func newTestTokenEndpoint(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *discovery.ProviderMetadata {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(s.Close)
+
+	return &discovery.ProviderMetadata{
+		Issuer:        s.URL,
+		TokenEndpoint: s.URL + "/token",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	must.Must(json.NewEncoder(w).Encode(v))
+}
+
+func TestClient_ExchangeAuthorizationCode(t *testing.T) {
+	t.Parallel()
+
+	codeVerifier := must.One(pkce.CreateCodeVerifier(43))
+
+	t.Run("success(client_secret_basic)", func(t *testing.T) {
+		t.Parallel()
+		metadata := newTestTokenEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+			id, secret, ok := r.BasicAuth()
+			if !ok || id != "client_id%40example.com" || secret != "client+secret" {
+				t.Errorf("❌: BasicAuth: id=%s secret=%s ok=%t", id, secret, ok)
+			}
+			if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("code") != "test_code" ||
+				r.PostFormValue("redirect_uri") != "https://rp.example.com/callback" || r.PostFormValue("code_verifier") != string(codeVerifier) {
+				t.Errorf("❌: PostForm: %v", r.PostForm)
+			}
+			if r.PostFormValue("client_secret") != "" {
+				t.Errorf("❌: PostForm: client_secret is present")
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"access_token": "test_access_token", "token_type": "Bearer", "expires_in": 3600, "id_token": "test_id_token", "extra": "value"})
+		})
+		metadata.TokenEndpointAuthMethodsSupported = []string{token.AuthMethodClientSecretBasic}
+
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretBasic(testClientSecret)))
+		resp, err := client.ExchangeAuthorizationCode(context.Background(), "test_code", "https://rp.example.com/callback", token.WithCodeVerifier(codeVerifier))
+		if err != nil {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err != nil: %v", err)
+		}
+		if resp.AccessToken != "test_access_token" || resp.IDToken != "test_id_token" || resp.Expiry.IsZero() {
+			t.Errorf("❌: (*token.Client).ExchangeAuthorizationCode: resp=%+v", resp)
+		}
+		var extra struct {
+			Extra string `json:"extra"`
+		}
+		if err := json.Unmarshal(resp.Raw, &extra); err != nil || extra.Extra != "value" {
+			t.Errorf("❌: resp.Raw: extra=%s err=%v", extra.Extra, err)
+		}
+	})
+
+	t.Run("failure(invalid_grant)", func(t *testing.T) {
+		t.Parallel()
+		metadata := newTestTokenEndpoint(t, func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant", "error_description": "code is expired"})
+		})
+		client := must.One(token.NewClient(metadata, testClientID))
+		_, err := client.ExchangeAuthorizationCode(context.Background(), "test_code", "https://rp.example.com/callback")
+		var e *token.ErrorResponse
+		if !errors.As(err, &e) || e.ErrorCode != "invalid_grant" || e.StatusCode != http.StatusBadRequest {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err != *token.ErrorResponse: %v", err)
+		}
+	})
+
+	t.Run("failure(not_json)", func(t *testing.T) {
+		t.Parallel()
+		metadata := newTestTokenEndpoint(t, func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		})
+		client := must.One(token.NewClient(metadata, testClientID))
+		if _, err := client.ExchangeAuthorizationCode(context.Background(), "test_code", "https://rp.example.com/callback"); !errors.Is(err, token.ErrResponseIsNotOK) {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err != token.ErrResponseIsNotOK: %v", err)
+		}
+	})
+
+	t.Run("failure(token.ErrResponseIsTooLarge)", func(t *testing.T) {
+		t.Parallel()
+		metadata := newTestTokenEndpoint(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write(make([]byte, 1024*1024+1))
+		})
+		client := must.One(token.NewClient(metadata, testClientID))
+		if _, err := client.ExchangeAuthorizationCode(context.Background(), "test_code", "https://rp.example.com/callback"); !errors.Is(err, token.ErrResponseIsTooLarge) {
+			t.Fatalf("❌: (*token.Client).ExchangeAuthorizationCode: err != token.ErrResponseIsTooLarge: %v", err)
+		}
+	})
+}
+
+func TestClient_Refresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(client_secret_post)", func(t *testing.T) {
+		t.Parallel()
+		metadata := newTestTokenEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+			if _, _, ok := r.BasicAuth(); ok {
+				t.Errorf("❌: BasicAuth: ok")
+			}
+			if r.PostFormValue("grant_type") != "refresh_token" || r.PostFormValue("refresh_token") != "test_refresh_token" ||
+				r.PostFormValue("client_id") != testClientID || r.PostFormValue("client_secret") != testClientSecret || r.PostFormValue("scope") != "openid email" {
+				t.Errorf("❌: PostForm: %v", r.PostForm)
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"access_token": "new_access_token", "token_type": "Bearer", "refresh_token": "new_refresh_token"})
+		})
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretPost(testClientSecret)))
+		resp, err := client.Refresh(context.Background(), "test_refresh_token", token.WithScope("openid", "email"))
+		if err != nil {
+			t.Fatalf("❌: (*token.Client).Refresh: err != nil: %v", err)
+		}
+		if resp.AccessToken != "new_access_token" || resp.RefreshToken != "new_refresh_token" || !resp.Expiry.IsZero() {
+			t.Errorf("❌: (*token.Client).Refresh: resp=%+v", resp)
+		}
+	})
+}
+
+func TestClient_RequestToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(params_is_not_modified)", func(t *testing.T) {
+		t.Parallel()
+		metadata := newTestTokenEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.PostFormValue("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" || r.PostFormValue("device_code") != "test_device_code" ||
+				r.PostFormValue("client_id") != testClientID || r.PostFormValue("client_secret") != testClientSecret || r.PostFormValue("scope") != "openid" {
+				t.Errorf("❌: PostForm: %v", r.PostForm)
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"access_token": "test_access_token", "token_type": "Bearer"})
+		})
+		client := must.One(token.NewClient(metadata, testClientID, token.WithClientSecretPost(testClientSecret)))
+		params := url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {"test_device_code"},
+		}
+		if _, err := client.RequestToken(context.Background(), params, token.WithScope("openid")); err != nil {
+			t.Fatalf("❌: (*token.Client).RequestToken: err != nil: %v", err)
+		}
+		if len(params) != 2 {
+			t.Errorf("❌: (*token.Client).RequestToken: params is modified: %v", params)
+		}
+	})
+}
+
+func TestClient_ClientCredentials(t *testing.T) {
+	t.Parallel()
+
+	privateKey := must.One(x509z.ParseECDSAPrivateKeyPEM([]byte(testingz.TestECDSAPrivateKey256BitPEM)))
+	publicKey := must.One(x509z.ParseECDSAPublicKeyPEM([]byte(testingz.TestECDSAPublicKey256BitPEM)))
+
+	t.Run("success(private_key_jwt)", func(t *testing.T) {
+		t.Parallel()
+		var metadata *discovery.ProviderMetadata
+		metadata = newTestTokenEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.PostFormValue("grant_type") != "client_credentials" || r.PostFormValue("client_assertion_type") != token.ClientAssertionTypeJWTBearer {
+				t.Errorf("❌: PostForm: %v", r.PostForm)
+			}
+			_, cs, err := jwt.Verify(jws.UseECDSAKey(publicKey), r.PostFormValue("client_assertion"),
+				jwt.VerifyAlgorithm(jwa.ES256),
+				jwt.VerifyIssuer(testClientID),
+				jwt.VerifyAudience(metadata.TokenEndpoint),
+				jwt.VerifyRequiredClaims("sub", "jti", "exp"),
+			)
+			if err != nil {
+				t.Errorf("❌: jwt.Verify: err != nil: %v", err)
+			} else if cs.Subject != testClientID {
+				t.Errorf("❌: jwt.Verify: sub=%s", cs.Subject)
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"access_token": "test_access_token", "token_type": "Bearer"})
+		})
+		metadata.TokenEndpointAuthMethodsSupported = []string{token.AuthMethodClientSecretBasic, token.AuthMethodPrivateKeyJWT}
+
+		client := must.One(token.NewClient(metadata, testClientID, token.WithPrivateKeyJWT(jws.WithECDSAKey(privateKey), jwa.ES256)))
+		if _, err := client.ClientCredentials(context.Background()); err != nil {
+			t.Fatalf("❌: (*token.Client).ClientCredentials: err != nil: %v", err)
+		}
+	})
+
+	t.Run("failure(token.ErrAuthMethodIsNotSupported)", func(t *testing.T) {
+		t.Parallel()
+		metadata := &discovery.ProviderMetadata{
+			TokenEndpoint:                     "https://op.example.com/token",
+			TokenEndpointAuthMethodsSupported: []string{token.AuthMethodClientSecretBasic},
+		}
+		if _, err := token.NewClient(metadata, testClientID, token.WithClientSecretPost(testClientSecret)); !errors.Is(err, token.ErrAuthMethodIsNotSupported) {
+			t.Fatalf("❌: token.NewClient: err != token.ErrAuthMethodIsNotSupported: %v", err)
+		}
+	})
+
+	t.Run("failure(token.ErrTokenEndpointIsEmpty)", func(t *testing.T) {
+		t.Parallel()
+		if _, err := token.NewClient(&discovery.ProviderMetadata{}, testClientID); !errors.Is(err, token.ErrTokenEndpointIsEmpty) {
+			t.Fatalf("❌: token.NewClient: err != token.ErrTokenEndpointIsEmpty: %v", err)
+		}
+	})
+}
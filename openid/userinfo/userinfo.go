@@ -0,0 +1,319 @@
+package userinfo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwe"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	slicez "github.com/kunitsucom/util.go/slices"
+)
+
+// - ref. UserInfo Endpoint https://openid.net/specs/openid-connect-core-1_0.html#UserInfo
+
+var (
+	ErrUserInfoEndpointIsEmpty   = errors.New("userinfo: userinfo endpoint is empty")
+	ErrResponseIsNotOK           = errors.New("userinfo: response is not OK")
+	ErrContentTypeIsNotSupported = errors.New("userinfo: content type is not supported")
+	ErrSubjectIsNotMatch         = errors.New("userinfo: subject is not match")
+	ErrIssuerIsNotMatch          = errors.New("userinfo: issuer is not match")
+	ErrAudienceIsNotMatch        = errors.New("userinfo: audience is not match")
+	ErrDecryptionKeyIsNotSet     = errors.New("userinfo: decryption key is not set")
+	ErrAlgorithmNoneIsNotAllowed = errors.New("userinfo: algorithm none is not allowed")
+)
+
+// Address is the "address" claim.
+//
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#AddressClaim
+//
+//nolint:tagliatelle
+type Address struct {
+	Formatted     string `json:"formatted,omitempty"`
+	StreetAddress string `json:"street_address,omitempty"`
+	Locality      string `json:"locality,omitempty"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code,omitempty"`
+	Country       string `json:"country,omitempty"`
+}
+
+// Claims is the standard claims of the UserInfo Response.
+// The claims which are not standard are stored in Extra.
+//
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#StandardClaims
+//
+//nolint:tagliatelle
+type Claims struct {
+	Subject             string   `json:"sub"`
+	Name                string   `json:"name,omitempty"`
+	GivenName           string   `json:"given_name,omitempty"`
+	FamilyName          string   `json:"family_name,omitempty"`
+	MiddleName          string   `json:"middle_name,omitempty"`
+	Nickname            string   `json:"nickname,omitempty"`
+	PreferredUsername   string   `json:"preferred_username,omitempty"`
+	Profile             string   `json:"profile,omitempty"`
+	Picture             string   `json:"picture,omitempty"`
+	Website             string   `json:"website,omitempty"`
+	Email               string   `json:"email,omitempty"`
+	EmailVerified       bool     `json:"email_verified,omitempty"`
+	Gender              string   `json:"gender,omitempty"`
+	Birthdate           string   `json:"birthdate,omitempty"`
+	Zoneinfo            string   `json:"zoneinfo,omitempty"`
+	Locale              string   `json:"locale,omitempty"`
+	PhoneNumber         string   `json:"phone_number,omitempty"`
+	PhoneNumberVerified bool     `json:"phone_number_verified,omitempty"`
+	Address             *Address `json:"address,omitempty"`
+	UpdatedAt           int64    `json:"updated_at,omitempty"`
+
+	// Extra is the claims which are not standard, e.g. "groups", and "iss" and "aud" of the signed response.
+	Extra map[string]any `json:"-"`
+}
+
+//nolint:gochecknoglobals
+var standardClaimNames = map[string]bool{
+	"sub": true, "name": true, "given_name": true, "family_name": true, "middle_name": true, "nickname": true,
+	"preferred_username": true, "profile": true, "picture": true, "website": true, "email": true, "email_verified": true,
+	"gender": true, "birthdate": true, "zoneinfo": true, "locale": true, "phone_number": true, "phone_number_verified": true,
+	"address": true, "updated_at": true,
+}
+
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	type alias Claims
+	if err := json.Unmarshal(data, (*alias)(c)); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	for name := range standardClaimNames {
+		delete(all, name)
+	}
+	c.Extra = nil
+	if len(all) > 0 {
+		c.Extra = all
+	}
+
+	return nil
+}
+
+func (c *Claims) MarshalJSON() ([]byte, error) {
+	type alias Claims
+	b, err := json.Marshal((*alias)(c))
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	if len(c.Extra) == 0 {
+		return b, nil
+	}
+
+	merged := make(map[string]json.RawMessage)
+	for name, value := range c.Extra {
+		if standardClaimNames[name] {
+			continue
+		}
+		v, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %w", err)
+		}
+		merged[name] = v
+	}
+	if err := json.Unmarshal(b, &merged); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return json.Marshal(merged) //nolint:wrapcheck
+}
+
+// Client is the client of the UserInfo Endpoint.
+//
+// Example:
+//
+//	client, err := userinfo.NewClient(metadata, "YOUR_CLIENT_ID")
+//	if err != nil {
+//		return err
+//	}
+//
+//	claims, err := client.GetUserInfo(ctx, tokenResponse.AccessToken, idTokenClaims.Subject)
+type Client struct {
+	client           *http.Client
+	jwkClient        *jwk.Client
+	decryptionKeyOpt *jwe.DecryptionKeyOption
+	metadata         *discovery.ProviderMetadata
+	clientID         string
+}
+
+type ClientOption func(*Client)
+
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithJWKClient sets the client to fetch the JWK Set to verify the signed response, instead of jwk.Default.
+func WithJWKClient(client *jwk.Client) ClientOption {
+	return func(c *Client) {
+		c.jwkClient = client
+	}
+}
+
+// WithDecryptionKey sets the key to decrypt the encrypted response.
+func WithDecryptionKey(keyOpt jwe.DecryptionKeyOption) ClientOption {
+	return func(c *Client) {
+		c.decryptionKeyOpt = &keyOpt
+	}
+}
+
+func NewClient(metadata *discovery.ProviderMetadata, clientID string, opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		client:    http.DefaultClient,
+		jwkClient: jwk.Default,
+		metadata:  metadata,
+		clientID:  clientID,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if metadata.UserInfoEndpoint == "" {
+		return nil, ErrUserInfoEndpointIsEmpty
+	}
+
+	return c, nil
+}
+
+// GetUserInfo requests the UserInfo Endpoint with accessToken as a bearer token, and returns the claims.
+// The "sub" of the response must exactly match subject, which is the "sub" of the ID Token.
+//
+//   - ref. https://openid.net/specs/openid-connect-core-1_0.html#UserInfoResponse
+//
+// If the response is "application/jwt", it is decrypted by the key of WithDecryptionKey if encrypted,
+// and verified by the JWK Set of "jwks_uri" if signed.
+func (c *Client) GetUserInfo(ctx context.Context, accessToken, subject string) (*Claims, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.metadata.UserInfoEndpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json, application/jwt")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("(*http.Client).Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		const cutOffSize = 100
+		return nil, fmt.Errorf("code=%d www-authenticate=%q body=%q: %w", resp.StatusCode, resp.Header.Get("WWW-Authenticate"), string(slicez.CutOff(body, cutOffSize)), ErrResponseIsNotOK)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("content-type=%s: %w", resp.Header.Get("Content-Type"), ErrContentTypeIsNotSupported)
+	}
+
+	claims := new(Claims)
+	switch mediaType {
+	case "application/json":
+		if err := json.Unmarshal(body, claims); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+	case "application/jwt":
+		if err := c.decodeJWT(ctx, strings.TrimSpace(string(body)), claims); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("content-type=%s: %w", mediaType, ErrContentTypeIsNotSupported)
+	}
+
+	if claims.Subject == "" || claims.Subject != subject {
+		return nil, fmt.Errorf("want=%s got=%s: %w", subject, claims.Subject, ErrSubjectIsNotMatch)
+	}
+
+	return claims, nil
+}
+
+func (c *Client) decodeJWT(ctx context.Context, token string, claims *Claims) error {
+	const jweParts = 5
+	if len(strings.Split(token, ".")) == jweParts {
+		if c.decryptionKeyOpt == nil {
+			return ErrDecryptionKeyIsNotSet
+		}
+		_, plaintext, err := jwe.Decrypt(*c.decryptionKeyOpt, token)
+		if err != nil {
+			return fmt.Errorf("jwe.Decrypt: %w", err)
+		}
+		// NOTE: encrypted only, not signed.
+		if json.Valid(plaintext) {
+			if err := json.Unmarshal(plaintext, claims); err != nil {
+				return fmt.Errorf("json.Unmarshal: %w", err)
+			}
+			return nil
+		}
+		token = string(plaintext)
+	}
+
+	return c.verifyJWT(ctx, token, claims)
+}
+
+func (c *Client) verifyJWT(ctx context.Context, token string, claims *Claims) error {
+	algorithms := c.metadata.UserinfoSigningAlgValuesSupported
+	if len(algorithms) == 0 {
+		algorithms = []string{jwa.RS256}
+	}
+	algorithms = slicez.Exclude(algorithms, []string{jwa.None})
+	if len(algorithms) == 0 {
+		return ErrAlgorithmNoneIsNotAllowed
+	}
+
+	_, cs, err := jwt.Verify(
+		jws.UseJWKSetURLWithClient(ctx, c.jwkClient, c.metadata.JwksURI),
+		token,
+		jwt.VerifyContext(ctx),
+		jwt.VerifyAlgorithm(algorithms...),
+	)
+	if err != nil {
+		return fmt.Errorf("jwt.Verify: %w", err)
+	}
+
+	// NOTE: If signed, the UserInfo Response SHOULD contain the Claims iss (issuer) and aud (audience) as members.
+	if cs.Issuer != "" && cs.Issuer != c.metadata.Issuer {
+		return fmt.Errorf("want=%s got=%s: %w", c.metadata.Issuer, cs.Issuer, ErrIssuerIsNotMatch)
+	}
+	if len(cs.Audience) > 0 && !slicez.Contains(cs.Audience, c.clientID) {
+		return fmt.Errorf("want=%s got=%v: %w", c.clientID, cs.Audience, ErrAudienceIsNotMatch)
+	}
+
+	_, payloadEncoded, _, err := jws.Parse(token)
+	if err != nil {
+		return fmt.Errorf("jws.Parse: %w", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadEncoded)
+	if err != nil {
+		return fmt.Errorf("base64.RawURLEncoding.DecodeString: %w", err)
+	}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return nil
+}
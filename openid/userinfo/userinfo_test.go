@@ -0,0 +1,189 @@
+package userinfo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	x509z "github.com/kunitsucom/util.go/crypto/x509"
+	"github.com/kunitsucom/util.go/jose"
+	"github.com/kunitsucom/util.go/jose/jwa"
+	"github.com/kunitsucom/util.go/jose/jwe"
+	"github.com/kunitsucom/util.go/jose/jwk"
+	"github.com/kunitsucom/util.go/jose/jws"
+	"github.com/kunitsucom/util.go/jose/jwt"
+	"github.com/kunitsucom/util.go/must"
+	"github.com/kunitsucom/util.go/openid/discovery"
+	"github.com/kunitsucom/util.go/openid/userinfo"
+	testingz "github.com/kunitsucom/util.go/testing"
+)
+
+const (
+	testClientID    = "test_client_id"
+	testAccessToken = "test_access_token"
+)
+
+type testUserInfoEndpoint struct {
+	metadata    *discovery.ProviderMetadata
+	keyManager  *jwk.KeyManager
+	contentType string
+	body        string
+}
+
+func newTestUserInfoEndpoint(t *testing.T) *testUserInfoEndpoint {
+	t.Helper()
+
+	e := &testUserInfoEndpoint{keyManager: must.One(jwk.NewKeyManager("EC", jwa.ES256))}
+	mux := http.NewServeMux()
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	mux.Handle("/certs", e.keyManager)
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testAccessToken {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", e.contentType)
+		_, _ = w.Write([]byte(e.body))
+	})
+
+	e.metadata = &discovery.ProviderMetadata{
+		Issuer:                            s.URL,
+		JwksURI:                           s.URL + "/certs",
+		UserInfoEndpoint:                  s.URL + "/userinfo",
+		UserinfoSigningAlgValuesSupported: []string{jwa.ES256},
+	}
+	return e
+}
+
+func (e *testUserInfoEndpoint) client(t *testing.T, opts ...userinfo.ClientOption) *userinfo.Client {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	opts = append([]userinfo.ClientOption{userinfo.WithJWKClient(jwk.NewClient(ctx))}, opts...)
+	return must.One(userinfo.NewClient(e.metadata, testClientID, opts...))
+}
+
+func (e *testUserInfoEndpoint) sign(t *testing.T, opts ...jwt.ClaimsSetOption) string {
+	t.Helper()
+
+	opts = append([]jwt.ClaimsSetOption{
+		jwt.WithSubject("userID"),
+		jwt.WithPrivateClaim("email", "user@example.com"),
+		jwt.WithPrivateClaim("groups", []string{"admin"}),
+	}, opts...)
+	claimsSet := jwt.NewClaimsSet(opts...)
+	claimsSet.IssuedAt = 0
+	return must.One(jwt.New(jws.WithSigningKeyProvider(e.keyManager), jose.NewHeader(jwa.ES256), claimsSet))
+}
+
+func TestClient_GetUserInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success(application/json)", func(t *testing.T) {
+		t.Parallel()
+		e := newTestUserInfoEndpoint(t)
+		e.contentType, e.body = "application/json; charset=utf-8", `{"sub":"userID","email":"user@example.com","email_verified":true,"address":{"country":"JP"},"groups":["admin"]}`
+
+		claims, err := e.client(t).GetUserInfo(context.Background(), testAccessToken, "userID")
+		if err != nil {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != nil: %v", err)
+		}
+		if claims.Email != "user@example.com" || !claims.EmailVerified || claims.Address.Country != "JP" {
+			t.Errorf("❌: (*userinfo.Client).GetUserInfo: claims=%+v", claims)
+		}
+		if len(claims.Extra) != 1 || claims.Extra["groups"] == nil {
+			t.Errorf("❌: (*userinfo.Client).GetUserInfo: extra=%v", claims.Extra)
+		}
+
+		b := must.One(json.Marshal(claims))
+		const expect = `{"address":{"country":"JP"},"email":"user@example.com","email_verified":true,"groups":["admin"],"sub":"userID"}`
+		if string(b) != expect {
+			t.Errorf("❌: json.Marshal: expect(%s) != actual(%s)", expect, b)
+		}
+	})
+
+	t.Run("success(application/jwt,signed)", func(t *testing.T) {
+		t.Parallel()
+		e := newTestUserInfoEndpoint(t)
+		e.contentType, e.body = "application/jwt", e.sign(t, jwt.WithIssuer(e.metadata.Issuer), jwt.WithAudience(testClientID))
+
+		claims, err := e.client(t).GetUserInfo(context.Background(), testAccessToken, "userID")
+		if err != nil {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != nil: %v", err)
+		}
+		if claims.Email != "user@example.com" || claims.Extra["iss"] != e.metadata.Issuer {
+			t.Errorf("❌: (*userinfo.Client).GetUserInfo: claims=%+v", claims)
+		}
+	})
+
+	t.Run("success(application/jwt,signed,encrypted)", func(t *testing.T) {
+		t.Parallel()
+		e := newTestUserInfoEndpoint(t)
+		privateKey := must.One(x509z.ParseRSAPrivateKeyPEM([]byte(testingz.TestRSAPrivateKey2048BitPEM)))
+		e.contentType = "application/jwt"
+		e.body = must.One(jwe.Encrypt(jwe.WithRSAKey(&privateKey.PublicKey), jose.NewHeader(jwa.RSAOAEP256, jose.WithEncryptionAlgorithm(jwa.A256GCM), jose.WithContentType("JWT")), []byte(e.sign(t))))
+
+		if _, err := e.client(t).GetUserInfo(context.Background(), testAccessToken, "userID"); !errors.Is(err, userinfo.ErrDecryptionKeyIsNotSet) {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != userinfo.ErrDecryptionKeyIsNotSet: %v", err)
+		}
+		claims, err := e.client(t, userinfo.WithDecryptionKey(jwe.UseRSAKey(privateKey))).GetUserInfo(context.Background(), testAccessToken, "userID")
+		if err != nil {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != nil: %v", err)
+		}
+		if claims.Email != "user@example.com" {
+			t.Errorf("❌: (*userinfo.Client).GetUserInfo: claims=%+v", claims)
+		}
+	})
+
+	t.Run("failure(sub)", func(t *testing.T) {
+		t.Parallel()
+		e := newTestUserInfoEndpoint(t)
+		e.contentType, e.body = "application/json", `{"sub":"otherUserID"}`
+		if _, err := e.client(t).GetUserInfo(context.Background(), testAccessToken, "userID"); !errors.Is(err, userinfo.ErrSubjectIsNotMatch) {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != userinfo.ErrSubjectIsNotMatch: %v", err)
+		}
+	})
+
+	t.Run("failure(aud)", func(t *testing.T) {
+		t.Parallel()
+		e := newTestUserInfoEndpoint(t)
+		e.contentType, e.body = "application/jwt", e.sign(t, jwt.WithAudience("other_client_id"))
+		if _, err := e.client(t).GetUserInfo(context.Background(), testAccessToken, "userID"); !errors.Is(err, userinfo.ErrAudienceIsNotMatch) {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != userinfo.ErrAudienceIsNotMatch: %v", err)
+		}
+	})
+
+	t.Run("failure(iss)", func(t *testing.T) {
+		t.Parallel()
+		e := newTestUserInfoEndpoint(t)
+		e.contentType, e.body = "application/jwt", e.sign(t, jwt.WithIssuer("https://evil.example.com"))
+		if _, err := e.client(t).GetUserInfo(context.Background(), testAccessToken, "userID"); !errors.Is(err, userinfo.ErrIssuerIsNotMatch) {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != userinfo.ErrIssuerIsNotMatch: %v", err)
+		}
+	})
+
+	t.Run("failure(unauthorized)", func(t *testing.T) {
+		t.Parallel()
+		e := newTestUserInfoEndpoint(t)
+		if _, err := e.client(t).GetUserInfo(context.Background(), "invalid_access_token", "userID"); !errors.Is(err, userinfo.ErrResponseIsNotOK) {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != userinfo.ErrResponseIsNotOK: %v", err)
+		}
+	})
+
+	t.Run("failure(content-type)", func(t *testing.T) {
+		t.Parallel()
+		e := newTestUserInfoEndpoint(t)
+		e.contentType, e.body = "text/plain", `sub=userID`
+		if _, err := e.client(t).GetUserInfo(context.Background(), testAccessToken, "userID"); !errors.Is(err, userinfo.ErrContentTypeIsNotSupported) {
+			t.Fatalf("❌: (*userinfo.Client).GetUserInfo: err != userinfo.ErrContentTypeIsNotSupported: %v", err)
+		}
+	})
+}